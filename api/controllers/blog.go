@@ -0,0 +1,262 @@
+package controllers
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"boilerplate-api/api/validators"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BlogController -> struct
+type BlogController struct {
+	logger      infrastructure.Logger
+	blogService services.BlogService
+	validator   validators.BlogValidator
+}
+
+// NewBlogController -> constructor
+func NewBlogController(
+	logger infrastructure.Logger,
+	blogService services.BlogService,
+	validator validators.BlogValidator,
+) BlogController {
+	return BlogController{
+		logger:      logger,
+		blogService: blogService,
+		validator:   validator,
+	}
+}
+
+// bindBlog -> binds and validates the blog from the request body
+func (cc BlogController) bindBlog(c *gin.Context) (models.Blog, error) {
+	blog := models.Blog{}
+	if err := c.ShouldBindJSON(&blog); err != nil {
+		return blog, errors.BadRequest.Wrap(err, "Failed to bind blog")
+	}
+	if validationErr := cc.validator.Validate.Struct(blog); validationErr != nil {
+		err := errors.BadRequest.Wrap(validationErr, "Validation error")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return blog, errors.AddErrorContextBlock(err, cc.validator.GenerateValidationResponse(validationErr))
+	}
+	// relations and authorship are never taken from the body
+	blog.CreatedBy = nil
+	blog.UpdatedBy = nil
+	blog.Categories = nil
+	return blog, nil
+}
+
+// listBlogs -> paginated blogs matching the filter
+func (cc BlogController) listBlogs(c *gin.Context, filter repository.BlogFilter) {
+	pagination := utils.BuildPagination(c)
+	fieldset := utils.BuildFieldset(c)
+	filter.CategoryID, _ = strconv.ParseInt(c.Query("category"), 10, 64)
+
+	blogs, count, err := cc.blogService.WithFieldset(fieldset).GetAllBlogs(pagination, filter)
+	if err != nil {
+		cc.logger.Zap.Error("Error finding Blog records", err.Error())
+		// invalid sort, filter or fields query
+		if errors.GetErrorType(err) == errors.BadRequest {
+			responses.HandleError(c, err)
+			return
+		}
+		err := errors.InternalError.Wrap(err, "Failed To Find Blog")
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSONCount(c, http.StatusOK, responses.Sparse(blogs, fieldset), count)
+}
+
+// GetPublishedBlogs -> Get all published blogs
+func (cc BlogController) GetPublishedBlogs(c *gin.Context) {
+	cc.listBlogs(c, repository.BlogFilter{PublishedOnly: true})
+}
+
+// GetDraftBlogs -> Get drafts of the user, privileged roles see every draft
+func (cc BlogController) GetDraftBlogs(c *gin.Context) {
+	filter := repository.BlogFilter{DraftsOnly: true}
+	if !utils.StringInList(c.GetString(constants.Role), constants.RolePrivileged) {
+		filter.AuthorID = c.GetInt64(constants.UserID)
+	}
+	cc.listBlogs(c, filter)
+}
+
+// getOneBlog -> blog matching the filter
+func (cc BlogController) getOneBlog(c *gin.Context, filter repository.BlogFilter) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	fieldset := utils.BuildFieldset(c)
+	blog, err := cc.blogService.WithFieldset(fieldset).GetOneBlog(ID, filter)
+	if err != nil {
+		cc.logger.Zap.Error("Error [GetOneBlog] [db GetOneBlog]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, responses.Sparse(blog, fieldset))
+}
+
+// GetOnePublishedBlog -> Get one published blog
+func (cc BlogController) GetOnePublishedBlog(c *gin.Context) {
+	cc.getOneBlog(c, repository.BlogFilter{PublishedOnly: true})
+}
+
+// GetPublishedBlogBySlug -> Get one published blog by slug, old slugs redirect to the current one
+func (cc BlogController) GetPublishedBlogBySlug(c *gin.Context) {
+	fieldset := utils.BuildFieldset(c)
+	blog, currentSlug, err := cc.blogService.WithFieldset(fieldset).GetPublishedBySlug(c.Param("slug"))
+	if err != nil {
+		cc.logger.Zap.Error("Error [GetPublishedBlogBySlug] [db GetPublishedBySlug]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	if currentSlug != "" {
+		location := strings.TrimSuffix(c.Request.URL.Path, c.Param("slug")) + currentSlug
+		if c.Request.URL.RawQuery != "" {
+			location += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, location)
+		return
+	}
+	responses.JSON(c, http.StatusOK, responses.Sparse(blog, fieldset))
+}
+
+// GetOneDraftBlog -> Get one draft of the user, privileged roles see every draft
+func (cc BlogController) GetOneDraftBlog(c *gin.Context) {
+	filter := repository.BlogFilter{DraftsOnly: true}
+	if !utils.StringInList(c.GetString(constants.Role), constants.RolePrivileged) {
+		filter.AuthorID = c.GetInt64(constants.UserID)
+	}
+	cc.getOneBlog(c, filter)
+}
+
+// CreateBlog -> Create Blog written by the authenticated user
+func (cc BlogController) CreateBlog(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	blog, err := cc.bindBlog(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateBlog] (bindBlog) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
+
+	if err := cc.blogService.WithTrx(trx).CreateBlog(&blog, c.GetInt64(constants.UserID)); err != nil {
+		cc.logger.Zap.Error("Error [CreateBlog] [db CreateBlog]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, blog)
+}
+
+// UpdateOneBlog -> Update One Blog By Id
+func (cc BlogController) UpdateOneBlog(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	blog, err := cc.bindBlog(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [UpdateBlog] (bindBlog) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
+	blog.ID = ID
+
+	if err := cc.blogService.WithTrx(trx).UpdateOneBlog(blog, c.GetInt64(constants.UserID), c.GetString(constants.Role)); err != nil {
+		cc.logger.Zap.Error("Error [UpdateBlog] [db UpdateBlog]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.SuccessJSON(c, http.StatusOK, "Blog Updated Sucessfully")
+}
+
+// PublishBlog -> Publish a draft
+func (cc BlogController) PublishBlog(c *gin.Context) {
+	cc.setPublished(c, true)
+}
+
+// UnpublishBlog -> Move a published blog back to draft
+func (cc BlogController) UnpublishBlog(c *gin.Context) {
+	cc.setPublished(c, false)
+}
+
+func (cc BlogController) setPublished(c *gin.Context, published bool) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.blogService.WithTrx(trx).SetPublished(ID, published, c.GetInt64(constants.UserID), c.GetString(constants.Role)); err != nil {
+		cc.logger.Zap.Error("Error [SetPublished] [db SetPublished]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	if published {
+		responses.SuccessJSON(c, http.StatusOK, "Blog Published Sucessfully")
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Blog Moved To Draft")
+}
+
+// UploadBlogImage -> Upload the blog image, an open graph sized copy is stored along with it
+func (cc BlogController) UploadBlogImage(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		cc.logger.Zap.Error("Error Get File from request :: ", err.Error())
+		err := errors.BadRequest.Wrap(err, "Failed to get file form request")
+		responses.HandleError(c, err)
+		return
+	}
+	defer file.Close()
+
+	fileHeader := make([]byte, 512)
+	if _, err := file.Read(fileHeader); err != nil {
+		cc.logger.Zap.Error("Error File Read upload File::", err.Error())
+		err := errors.BadRequest.Wrap(err, "Failed to read upload File")
+		responses.HandleError(c, err)
+		return
+	}
+	fileType := http.DetectContentType(fileHeader)
+	if fileType != "image/png" && fileType != "image/jpeg" {
+		err := errors.BadRequest.Newf("unsupported image type %s", fileType)
+		err = errors.SetCustomMessage(err, "Only png and jpeg images are supported")
+		responses.HandleError(c, err)
+		return
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		err := errors.BadRequest.Wrap(err, "Failed to read upload File")
+		responses.HandleError(c, err)
+		return
+	}
+
+	blog, err := cc.blogService.WithTrx(trx).UploadImage(c.Request.Context(), ID, file, fileType, c.GetInt64(constants.UserID), c.GetString(constants.Role))
+	if err != nil {
+		cc.logger.Zap.Error("Error [UploadBlogImage] [UploadImage]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, gin.H{
+		"thumbnail_image": blog.ThumbnailImage,
+		"og_image":        blog.OGImage,
+	})
+}
+
+// DeleteOneBlog -> Delete One Blog By Id
+func (cc BlogController) DeleteOneBlog(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.blogService.WithTrx(trx).DeleteOneBlog(ID, c.GetInt64(constants.UserID), c.GetString(constants.Role)); err != nil {
+		cc.logger.Zap.Error("Error [DeleteOneBlog] [db DeleteOneBlog]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.SuccessJSON(c, http.StatusOK, "Blog Deleted Sucessfully")
+}
@@ -0,0 +1,24 @@
+package controllers
+
+import (
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bulkResponse -> responds with the per operation results of a bulk request
+// an atomic request that failed responds with the status of the failed operation, which rolls the transaction back
+func bulkResponse(c *gin.Context, result services.BulkResult) {
+	for i := range result.Results {
+		if item := &result.Results[i]; item.Err != nil {
+			_, item.Error = responses.ErrorResponse(item.Err)
+		}
+	}
+	if failed := result.FirstError(); result.Atomic && failed != nil {
+		responses.JSON(c, failed.Status, result)
+		return
+	}
+	responses.JSON(c, http.StatusOK, result)
+}
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"boilerplate-api/api/validators"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CategoryController -> struct
+type CategoryController struct {
+	logger          infrastructure.Logger
+	categoryService services.CategoryService
+	validator       validators.CategoryValidator
+}
+
+// NewCategoryController -> constructor
+func NewCategoryController(
+	logger infrastructure.Logger,
+	categoryService services.CategoryService,
+	validator validators.CategoryValidator,
+) CategoryController {
+	return CategoryController{
+		logger:          logger,
+		categoryService: categoryService,
+		validator:       validator,
+	}
+}
+
+// bindCategory -> binds and validates the category from the request body
+func (cc CategoryController) bindCategory(c *gin.Context) (models.Category, error) {
+	category := models.Category{}
+	if err := c.ShouldBindJSON(&category); err != nil {
+		return category, errors.BadRequest.Wrap(err, "Failed to bind category")
+	}
+	if validationErr := cc.validator.Validate.Struct(category); validationErr != nil {
+		err := errors.BadRequest.Wrap(validationErr, "Validation error")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return category, errors.AddErrorContextBlock(err, cc.validator.GenerateValidationResponse(validationErr))
+	}
+	return category, nil
+}
+
+// CreateCategory -> Create Category
+func (cc CategoryController) CreateCategory(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	category, err := cc.bindCategory(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateCategory] (bindCategory) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
+
+	category, err = cc.categoryService.WithTrx(trx).CreateCategory(category)
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateCategory] [db CreateCategory]: ", err.Error())
+		err := errors.BadRequest.Wrap(err, "Failed To Create Category")
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, category)
+}
+
+// GetAllCategory -> Get All Category
+func (cc CategoryController) GetAllCategory(c *gin.Context) {
+	pagination := utils.BuildPagination(c)
+	categories, count, err := cc.categoryService.GetAllCategory(pagination)
+	if err != nil {
+		cc.logger.Zap.Error("Error finding Category records", err.Error())
+		// invalid sort or filter query
+		if errors.GetErrorType(err) == errors.BadRequest {
+			responses.HandleError(c, err)
+			return
+		}
+		err := errors.InternalError.Wrap(err, "Failed To Find Category")
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSONCount(c, http.StatusOK, categories, count)
+}
+
+// GetOneCategory -> Get One Category
+func (cc CategoryController) GetOneCategory(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	category, err := cc.categoryService.GetOneCategory(ID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [GetOneCategory] [db GetOneCategory]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, category)
+}
+
+// UpdateOneCategory -> Update One Category By Id
+func (cc CategoryController) UpdateOneCategory(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	category, err := cc.bindCategory(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [UpdateCategory] (bindCategory) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
+	category.ID = ID
+
+	if err := cc.categoryService.WithTrx(trx).UpdateOneCategory(category); err != nil {
+		cc.logger.Zap.Error("Error [UpdateCategory] [db UpdateCategory]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.SuccessJSON(c, http.StatusOK, "Category Updated Sucessfully")
+}
+
+// DeleteOneCategory -> Delete One Category By Id
+func (cc CategoryController) DeleteOneCategory(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.categoryService.WithTrx(trx).DeleteOneCategory(ID); err != nil {
+		cc.logger.Zap.Error("Error [DeleteOneCategory] [db DeleteOneCategory]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.SuccessJSON(c, http.StatusOK, "Category Deleted Sucessfully")
+}
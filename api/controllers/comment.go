@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"boilerplate-api/api/validators"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CommentController -> struct
+type CommentController struct {
+	logger         infrastructure.Logger
+	commentService services.CommentService
+	validator      validators.CommentValidator
+}
+
+// NewCommentController -> constructor
+func NewCommentController(
+	logger infrastructure.Logger,
+	commentService services.CommentService,
+	validator validators.CommentValidator,
+) CommentController {
+	return CommentController{
+		logger:         logger,
+		commentService: commentService,
+		validator:      validator,
+	}
+}
+
+// bindComment -> binds and validates the comment from the request body
+func (cc CommentController) bindComment(c *gin.Context) (models.Comment, error) {
+	comment := models.Comment{}
+	if err := c.ShouldBindJSON(&comment); err != nil {
+		return comment, errors.BadRequest.Wrap(err, "Failed to bind comment")
+	}
+	if validationErr := cc.validator.Validate.Struct(comment); validationErr != nil {
+		err := errors.BadRequest.Wrap(validationErr, "Validation error")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return comment, errors.AddErrorContextBlock(err, cc.validator.GenerateValidationResponse(validationErr))
+	}
+	// authorship and moderation are never taken from the body
+	comment.User = nil
+	comment.Replies = nil
+	return comment, nil
+}
+
+// CreateComment -> Create Comment or reply on a published blog
+func (cc CommentController) CreateComment(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	blogID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	comment, err := cc.bindComment(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateComment] (bindComment) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
+	comment.BlogId = blogID
+
+	if err := cc.commentService.WithTrx(trx).CreateComment(&comment, c.GetInt64(constants.UserID), c.GetString(constants.Role)); err != nil {
+		cc.logger.Zap.Error("Error [CreateComment] [db CreateComment]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, comment)
+}
+
+// GetBlogComments -> Get approved comments of a published blog as a tree, paginated by thread
+func (cc CommentController) GetBlogComments(c *gin.Context) {
+	blogID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	pagination := utils.BuildPagination(c)
+	comments, count, err := cc.commentService.GetCommentTree(blogID, pagination)
+	if err != nil {
+		cc.logger.Zap.Error("Error finding Comment records", err.Error())
+		if errors.GetErrorType(err) == errors.NotFound {
+			responses.HandleError(c, err)
+			return
+		}
+		err := errors.InternalError.Wrap(err, "Failed To Find Comment")
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSONCount(c, http.StatusOK, comments, count)
+}
+
+// GetModerationQueue -> Get comments waiting for moderation
+func (cc CommentController) GetModerationQueue(c *gin.Context) {
+	pagination := utils.BuildPagination(c)
+	comments, count, err := cc.commentService.GetModerationQueue(pagination)
+	if err != nil {
+		cc.logger.Zap.Error("Error finding Comment records", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed To Find Comment")
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSONCount(c, http.StatusOK, comments, count)
+}
+
+// UpdateComment -> Update the content of a comment
+func (cc CommentController) UpdateComment(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	comment, err := cc.bindComment(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [UpdateComment] (bindComment) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
+
+	if err := cc.commentService.UpdateComment(ID, comment.Content, c.GetInt64(constants.UserID), c.GetString(constants.Role)); err != nil {
+		cc.logger.Zap.Error("Error [UpdateComment] [db UpdateComment]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.SuccessJSON(c, http.StatusOK, "Comment Updated Sucessfully")
+}
+
+// DeleteComment -> Delete Comment By Id along with its replies
+func (cc CommentController) DeleteComment(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.commentService.WithTrx(trx).DeleteComment(ID, c.GetInt64(constants.UserID), c.GetString(constants.Role)); err != nil {
+		cc.logger.Zap.Error("Error [DeleteComment] [db DeleteComment]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.SuccessJSON(c, http.StatusOK, "Comment Deleted Sucessfully")
+}
+
+// ApproveComment -> Approve a comment
+func (cc CommentController) ApproveComment(c *gin.Context) {
+	cc.moderate(c, true)
+}
+
+// RejectComment -> Reject a comment
+func (cc CommentController) RejectComment(c *gin.Context) {
+	cc.moderate(c, false)
+}
+
+func (cc CommentController) moderate(c *gin.Context, approved bool) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.commentService.Moderate(ID, approved, c.GetInt64(constants.UserID)); err != nil {
+		cc.logger.Zap.Error("Error [Moderate] [db SetStatus]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	if approved {
+		responses.SuccessJSON(c, http.StatusOK, "Comment Approved")
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Comment Rejected")
+}
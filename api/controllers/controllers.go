@@ -7,4 +7,11 @@ var Module = fx.Options(
 	fx.Provide(NewUserController),
 	fx.Provide(NewUtilityController),
 	fx.Provide(NewTodoController),
+	fx.Provide(NewTodoListController),
+	fx.Provide(NewOrganizationController),
+	fx.Provide(NewInvitationController),
+	fx.Provide(NewBlogController),
+	fx.Provide(NewCategoryController),
+	fx.Provide(NewCommentController),
+	fx.Provide(NewTrashController),
 )
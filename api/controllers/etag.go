@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// notModified -> sets the ETag of the version, responds with 304 when If-None-Match already has it
+func notModified(c *gin.Context, version int64) bool {
+	etag := utils.ETag(version)
+	c.Header("ETag", etag)
+	if header := c.GetHeader("If-None-Match"); header != "" && utils.ETagMatch(header, etag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// ifMatch -> version the If-Match header was sent for, 0 for `*`
+// changes without If-Match are rejected so clients cannot overwrite changes they have not seen
+func ifMatch(c *gin.Context) (int64, error) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		err := errors.PreconditionRequired.New("missing If-Match header")
+		return 0, errors.SetCustomMessage(err, "If-Match header with the ETag of the resource is required")
+	}
+	if header == "*" {
+		return 0, nil
+	}
+	version, ok := utils.ParseETag(header)
+	if !ok {
+		err := errors.PreconditionFailed.Newf("unsupported If-Match header %s", header)
+		return 0, errors.SetCustomMessage(err, "The resource has been modified, fetch it again")
+	}
+	return version, nil
+}
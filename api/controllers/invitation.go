@@ -0,0 +1,172 @@
+package controllers
+
+import (
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"boilerplate-api/api/validators"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+// InvitationController -> struct
+type InvitationController struct {
+	logger            infrastructure.Logger
+	invitationService services.InvitationService
+	userService       services.UserService
+	validator         validators.UserValidator
+}
+
+// NewInvitationController -> constructor
+func NewInvitationController(
+	logger infrastructure.Logger,
+	invitationService services.InvitationService,
+	userService services.UserService,
+	validator validators.UserValidator,
+) InvitationController {
+	return InvitationController{
+		logger:            logger,
+		invitationService: invitationService,
+		userService:       userService,
+		validator:         validator,
+	}
+}
+
+// CreateInvitation -> Invite a new user by email
+func (cc InvitationController) CreateInvitation(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	reqData := struct {
+		Email string `json:"email" validate:"required,email"`
+		Role  string `json:"role" validate:"required,oneof=admin client client_admin client_general client_user user"`
+	}{}
+
+	if err := c.ShouldBindJSON(&reqData); err != nil {
+		cc.logger.Zap.Error("Error [CreateInvitation] (ShouldBindJson) : ", err)
+		err := errors.BadRequest.Wrap(err, "Failed to bind invitation")
+		responses.HandleError(c, err)
+		return
+	}
+	if validationErr := cc.validator.Validate.Struct(reqData); validationErr != nil {
+		err := errors.BadRequest.Wrap(validationErr, "Validation error")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		err = errors.AddErrorContextBlock(err, cc.validator.GenerateValidationResponse(validationErr))
+		responses.HandleError(c, err)
+		return
+	}
+
+	inviter, err := cc.userService.GetOneUser(c.GetInt64(constants.UserID))
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateInvitation] [db GetOneUser]: ", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed to get users data")
+		responses.HandleError(c, err)
+		return
+	}
+
+	invitation, err := cc.invitationService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).
+		Invite(reqData.Email, reqData.Role, *inviter)
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateInvitation] [db Invite]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, invitation.ToMap())
+}
+
+// GetPendingInvitations -> Get all pending invitations
+func (cc InvitationController) GetPendingInvitations(c *gin.Context) {
+	pagination := utils.BuildPagination(c)
+	invitations, count, err := cc.invitationService.WithTenant(c.GetInt64(constants.TenantID)).GetAllPending(pagination)
+	if err != nil {
+		cc.logger.Zap.Error("Error finding invitation records", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed to get invitations")
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSONCount(c, http.StatusOK, invitations, count)
+}
+
+// ResendInvitation -> Send a new invitation link
+func (cc InvitationController) ResendInvitation(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	invitation, err := cc.invitationService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).Resend(ID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [ResendInvitation] [db Resend]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, invitation.ToMap())
+}
+
+// RevokeInvitation -> Revoke a pending invitation
+func (cc InvitationController) RevokeInvitation(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.invitationService.WithTenant(c.GetInt64(constants.TenantID)).Revoke(ID); err != nil {
+		cc.logger.Zap.Error("Error [RevokeInvitation] [db Revoke]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Invitation revoked")
+}
+
+// AcceptInvitation -> Join the organization of the invitation or create the invited user with their own password
+func (cc InvitationController) AcceptInvitation(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	reqData := struct {
+		Token           string `json:"token" validate:"required"`
+		Username        string `json:"username" validate:"required_with=Password"`
+		FullName        string `json:"full_name" validate:"required_with=Password"`
+		Phone           string `json:"phone" validate:"required_with=Password"`
+		Address         string `json:"address" validate:"required_with=Password"`
+		Password        string `json:"password" validate:"omitempty,min=8"`
+		ConfirmPassword string `json:"confirm_password" validate:"eqfield=Password"`
+	}{}
+
+	if err := c.ShouldBindJSON(&reqData); err != nil {
+		cc.logger.Zap.Error("Error [AcceptInvitation] (ShouldBindJson) : ", err)
+		err := errors.BadRequest.Wrap(err, "Failed to bind invitation data")
+		responses.HandleError(c, err)
+		return
+	}
+	if validationErr := cc.validator.Validate.Struct(reqData); validationErr != nil {
+		err := errors.BadRequest.Wrap(validationErr, "Validation error")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		err = errors.AddErrorContextBlock(err, cc.validator.GenerateValidationResponse(validationErr))
+		responses.HandleError(c, err)
+		return
+	}
+
+	// Registered users join with the token alone, new users sign up with their profile
+	var user *models.User
+	if reqData.Password != "" {
+		password, err := bcrypt.GenerateFromPassword([]byte(reqData.Password), 10)
+		if err != nil {
+			responses.ErrorJSON(c, http.StatusInternalServerError, "Failed top create hash pw")
+			return
+		}
+		user = &models.User{
+			Username: reqData.Username,
+			FullName: reqData.FullName,
+			Phone:    reqData.Phone,
+			Address:  reqData.Address,
+			Password: string(password),
+		}
+	}
+	created, err := cc.invitationService.WithTrx(trx).Accept(reqData.Token, user)
+	if err != nil {
+		cc.logger.Zap.Error("Error [AcceptInvitation] [db Accept]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, created.ToMap())
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"boilerplate-api/api/validators"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// OrganizationController -> struct
+type OrganizationController struct {
+	logger              infrastructure.Logger
+	organizationService services.OrganizationService
+	userService         services.UserService
+	validator           validators.UserValidator
+}
+
+// NewOrganizationController -> constructor
+func NewOrganizationController(
+	logger infrastructure.Logger,
+	organizationService services.OrganizationService,
+	userService services.UserService,
+	validator validators.UserValidator,
+) OrganizationController {
+	return OrganizationController{
+		logger:              logger,
+		organizationService: organizationService,
+		userService:         userService,
+		validator:           validator,
+	}
+}
+
+// currentUser -> authenticated user of the request
+func (cc OrganizationController) currentUser(c *gin.Context) (*models.User, error) {
+	return cc.userService.GetOneUser(c.GetInt64(constants.UserID))
+}
+
+// CreateOrganization -> Create Organization
+func (cc OrganizationController) CreateOrganization(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	organization := models.Organization{}
+
+	if err := c.ShouldBindJSON(&organization); err != nil {
+		cc.logger.Zap.Error("Error [CreateOrganization] (ShouldBindJson) : ", err)
+		err := errors.BadRequest.Wrap(err, "Failed to bind organization")
+		responses.HandleError(c, err)
+		return
+	}
+	if validationErr := cc.validator.Validate.Struct(organization); validationErr != nil {
+		err := errors.BadRequest.Wrap(validationErr, "Validation error")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		err = errors.AddErrorContextBlock(err, cc.validator.GenerateValidationResponse(validationErr))
+		responses.HandleError(c, err)
+		return
+	}
+
+	user, err := cc.currentUser(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateOrganization] [db GetOneUser]: ", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed to get users data")
+		responses.HandleError(c, err)
+		return
+	}
+
+	if err := cc.organizationService.WithTrx(trx).CreateOrganization(&organization, *user); err != nil {
+		cc.logger.Zap.Error("Error [CreateOrganization] [db CreateOrganization]: ", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed to create organization")
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, organization.ToMap())
+}
+
+// GetMyOrganizations -> Get organizations of the authenticated user
+func (cc OrganizationController) GetMyOrganizations(c *gin.Context) {
+	memberships, err := cc.organizationService.GetUserOrganizations(c.GetInt64(constants.UserID))
+	if err != nil {
+		cc.logger.Zap.Error("Error finding organization records", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed to get organizations")
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, memberships)
+}
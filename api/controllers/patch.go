@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/utils"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// patchDocument -> applies the merge patch or JSON Patch of the request to the writable fields
+// writable holds the current values and is filled with the patched ones, the fields the patch changed are returned
+// fields outside of writable are rejected, removed fields come back as null
+func patchDocument(c *gin.Context, writable interface{}) (map[string]json.RawMessage, error) {
+	contentType := c.GetHeader("Content-Type")
+	if !utils.IsPatchContentType(contentType) {
+		err := errors.UnsupportedMediaType.Newf("unsupported patch content type %s", contentType)
+		return nil, errors.SetCustomMessage(err, "Content-Type has to be one of "+strings.Join(utils.PatchContentTypes, ", "))
+	}
+	patch, err := ioutil.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, errors.BadRequest.Wrap(err, "failed to read patch")
+	}
+	document, err := json.Marshal(writable)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := utils.ApplyPatch(contentType, document, patch)
+	if err != nil {
+		err := errors.BadRequest.Wrap(err, "failed to apply patch")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return nil, errors.AddErrorContext(err, "patch", "Patch could not be applied.")
+	}
+
+	before, after := map[string]json.RawMessage{}, map[string]json.RawMessage{}
+	if err := json.Unmarshal(document, &before); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		err := errors.BadRequest.Wrap(err, "patched document is not an object")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return nil, errors.AddErrorContext(err, "patch", "Patch has to leave an object.")
+	}
+
+	fields := make([]string, 0, len(after))
+	for field := range after {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	var invalid []errors.ErrorContext
+	changed := map[string]json.RawMessage{}
+	for _, field := range fields {
+		original, ok := before[field]
+		if !ok {
+			invalid = append(invalid, errors.ErrorContext{Field: field, Message: fmt.Sprintf("Field '%s' can not be changed.", field)})
+			continue
+		}
+		if !jsonEqual(original, after[field]) {
+			changed[field] = after[field]
+		}
+	}
+	for field := range before {
+		if _, ok := after[field]; !ok {
+			changed[field] = json.RawMessage("null")
+			after[field] = json.RawMessage("null")
+		}
+	}
+	if len(invalid) > 0 {
+		err := errors.BadRequest.New("patch changes fields that are not writable")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return nil, errors.AddErrorContextBlock(err, invalid)
+	}
+
+	patched, err = json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+	// null has to clear the field, which unmarshalling into the current values would not do
+	target := reflect.ValueOf(writable).Elem()
+	target.Set(reflect.Zero(target.Type()))
+	if bindErr := json.Unmarshal(patched, writable); bindErr != nil {
+		err := errors.BadRequest.Wrap(bindErr, "failed to bind patched document")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		if typeErr, ok := bindErr.(*json.UnmarshalTypeError); ok {
+			return nil, errors.AddErrorContext(err, typeErr.Field, fmt.Sprintf("Field '%s' is not valid.", typeErr.Field))
+		}
+		return nil, errors.AddErrorContext(err, "patch", "Patched document is not valid.")
+	}
+	return changed, nil
+}
+
+// jsonEqual -> whether both values encode the same JSON
+func jsonEqual(a json.RawMessage, b json.RawMessage) bool {
+	var left, right interface{}
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
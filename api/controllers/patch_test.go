@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"boilerplate-api/errors"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPatchDocument(t *testing.T) {
+	priority, listID := 2, int64(5)
+	current := func() todoPatch {
+		return todoPatch{Task: "milk", Priority: &priority, ListID: &listID, Tags: []string{"home"}}
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		patch       string
+		wantChanged []string
+		wantNull    []string
+		want        func(patch *todoPatch)
+		wantErr     bool
+		wantErrType errors.HttpErrorType
+		wantInvalid []string
+	}{
+		{
+			name:        "merge patch",
+			contentType: "application/merge-patch+json",
+			patch:       `{"task":"bread","priority":2}`,
+			wantChanged: []string{"task"},
+			want:        func(patch *todoPatch) { patch.Task = "bread" },
+		},
+		{
+			name:        "null clears the field",
+			contentType: "application/merge-patch+json",
+			patch:       `{"list_id":null}`,
+			wantChanged: []string{"list_id"},
+			wantNull:    []string{"list_id"},
+			want:        func(patch *todoPatch) { patch.ListID = nil },
+		},
+		{
+			name:        "json patch",
+			contentType: "application/json-patch+json",
+			patch:       `[{"op":"add","path":"/tags/-","value":"work"},{"op":"remove","path":"/priority"}]`,
+			wantChanged: []string{"priority", "tags"},
+			wantNull:    []string{"priority"},
+			want: func(patch *todoPatch) {
+				patch.Tags = []string{"home", "work"}
+				patch.Priority = nil
+			},
+		},
+		{
+			name:        "unchanged values are not reported",
+			contentType: "application/json",
+			patch:       `{"task":"milk","tags":["home"]}`,
+			want:        func(patch *todoPatch) {},
+		},
+		{name: "unsupported content type", contentType: "text/plain", patch: `{}`, wantErr: true, wantErrType: errors.UnsupportedMediaType},
+		{name: "patch that can not be applied", contentType: "application/json-patch+json", patch: `[{"op":"replace","path":"/owner_id","value":1}]`, wantErr: true, wantErrType: errors.BadRequest, wantInvalid: []string{"patch"}},
+		{name: "fields outside of the writable ones", contentType: "application/merge-patch+json", patch: `{"owner_id":1,"version":3,"task":"bread"}`, wantErr: true, wantErrType: errors.BadRequest, wantInvalid: []string{"owner_id", "version"}},
+		{name: "patch leaving no object", contentType: "application/json-patch+json", patch: `[{"op":"replace","path":"","value":[]}]`, wantErr: true, wantErrType: errors.BadRequest, wantInvalid: []string{"patch"}},
+		{name: "value of the wrong type", contentType: "application/merge-patch+json", patch: `{"priority":"high"}`, wantErr: true, wantErrType: errors.BadRequest, wantInvalid: []string{"priority"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("PATCH", "/todo/1", strings.NewReader(test.patch))
+			c.Request.Header.Set("Content-Type", test.contentType)
+
+			patch := current()
+			changed, err := patchDocument(c, &patch)
+			if test.wantErr {
+				if errors.GetErrorType(err) != test.wantErrType {
+					t.Fatalf("error = %v, want type %v", err, test.wantErrType)
+				}
+				var invalid []string
+				for _, context := range errors.GetErrorContext(err) {
+					invalid = append(invalid, context.Field)
+				}
+				if !reflect.DeepEqual(invalid, test.wantInvalid) {
+					t.Errorf("invalid fields = %v, want %v", invalid, test.wantInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			fields, nulls := []string{}, []string{}
+			for field, value := range changed {
+				fields = append(fields, field)
+				if string(value) == "null" {
+					nulls = append(nulls, field)
+				}
+			}
+			sort.Strings(fields)
+			sort.Strings(nulls)
+			if test.wantChanged == nil {
+				test.wantChanged = []string{}
+			}
+			if test.wantNull == nil {
+				test.wantNull = []string{}
+			}
+			if !reflect.DeepEqual(fields, test.wantChanged) || !reflect.DeepEqual(nulls, test.wantNull) {
+				t.Errorf("changed = %v with nulls %v, want %v with nulls %v", fields, nulls, test.wantChanged, test.wantNull)
+			}
+
+			want := current()
+			test.want(&want)
+			got, _ := json.Marshal(patch)
+			wantJSON, _ := json.Marshal(want)
+			if string(got) != string(wantJSON) {
+				t.Errorf("patched = %s, want %s", got, wantJSON)
+			}
+		})
+	}
+}
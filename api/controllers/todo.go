@@ -1,37 +1,82 @@
 package controllers
 
 import (
+	"boilerplate-api/api/repository"
 	"boilerplate-api/api/responses"
 	"boilerplate-api/api/services"
+	"boilerplate-api/constants"
 	"boilerplate-api/errors"
 	"boilerplate-api/infrastructure"
 	"boilerplate-api/models"
 	"boilerplate-api/utils"
+	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 // TodoController -> struct
 type TodoController struct {
 	logger      infrastructure.Logger
+	env         infrastructure.Env
 	TodoService services.TodoService
+	bulkService services.BulkService
 }
 
 // NewTodoController -> constructor
 func NewTodoController(
 	logger infrastructure.Logger,
+	env infrastructure.Env,
 	TodoService services.TodoService,
+	bulkService services.BulkService,
 ) TodoController {
 	return TodoController{
 		logger:      logger,
+		env:         env,
 		TodoService: TodoService,
+		bulkService: bulkService,
 	}
 }
 
+// todoFilter -> tag, due date and relation filters of the todo listing
+// `?tag=a,b` matches todos having all the tags, `?due=` is computed in `?timezone=` (UTC by default)
+// `?relation=owned|assigned|shared` limits todos to the ones related to the user
+func (cc TodoController) todoFilter(c *gin.Context) (repository.TodoFilter, error) {
+	filter := repository.TodoFilter{
+		Due:      c.Query("due"),
+		Relation: c.Query("relation"),
+		UserID:   c.GetInt64(constants.UserID),
+	}
+	if tags := c.Query("tag"); tags != "" {
+		filter.Tags = services.NormalizeTags(strings.Split(tags, ","))
+	}
+	if filter.Due != "" && !utils.StringInList(filter.Due, repository.TodoDueFilters) {
+		err := errors.BadRequest.Newf("unknown due filter %s", filter.Due)
+		err = errors.SetCustomMessage(err, "Invalid query")
+		return filter, errors.AddErrorContext(err, "due", "Due has to be one of "+strings.Join(repository.TodoDueFilters, ", ")+".")
+	}
+	if filter.Relation != "" && !utils.StringInList(filter.Relation, repository.TodoRelations) {
+		err := errors.BadRequest.Newf("unknown relation filter %s", filter.Relation)
+		err = errors.SetCustomMessage(err, "Invalid query")
+		return filter, errors.AddErrorContext(err, "relation", "Relation has to be one of "+strings.Join(repository.TodoRelations, ", ")+".")
+	}
+	loc, err := utils.LoadTimezone(c.Query("timezone"))
+	if err != nil {
+		err := errors.BadRequest.Wrap(err, "unknown timezone")
+		err = errors.SetCustomMessage(err, "Invalid query")
+		return filter, errors.AddErrorContext(err, "timezone", "Unknown timezone '"+c.Query("timezone")+"'.")
+	}
+	filter.Location = loc
+	return filter, nil
+}
+
 // CreateTodo -> Create Todo
 func (cc TodoController) CreateTodo(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
 	todo := models.Todo{}
 
 	if err := c.ShouldBindJSON(&todo); err != nil {
@@ -40,9 +85,15 @@ func (cc TodoController) CreateTodo(c *gin.Context) {
 		responses.HandleError(c, err)
 		return
 	}
+	todo.OrganizationID = nil
 
-	if _, err := cc.TodoService.CreateTodo(todo); err != nil {
+	if _, err := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).CreateTodo(todo); err != nil {
 		cc.logger.Zap.Error("Error [CreateTodo] [db CreateTodo]: ", err.Error())
+		// invalid list, priority, due date, tags or assignee
+		if errors.GetErrorType(err) == errors.BadRequest {
+			responses.HandleError(c, err)
+			return
+		}
 		err := errors.BadRequest.Wrap(err, "Failed To Create Todo")
 		responses.HandleError(c, err)
 		return
@@ -55,38 +106,83 @@ func (cc TodoController) CreateTodo(c *gin.Context) {
 func (cc TodoController) GetAllTodo(c *gin.Context) {
 
 	pagination := utils.BuildPagination(c)
-	pagination.Sort = "CreateDateTime desc"
-	todos, count, err := cc.TodoService.GetAllTodo(pagination)
+	if err := pagination.DecodeCursor(cc.env.PaginationCursorSecret); err != nil {
+		cc.logger.Zap.Error("Error [GetAllTodo] (DecodeCursor) : ", err)
+		err := errors.BadRequest.Wrap(err, "Invalid cursor")
+		responses.HandleError(c, err)
+		return
+	}
+	filter, err := cc.todoFilter(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [GetAllTodo] (todoFilter) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
+	fieldset := utils.BuildFieldset(c)
+	todos, page, err := cc.TodoService.WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).WithFieldset(fieldset).GetAllTodo(pagination, filter)
 
 	if err != nil {
 		cc.logger.Zap.Error("Error finding Todo records", err.Error())
+		// invalid sort, filter or fields query
+		if errors.GetErrorType(err) == errors.BadRequest {
+			responses.HandleError(c, err)
+			return
+		}
 		err := errors.InternalError.Wrap(err, "Failed To Find Todo")
 		responses.HandleError(c, err)
 		return
 	}
-	responses.JSONCount(c, http.StatusOK, todos, count)
+	next, prev := page.EncodeCursors(cc.env.PaginationCursorSecret)
+	responses.JSONPage(c, http.StatusOK, responses.Sparse(todos, fieldset), page.Count, next, prev)
 
 }
 
 // GetOneTodo -> Get One Todo
 func (cc TodoController) GetOneTodo(c *gin.Context) {
 	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-	todo, err := cc.TodoService.GetOneTodo(ID)
+	fieldset := utils.BuildFieldset(c)
+	todo, err := cc.TodoService.WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).WithFieldset(fieldset).GetOneTodo(ID)
 
 	if err != nil {
 		cc.logger.Zap.Error("Error [GetOneTodo] [db GetOneTodo]: ", err.Error())
+		// invalid fields query
+		if errors.GetErrorType(err) == errors.BadRequest {
+			responses.HandleError(c, err)
+			return
+		}
 		err := errors.InternalError.Wrap(err, "Failed To Find Todo")
 		responses.HandleError(c, err)
 		return
 	}
-	responses.JSON(c, http.StatusOK, todo)
+	if notModified(c, todo.Version) {
+		return
+	}
+	responses.JSON(c, http.StatusOK, responses.Sparse(todo, fieldset))
 
 }
 
-// UpdateOneTodo -> Update One Todo By Id
+// GetAllTags -> Get the tags used on todos of the organization
+func (cc TodoController) GetAllTags(c *gin.Context) {
+	tags, err := cc.TodoService.WithTenant(c.GetInt64(constants.TenantID)).GetAllTags()
+	if err != nil {
+		cc.logger.Zap.Error("Error finding Tag records", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed To Find Tag")
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, tags)
+}
+
+// UpdateOneTodo -> Update One Todo By Id, requires If-Match with the ETag of the todo
 func (cc TodoController) UpdateOneTodo(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
 	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
 	todo := models.Todo{}
+	version, err := ifMatch(c)
+	if err != nil {
+		responses.HandleError(c, err)
+		return
+	}
 
 	if err := c.ShouldBindJSON(&todo); err != nil {
 		cc.logger.Zap.Error("Error [UpdateTodo] (ShouldBindJson) : ", err)
@@ -95,24 +191,212 @@ func (cc TodoController) UpdateOneTodo(c *gin.Context) {
 		return
 	}
 	todo.ID = ID
+	todo.OrganizationID = nil
+	todo.Version = version
 
-	if err := cc.TodoService.UpdateOneTodo(todo); err != nil {
+	next, err := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).UpdateOneTodo(todo)
+	if err != nil {
 		cc.logger.Zap.Error("Error [UpdateTodo] [db UpdateTodo]: ", err.Error())
+		// unknown, forbidden or modified todo, invalid list, priority, due date or tags
+		if t := errors.GetErrorType(err); t == errors.BadRequest || t == errors.NotFound || t == errors.Forbidden || t == errors.PreconditionFailed {
+			responses.HandleError(c, err)
+			return
+		}
 		err := errors.InternalError.Wrap(err, "failed to update todo")
 		responses.HandleError(c, err)
 		return
 	}
 
+	// completing a recurring todo hands back its next occurrence
+	if next != nil {
+		responses.JSON(c, http.StatusOK, gin.H{"next_occurrence": next})
+		return
+	}
 	responses.SuccessJSON(c, http.StatusOK, "Todo Updated Sucessfully")
 }
 
-// DeleteOneTodo -> Delete One Todo By Id
+// todoPatch -> fields of a todo PATCH can change
+type todoPatch struct {
+	Task        string     `json:"task"`
+	IsCompleted *bool      `json:"is_completed"`
+	ListID      *int64     `json:"list_id"`
+	Priority    *int       `json:"priority"`
+	DueAt       *time.Time `json:"due_at"`
+	DueTimezone string     `json:"due_timezone"`
+	RRule       *string    `json:"rrule"`
+	Tags        []string   `json:"tags"`
+}
+
+// PatchOneTodo -> Partially update One Todo By Id with a merge patch or JSON Patch, requires If-Match with the ETag of the todo
+// null clears list_id, due_at and rrule and resets priority to none, `[{"op": "add", "path": "/tags/-", "value": "home"}]` adds a tag
+func (cc TodoController) PatchOneTodo(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	version, err := ifMatch(c)
+	if err != nil {
+		responses.HandleError(c, err)
+		return
+	}
+	service := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role))
+	current, err := service.GetOneTodo(ID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [PatchTodo] [db GetOneTodo]: ", err.Error())
+		if err == gorm.ErrRecordNotFound {
+			responses.HandleError(c, errors.NotFound.Wrap(err, "todo not found"))
+			return
+		}
+		responses.HandleError(c, errors.InternalError.Wrap(err, "Failed To Find Todo"))
+		return
+	}
+
+	patch := todoPatch{
+		Task:        current.Task,
+		IsCompleted: current.IsCompleted,
+		ListID:      current.ListID,
+		Priority:    current.Priority,
+		DueAt:       current.DueAt,
+		DueTimezone: current.DueTimezone,
+		RRule:       current.RRule,
+		Tags:        current.Tags,
+	}
+	if patch.Tags == nil {
+		patch.Tags = []string{}
+	}
+	changed, err := patchDocument(c, &patch)
+	if err != nil {
+		cc.logger.Zap.Error("Error [PatchTodo] (patchDocument) : ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	todo := models.Todo{Version: version}
+	todo.ID = ID
+	var clear []string
+	for field, value := range changed {
+		if _, ok := services.TodoClearableColumns[field]; ok && string(value) == "null" {
+			clear = append(clear, field)
+			continue
+		}
+		switch field {
+		case "task":
+			if strings.TrimSpace(patch.Task) == "" {
+				err := errors.BadRequest.New("empty task")
+				err = errors.SetCustomMessage(err, "Invalid input information")
+				responses.HandleError(c, errors.AddErrorContext(err, "task", "Field 'task' is 'required'."))
+				return
+			}
+			todo.Task = patch.Task
+		case "is_completed":
+			completed := patch.IsCompleted != nil && *patch.IsCompleted
+			todo.IsCompleted = &completed
+		case "list_id":
+			todo.ListID = patch.ListID
+		case "priority":
+			todo.Priority = patch.Priority
+			if todo.Priority == nil {
+				none := models.TodoPriorityNone
+				todo.Priority = &none
+			}
+		case "due_at":
+			todo.DueAt = patch.DueAt
+		case "due_timezone":
+			todo.DueTimezone = patch.DueTimezone
+		case "rrule":
+			// an empty rule stops the todo from recurring
+			rule := ""
+			if patch.RRule != nil {
+				rule = *patch.RRule
+			}
+			todo.RRule = &rule
+		case "tags":
+			todo.Tags = patch.Tags
+			if todo.Tags == nil {
+				todo.Tags = []string{}
+			}
+		}
+	}
+
+	next, err := service.PatchOneTodo(todo, clear)
+	if err != nil {
+		cc.logger.Zap.Error("Error [PatchTodo] [db PatchOneTodo]: ", err.Error())
+		// forbidden or modified todo, invalid list, priority, due date or tags
+		if t := errors.GetErrorType(err); t == errors.BadRequest || t == errors.NotFound || t == errors.Forbidden || t == errors.PreconditionFailed {
+			responses.HandleError(c, err)
+			return
+		}
+		err := errors.InternalError.Wrap(err, "failed to update todo")
+		responses.HandleError(c, err)
+		return
+	}
+
+	// completing a recurring todo hands back its next occurrence
+	if next != nil {
+		responses.JSON(c, http.StatusOK, gin.H{"next_occurrence": next})
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Todo Updated Sucessfully")
+}
+
+// GetTodoOccurrences -> Get upcoming occurrences of a recurring todo (`?count=`, 10 by default)
+func (cc TodoController) GetTodoOccurrences(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	count, _ := strconv.Atoi(c.Query("count"))
+	occurrences, err := cc.TodoService.WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).GetOccurrences(ID, count)
+	if err != nil {
+		cc.logger.Zap.Error("Error [GetTodoOccurrences] [GetOccurrences]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, occurrences)
+}
+
+// PreviewOccurrences -> Preview the occurrences of a rule before saving it
+// `?rrule=FREQ=WEEKLY;BYDAY=MO&start=2024-01-01T09:00&timezone=Asia/Kathmandu&count=5`, start defaults to now
+func (cc TodoController) PreviewOccurrences(c *gin.Context) {
+	count, _ := strconv.Atoi(c.Query("count"))
+	timezone := c.Query("timezone")
+	start := time.Now().UTC().Truncate(time.Second)
+	if value := c.Query("start"); value != "" {
+		loc, err := utils.LoadTimezone(timezone)
+		if err != nil {
+			loc = time.UTC
+		}
+		parsed, err := utils.ParseDueDate(value, loc)
+		if err != nil {
+			err := errors.BadRequest.Wrap(err, "invalid start")
+			err = errors.SetCustomMessage(err, "Invalid query")
+			responses.HandleError(c, errors.AddErrorContext(err, "start", "Start has to be a date (2006-01-02) or a date time (2006-01-02T15:04)."))
+			return
+		}
+		start = parsed
+	}
+	occurrences, err := cc.TodoService.PreviewOccurrences(c.Query("rrule"), start, start, timezone, count)
+	if err != nil {
+		cc.logger.Zap.Error("Error [PreviewOccurrences] [PreviewOccurrences]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, occurrences)
+}
+
+// DeleteOneTodo -> Delete One Todo By Id, requires If-Match with the ETag of the todo
 func (cc TodoController) DeleteOneTodo(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
 	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
-	err := cc.TodoService.DeleteOneTodo(ID)
+	version, err := ifMatch(c)
+	if err != nil {
+		responses.HandleError(c, err)
+		return
+	}
+	err = cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).DeleteOneTodo(ID, version)
 
 	if err != nil {
 		cc.logger.Zap.Error("Error [DeleteOneTodo] [db DeleteOneTodo]: ", err.Error())
+		// unknown todo, not the owner or modified meanwhile
+		if t := errors.GetErrorType(err); t == errors.NotFound || t == errors.Forbidden || t == errors.PreconditionFailed {
+			responses.HandleError(c, err)
+			return
+		}
 		err := errors.InternalError.Wrap(err, "Failed to Delete Todo")
 		responses.HandleError(c, err)
 		return
@@ -120,3 +404,189 @@ func (cc TodoController) DeleteOneTodo(c *gin.Context) {
 
 	responses.SuccessJSON(c, http.StatusOK, "Todo Deleted Sucessfully")
 }
+
+// AssignTodo -> Assign the todo to a user of the organization, `{"assignee_id": null}` unassigns it
+func (cc TodoController) AssignTodo(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	reqData := struct {
+		AssigneeID *int64 `json:"assignee_id"`
+	}{}
+
+	if err := c.ShouldBindJSON(&reqData); err != nil {
+		cc.logger.Zap.Error("Error [AssignTodo] (ShouldBindJson) : ", err)
+		err := errors.BadRequest.Wrap(err, "Failed to bind assignee")
+		responses.HandleError(c, err)
+		return
+	}
+	err := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).AssignTodo(ID, reqData.AssigneeID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [AssignTodo] [AssignTodo]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Todo Assigned Sucessfully")
+}
+
+// GetCollaborators -> Get the users the todo is shared with
+func (cc TodoController) GetCollaborators(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	collaborators, err := cc.TodoService.WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).GetCollaborators(ID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [GetCollaborators] [GetCollaborators]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, collaborators)
+}
+
+// SetCollaborator -> Share the todo with a user, `{"user_id": 2, "permission": "view|edit"}`
+func (cc TodoController) SetCollaborator(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	reqData := struct {
+		UserID     int64  `json:"user_id"`
+		Permission string `json:"permission"`
+	}{}
+
+	if err := c.ShouldBindJSON(&reqData); err != nil {
+		cc.logger.Zap.Error("Error [SetCollaborator] (ShouldBindJson) : ", err)
+		err := errors.BadRequest.Wrap(err, "Failed to bind collaborator")
+		responses.HandleError(c, err)
+		return
+	}
+	err := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).SetCollaborator(ID, reqData.UserID, reqData.Permission)
+	if err != nil {
+		cc.logger.Zap.Error("Error [SetCollaborator] [SetCollaborator]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Todo Shared Sucessfully")
+}
+
+// RemoveCollaborator -> Stop sharing the todo with a user
+func (cc TodoController) RemoveCollaborator(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	userID, _ := strconv.ParseInt(c.Param("userId"), 10, 64)
+	err := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).RemoveCollaborator(ID, userID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [RemoveCollaborator] [RemoveCollaborator]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Collaborator Removed Sucessfully")
+}
+
+// shareURL -> client url opening the share link
+func (cc TodoController) shareURL(share *models.TodoShare) {
+	share.URL = cc.env.ClientURL + "/todo/shared/" + share.Token
+}
+
+// CreateShare -> Create a share link, `{"permission": "view|edit", "expires_in": 3600}` (seconds, 0 never expires)
+func (cc TodoController) CreateShare(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	reqData := struct {
+		Permission string `json:"permission"`
+		ExpiresIn  int64  `json:"expires_in"`
+	}{}
+
+	if err := c.ShouldBindJSON(&reqData); err != nil {
+		cc.logger.Zap.Error("Error [CreateShare] (ShouldBindJson) : ", err)
+		err := errors.BadRequest.Wrap(err, "Failed to bind share")
+		responses.HandleError(c, err)
+		return
+	}
+	share, err := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).CreateShare(ID, reqData.Permission, time.Duration(reqData.ExpiresIn)*time.Second)
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateShare] [CreateShare]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	cc.shareURL(&share)
+	responses.JSON(c, http.StatusOK, share)
+}
+
+// GetShares -> Get the share links of the todo
+func (cc TodoController) GetShares(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	shares, err := cc.TodoService.WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).GetShares(ID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [GetShares] [GetShares]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	for i := range shares {
+		cc.shareURL(&shares[i])
+	}
+	responses.JSON(c, http.StatusOK, shares)
+}
+
+// RevokeShare -> Revoke a share link
+func (cc TodoController) RevokeShare(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	shareID, _ := strconv.ParseInt(c.Param("shareId"), 10, 64)
+	err := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).RevokeShare(ID, shareID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [RevokeShare] [RevokeShare]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Share Link Revoked Sucessfully")
+}
+
+// JoinShare -> Become a collaborator of the todo through a share link
+func (cc TodoController) JoinShare(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	todo, err := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).JoinShare(c.Param("token"))
+	if err != nil {
+		cc.logger.Zap.Error("Error [JoinShare] [JoinShare]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, todo)
+}
+
+// BulkTodo -> Create, update and delete many todos in a single transaction
+// `{"atomic": false, "operations": [{"op": "update", "id": 1, "data": {"is_completed": true}}]}`
+func (cc TodoController) BulkTodo(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	request := services.BulkRequest{}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		cc.logger.Zap.Error("Error [BulkTodo] (ShouldBindJson) : ", err)
+		err := errors.BadRequest.Wrap(err, "Failed to bind bulk request")
+		responses.HandleError(c, err)
+		return
+	}
+	if err := cc.bulkService.Validate(request); err != nil {
+		responses.HandleError(c, err)
+		return
+	}
+
+	result := cc.bulkService.Run(trx, request, func(trx *gorm.DB, operation services.BulkOperation) (interface{}, error) {
+		service := cc.TodoService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role))
+		if operation.Op == services.BulkOpDelete {
+			return nil, service.DeleteOneTodo(operation.ID, operation.Version)
+		}
+		todo := models.Todo{}
+		if err := json.Unmarshal(operation.Data, &todo); err != nil {
+			return nil, errors.BadRequest.Wrap(err, "Failed to bind Todo")
+		}
+		todo.OrganizationID = nil
+		todo.Version = 0
+		if operation.Op == services.BulkOpCreate {
+			return service.CreateTodo(todo)
+		}
+		todo.ID = operation.ID
+		todo.Version = operation.Version
+		next, err := service.UpdateOneTodo(todo)
+		if next != nil {
+			return gin.H{"next_occurrence": next}, err
+		}
+		return nil, err
+	})
+	bulkResponse(c, result)
+}
@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"boilerplate-api/api/validators"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TodoListController -> struct
+type TodoListController struct {
+	logger          infrastructure.Logger
+	todoListService services.TodoListService
+	validator       validators.TodoListValidator
+}
+
+// NewTodoListController -> constructor
+func NewTodoListController(
+	logger infrastructure.Logger,
+	todoListService services.TodoListService,
+	validator validators.TodoListValidator,
+) TodoListController {
+	return TodoListController{
+		logger:          logger,
+		todoListService: todoListService,
+		validator:       validator,
+	}
+}
+
+// bindTodoList -> binds and validates the todo list from the request body
+func (cc TodoListController) bindTodoList(c *gin.Context) (models.TodoList, error) {
+	list := models.TodoList{}
+	if err := c.ShouldBindJSON(&list); err != nil {
+		return list, errors.BadRequest.Wrap(err, "Failed to bind todo list")
+	}
+	if validationErr := cc.validator.Validate.Struct(list); validationErr != nil {
+		err := errors.BadRequest.Wrap(validationErr, "Validation error")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return list, errors.AddErrorContextBlock(err, cc.validator.GenerateValidationResponse(validationErr))
+	}
+	return list, nil
+}
+
+// CreateTodoList -> Create TodoList
+func (cc TodoListController) CreateTodoList(c *gin.Context) {
+	list, err := cc.bindTodoList(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateTodoList] (bindTodoList) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
+
+	list, err = cc.todoListService.WithTenant(c.GetInt64(constants.TenantID)).CreateTodoList(list)
+	if err != nil {
+		cc.logger.Zap.Error("Error [CreateTodoList] [db CreateTodoList]: ", err.Error())
+		err := errors.BadRequest.Wrap(err, "Failed To Create Todo List")
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.JSON(c, http.StatusOK, list)
+}
+
+// GetAllTodoList -> Get All TodoList
+func (cc TodoListController) GetAllTodoList(c *gin.Context) {
+	pagination := utils.BuildPagination(c)
+	lists, count, err := cc.todoListService.WithTenant(c.GetInt64(constants.TenantID)).GetAllTodoList(pagination)
+	if err != nil {
+		cc.logger.Zap.Error("Error finding TodoList records", err.Error())
+		// invalid sort or filter query
+		if errors.GetErrorType(err) == errors.BadRequest {
+			responses.HandleError(c, err)
+			return
+		}
+		err := errors.InternalError.Wrap(err, "Failed To Find Todo List")
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSONCount(c, http.StatusOK, lists, count)
+}
+
+// GetOneTodoList -> Get One TodoList
+func (cc TodoListController) GetOneTodoList(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	list, err := cc.todoListService.WithTenant(c.GetInt64(constants.TenantID)).GetOneTodoList(ID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [GetOneTodoList] [db GetOneTodoList]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, list)
+}
+
+// UpdateOneTodoList -> Update One TodoList By Id
+func (cc TodoListController) UpdateOneTodoList(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	list, err := cc.bindTodoList(c)
+	if err != nil {
+		cc.logger.Zap.Error("Error [UpdateTodoList] (bindTodoList) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
+	list.ID = ID
+
+	if err := cc.todoListService.WithTenant(c.GetInt64(constants.TenantID)).UpdateOneTodoList(list); err != nil {
+		cc.logger.Zap.Error("Error [UpdateTodoList] [db UpdateTodoList]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.SuccessJSON(c, http.StatusOK, "Todo List Updated Sucessfully")
+}
+
+// DeleteOneTodoList -> Delete One TodoList By Id
+func (cc TodoListController) DeleteOneTodoList(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.todoListService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).DeleteOneTodoList(ID); err != nil {
+		cc.logger.Zap.Error("Error [DeleteOneTodoList] [db DeleteOneTodoList]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+
+	responses.SuccessJSON(c, http.StatusOK, "Todo List Deleted Sucessfully")
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashController -> struct
+type TrashController struct {
+	logger       infrastructure.Logger
+	env          infrastructure.Env
+	trashService services.TrashService
+}
+
+// NewTrashController -> constructor
+func NewTrashController(
+	logger infrastructure.Logger,
+	env infrastructure.Env,
+	trashService services.TrashService,
+) TrashController {
+	return TrashController{
+		logger:       logger,
+		env:          env,
+		trashService: trashService,
+	}
+}
+
+// GetDeletedUsers -> Get the soft deleted users of the organization
+func (cc TrashController) GetDeletedUsers(c *gin.Context) {
+	pagination := utils.BuildPagination(c)
+	if err := pagination.DecodeCursor(cc.env.PaginationCursorSecret); err != nil {
+		cc.logger.Zap.Error("Error [GetDeletedUsers] (DecodeCursor) : ", err)
+		err := errors.BadRequest.Wrap(err, "Invalid cursor")
+		responses.HandleError(c, err)
+		return
+	}
+	users, page, err := cc.trashService.WithTenant(c.GetInt64(constants.TenantID)).GetDeletedUsers(pagination)
+	if err != nil {
+		cc.logger.Zap.Error("Error finding deleted user records", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed to get deleted users")
+		responses.HandleError(c, err)
+		return
+	}
+	next, prev := page.EncodeCursors(cc.env.PaginationCursorSecret)
+	responses.JSONPage(c, http.StatusOK, users, page.Count, next, prev)
+}
+
+// RestoreUser -> Restore a soft deleted user and enable the firebase account
+func (cc TrashController) RestoreUser(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	user, err := cc.trashService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).RestoreUser(ID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [RestoreUser] [RestoreUser]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, user.ToMap())
+}
+
+// PurgeUser -> Permanently delete a soft deleted user along with the firebase account
+func (cc TrashController) PurgeUser(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.trashService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).PurgeUser(ID); err != nil {
+		cc.logger.Zap.Error("Error [PurgeUser] [PurgeUser]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "User Purged Sucessfully")
+}
+
+// GetDeletedTodos -> Get the soft deleted todos of the organization
+func (cc TrashController) GetDeletedTodos(c *gin.Context) {
+	pagination := utils.BuildPagination(c)
+	if err := pagination.DecodeCursor(cc.env.PaginationCursorSecret); err != nil {
+		cc.logger.Zap.Error("Error [GetDeletedTodos] (DecodeCursor) : ", err)
+		err := errors.BadRequest.Wrap(err, "Invalid cursor")
+		responses.HandleError(c, err)
+		return
+	}
+	todos, page, err := cc.trashService.WithTenant(c.GetInt64(constants.TenantID)).GetDeletedTodos(pagination)
+	if err != nil {
+		cc.logger.Zap.Error("Error finding deleted Todo records", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed to get deleted todos")
+		responses.HandleError(c, err)
+		return
+	}
+	next, prev := page.EncodeCursors(cc.env.PaginationCursorSecret)
+	responses.JSONPage(c, http.StatusOK, todos, page.Count, next, prev)
+}
+
+// RestoreTodo -> Restore a soft deleted todo
+func (cc TrashController) RestoreTodo(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.trashService.WithTenant(c.GetInt64(constants.TenantID)).RestoreTodo(ID); err != nil {
+		cc.logger.Zap.Error("Error [RestoreTodo] [RestoreTodo]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Todo Restored Sucessfully")
+}
+
+// PurgeTodo -> Permanently delete a soft deleted todo
+func (cc TrashController) PurgeTodo(c *gin.Context) {
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err := cc.trashService.WithTenant(c.GetInt64(constants.TenantID)).PurgeTodo(ID); err != nil {
+		cc.logger.Zap.Error("Error [PurgeTodo] [PurgeTodo]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, "Todo Purged Sucessfully")
+}
@@ -1,7 +1,6 @@
 package controllers
 
 import (
-	"boilerplate-api/api/middlewares"
 	"boilerplate-api/api/responses"
 	"boilerplate-api/api/services"
 	"boilerplate-api/api/validators"
@@ -10,12 +9,11 @@ import (
 	"boilerplate-api/infrastructure"
 	"boilerplate-api/models"
 	"boilerplate-api/utils"
-	"fmt"
+	"encoding/json"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v4"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
@@ -27,6 +25,10 @@ type UserController struct {
 	env             infrastructure.Env
 	validator       validators.UserValidator
 	firebaseService services.FirebaseService
+	dpopService     services.DPoPService
+	jwtService      services.JWTAuthService
+	bulkService     services.BulkService
+	importService   services.UserImportService
 }
 
 // NewUserController -> constructor
@@ -36,6 +38,10 @@ func NewUserController(
 	env infrastructure.Env,
 	validator validators.UserValidator,
 	firebaseService services.FirebaseService,
+	dpopService services.DPoPService,
+	jwtService services.JWTAuthService,
+	bulkService services.BulkService,
+	importService services.UserImportService,
 ) UserController {
 	return UserController{
 		logger:          logger,
@@ -43,6 +49,10 @@ func NewUserController(
 		env:             env,
 		validator:       validator,
 		firebaseService: firebaseService,
+		dpopService:     dpopService,
+		jwtService:      jwtService,
+		bulkService:     bulkService,
+		importService:   importService,
 	}
 }
 
@@ -59,6 +69,8 @@ func (cc UserController) CreateUser(c *gin.Context) {
 		responses.HandleError(c, err)
 		return
 	}
+	reqData.User.OrganizationID = nil
+	reqData.User.Memberships = nil
 	if reqData.User.Password != reqData.ConfirmPassword {
 		cc.logger.Zap.Error("Password and confirm password not matching : ")
 		responses.ErrorJSON(c, http.StatusBadRequest, "Password and confirm password should be same.")
@@ -78,14 +90,24 @@ func (cc UserController) CreateUser(c *gin.Context) {
 		responses.ErrorJSON(c, http.StatusBadRequest, "Invalid Email")
 		return
 	}
+	if err := services.ValidateRole(reqData.User.Role, c.GetString(constants.Role)); err != nil {
+		cc.logger.Zap.Error("Error [CreateUser] (ValidateRole) : ", err)
+		responses.HandleError(c, err)
+		return
+	}
 	password, err := bcrypt.GenerateFromPassword([]byte(reqData.ConfirmPassword), 10)
 	reqData.User.Password = string(password)
 	if err != nil {
 		responses.ErrorJSON(c, http.StatusInternalServerError, "Failed top create hash pw")
 		return
 	}
-	if _, err := cc.userService.WithTrx(trx).CreateUser(&reqData.User); err != nil {
+	if _, err := cc.userService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).CreateUser(&reqData.User); err != nil {
 		cc.logger.Zap.Error("Error [CreateUser] [db CreateUser]: ", err.Error())
+		// email, username or phone taken
+		if errors.GetErrorType(err) == errors.Conflict {
+			responses.HandleError(c, err)
+			return
+		}
 		err := errors.InternalError.Wrap(err, "Failed to create user")
 		responses.HandleError(c, err)
 		return
@@ -97,50 +119,93 @@ func (cc UserController) CreateUser(c *gin.Context) {
 // GetAllUser -> Get All User
 func (cc UserController) GetAllUsers(c *gin.Context) {
 	pagination := utils.BuildPagination(c)
-	users, count, err := cc.userService.GetAllUsers(pagination)
+	if err := pagination.DecodeCursor(cc.env.PaginationCursorSecret); err != nil {
+		cc.logger.Zap.Error("Error [GetAllUsers] (DecodeCursor) : ", err)
+		err := errors.BadRequest.Wrap(err, "Invalid cursor")
+		responses.HandleError(c, err)
+		return
+	}
+	fieldset := utils.BuildFieldset(c)
+	users, page, err := cc.userService.WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).WithFieldset(fieldset).GetAllUsers(pagination)
 	if err != nil {
 		cc.logger.Zap.Error("Error finding user records", err.Error())
+		// invalid sort, filter or fields query
+		if errors.GetErrorType(err) == errors.BadRequest {
+			responses.HandleError(c, err)
+			return
+		}
 		err := errors.InternalError.Wrap(err, "Failed to get users data")
 		responses.HandleError(c, err)
 		return
 	}
-	responses.JSONCount(c, http.StatusOK, users, count)
+	next, prev := page.EncodeCursors(cc.env.PaginationCursorSecret)
+	responses.JSONPage(c, http.StatusOK, responses.Sparse(users, fieldset), page.Count, next, prev)
 }
 
 func (cc UserController) GetOneUser(c *gin.Context) {
-	id := c.Param("id")
-	user, err := cc.userService.GetOneUser(id)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	fieldset := utils.BuildFieldset(c)
+	user, err := cc.userService.WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).WithFieldset(fieldset).GetOneUser(ID)
 	if err != nil {
 		cc.logger.Zap.Error("Error finding user records", err.Error())
+		// invalid fields query
+		if errors.GetErrorType(err) == errors.BadRequest {
+			responses.HandleError(c, err)
+			return
+		}
 		err := errors.InternalError.Wrap(err, "Failed to get users data")
 		responses.HandleError(c, err)
 		return
 	}
-	responses.SuccessJSON(c, http.StatusOK, user.ToMap())
-	return
+	if notModified(c, user.Version) {
+		return
+	}
+	responses.SuccessJSON(c, http.StatusOK, responses.Sparse(user, fieldset))
 }
 
+// DeleteOneUser -> Delete One User By Id, requires If-Match with the ETag of the user
 func (cc UserController) DeleteOneUser(c *gin.Context) {
 	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
-	f_uid, err := cc.userService.WithTrx(trx).DeleteOneUser(c.Param("id"))
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	version, err := ifMatch(c)
 	if err != nil {
-		cc.logger.Zap.Error("Error Deleting user record", err.Error())
-		err := errors.InternalError.Wrap(err, "Failed to delete user data")
 		responses.HandleError(c, err)
 		return
 	}
-	if err := cc.firebaseService.DeleteUser(*f_uid); err != nil {
-		cc.logger.Zap.Error("Error Deleting user record from firebase", err.Error())
-		err := errors.InternalError.Wrap(err, "Failed to delete user data from firebase")
+	f_uid, err := cc.userService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).DeleteOneUser(ID, version)
+	if err != nil {
+		cc.logger.Zap.Error("Error Deleting user record", err.Error())
+		// modified since the client fetched it
+		if errors.GetErrorType(err) == errors.PreconditionFailed {
+			responses.HandleError(c, err)
+			return
+		}
+		err := errors.InternalError.Wrap(err, "Failed to delete user data")
 		responses.HandleError(c, err)
 		return
 	}
+	// the firebase account is only disabled so the user can be restored from the trash
+	if *f_uid != "" {
+		if err := cc.firebaseService.DisableUser(*f_uid, true); err != nil {
+			cc.logger.Zap.Error("Error Disabling user record in firebase", err.Error())
+			err := errors.InternalError.Wrap(err, "Failed to disable user in firebase")
+			responses.HandleError(c, err)
+			return
+		}
+	}
 	responses.SuccessJSON(c, http.StatusOK, "User deleted successfully")
 	return
 }
 
+// UpdateUser -> Update One User By Id, requires If-Match with the ETag of the user
 func (cc UserController) UpdateUser(c *gin.Context) {
 	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	version, err := ifMatch(c)
+	if err != nil {
+		responses.HandleError(c, err)
+		return
+	}
 	bodyData := struct {
 		Username string `json:"username" validate:"required"`
 		FullName string `json:"full_name" validate:"required"`
@@ -181,9 +246,14 @@ func (cc UserController) UpdateUser(c *gin.Context) {
 		"full_name": bodyData.FullName,
 		"address":   bodyData.Address,
 	}
-	user, err := cc.userService.WithTrx(trx).UpdateUser(c.Param("id"), bodyDataMap)
+	user, err := cc.userService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role)).UpdateUser(ID, bodyDataMap, version)
 	if err != nil {
 		cc.logger.Zap.Error("Error [UpdateUser] [db UpdateUser]: ", err.Error())
+		// modified since the client fetched it, or email or phone taken
+		if t := errors.GetErrorType(err); t == errors.PreconditionFailed || t == errors.Conflict {
+			responses.HandleError(c, err)
+			return
+		}
 		err := errors.InternalError.Wrap(err, "Failed to update user")
 		responses.HandleError(c, err)
 		return
@@ -194,19 +264,126 @@ func (cc UserController) UpdateUser(c *gin.Context) {
 	userToUpdate.Username = user.Username
 	userToUpdate.Address = user.Address
 	userToUpdate.Phone = user.Phone
-	updateFirebaseUser, err := cc.firebaseService.UpdateUser(user.FirebaseUID, userToUpdate)
+	_, err = cc.firebaseService.UpdateUser(user.FirebaseUID, userToUpdate)
 	if err != nil {
 		cc.logger.Zap.Error("Error [UpdateUser] [db UpdateUser]: ", err.Error())
 		err := errors.InternalError.Wrap(err, "Failed to update user")
 		responses.HandleError(c, err)
 		return
 	}
-	fmt.Println(updateFirebaseUser)
+	c.Header("ETag", utils.ETag(user.Version))
 	responses.SuccessJSON(c, http.StatusOK, user)
 	return
 
 }
 
+// userPatch -> fields of a user PATCH can change, the patched user has to pass the same rules as UpdateUser
+type userPatch struct {
+	Username string `json:"username" validate:"required"`
+	FullName string `json:"full_name" validate:"required"`
+	Email    string `json:"email" validate:"required"`
+	Phone    string `json:"phone" validate:"required"`
+	Address  string `json:"address" validate:"required"`
+}
+
+// PatchUser -> Partially update One User By Id with a merge patch or JSON Patch, requires If-Match with the ETag of the user
+// `Content-Type: application/merge-patch+json` `{"phone": "9800000000"}`
+// `Content-Type: application/json-patch+json` `[{"op": "replace", "path": "/phone", "value": "9800000000"}]`
+func (cc UserController) PatchUser(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	ID, _ := strconv.ParseInt(c.Param("id"), 10, 64)
+	version, err := ifMatch(c)
+	if err != nil {
+		responses.HandleError(c, err)
+		return
+	}
+	service := cc.userService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role))
+	user, err := service.GetOneUser(ID)
+	if err != nil {
+		cc.logger.Zap.Error("Error [PatchUser] [db GetOneUser]: ", err.Error())
+		if err == gorm.ErrRecordNotFound {
+			err = errors.NotFound.Wrap(err, "user not found")
+			responses.HandleError(c, errors.SetCustomMessage(err, "User not found"))
+			return
+		}
+		responses.HandleError(c, errors.InternalError.Wrap(err, "Failed to get users data"))
+		return
+	}
+
+	patch := userPatch{
+		Username: user.Username,
+		FullName: user.FullName,
+		Email:    user.Email,
+		Phone:    user.Phone,
+		Address:  user.Address,
+	}
+	changed, err := patchDocument(c, &patch)
+	if err != nil {
+		cc.logger.Zap.Error("Error [PatchUser] (patchDocument) : ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	if validatedError := cc.validator.Validate.Struct(&patch); validatedError != nil {
+		err := errors.BadRequest.Wrap(validatedError, "Validation error")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		err = errors.AddErrorContextBlock(err, cc.validator.GenerateValidationResponse(validatedError))
+		responses.HandleError(c, err)
+		return
+	}
+	if _, ok := changed["email"]; ok {
+		if !utils.IsValidEmail(patch.Email) {
+			err := errors.BadRequest.New("Invalid email")
+			err = errors.SetCustomMessage(err, "Invalid input information")
+			responses.HandleError(c, errors.AddErrorContext(err, "email", "Field 'email' is not valid."))
+			return
+		}
+		if cc.firebaseService.GetUserByEmail(patch.Email) != "" {
+			err := errors.BadRequest.New("Firebase user already exists")
+			err = errors.SetCustomMessage(err, "Email address already taken")
+			responses.HandleError(c, err)
+			return
+		}
+	}
+
+	values := map[string]interface{}{
+		"username":  patch.Username,
+		"full_name": patch.FullName,
+		"email":     patch.Email,
+		"phone":     patch.Phone,
+		"address":   patch.Address,
+	}
+	updates := map[string]interface{}{}
+	for field := range changed {
+		updates[field] = values[field]
+	}
+	user, err = service.UpdateUser(ID, updates, version)
+	if err != nil {
+		cc.logger.Zap.Error("Error [PatchUser] [db UpdateUser]: ", err.Error())
+		// modified since the client fetched it, or email or phone taken
+		if t := errors.GetErrorType(err); t == errors.PreconditionFailed || t == errors.BadRequest || t == errors.Conflict {
+			responses.HandleError(c, err)
+			return
+		}
+		responses.HandleError(c, errors.InternalError.Wrap(err, "Failed to update user"))
+		return
+	}
+	if len(updates) > 0 && user.FirebaseUID != "" {
+		if _, err := cc.firebaseService.UpdateUser(user.FirebaseUID, models.UserToUpdate{
+			Email:    user.Email,
+			Username: user.Username,
+			FullName: user.FullName,
+			Phone:    user.Phone,
+			Address:  user.Address,
+		}); err != nil {
+			cc.logger.Zap.Error("Error [PatchUser] [firebase UpdateUser]: ", err.Error())
+			responses.HandleError(c, errors.InternalError.Wrap(err, "Failed to update user"))
+			return
+		}
+	}
+	c.Header("ETag", utils.ETag(user.Version))
+	responses.SuccessJSON(c, http.StatusOK, user)
+}
+
 func (cc UserController) LoginUser(c *gin.Context) {
 	var reqData struct {
 		Email    string `json:"email" binding:"required"`
@@ -231,28 +408,178 @@ func (cc UserController) LoginUser(c *gin.Context) {
 		responses.ErrorJSON(c, http.StatusBadRequest, "Invalid user credentials2")
 		return
 	}
-	userId := fmt.Sprintf("%v", user.ID)
+	// Create the access token claims from the configured claim mappings
+	claims := cc.jwtService.BuildClaims(*user)
 
-	// Create a new JWT claims object
-	claims := middlewares.JWTClaims{
-		Role: user.Role,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour).Unix(),
-			Subject:   userId,
-		},
+	// Bind the token to the client's key when a DPoP proof is presented
+	tokenType := "Bearer"
+	if proof := c.GetHeader("DPoP"); proof != "" {
+		jkt, err := cc.dpopService.VerifyProof(services.DPoPProof{
+			Proof:  proof,
+			Method: c.Request.Method,
+			URL:    utils.RequestURL(c),
+		})
+		if err != nil {
+			cc.logger.Zap.Error("Error [LoginUser] [VerifyProof]: ", err.Error())
+			err := errors.BadRequest.Wrap(err, "Invalid DPoP proof")
+			err = errors.SetCustomMessage(err, "Invalid DPoP proof")
+			responses.HandleError(c, err)
+			return
+		}
+		claims.Confirmation = &services.JWTConfirmation{JKT: jkt}
+		tokenType = "DPoP"
 	}
 
 	// Create a new JWT token using the claims and the secret key
-	tokenClaim := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	token, tokenErr := tokenClaim.SignedString([]byte(cc.env.JWT_SECRET))
+	token, tokenErr := cc.jwtService.SignToken(claims)
 	if tokenErr != nil {
 		responses.ErrorJSON(c, http.StatusInternalServerError, tokenErr.Error())
 		return
 	}
 	data := map[string]interface{}{
-		"user":  user.ToMap(),
-		"token": token,
+		"user":       user.ToMap(),
+		"token":      token,
+		"token_type": tokenType,
 	}
 	responses.SuccessJSON(c, http.StatusOK, data)
 	return
 }
+
+// bulkUserUpdate -> fields a bulk operation can change, disabled toggles the firebase account
+type bulkUserUpdate struct {
+	Username *string `json:"username"`
+	FullName *string `json:"full_name"`
+	Phone    *string `json:"phone"`
+	Address  *string `json:"address"`
+	Disabled *bool   `json:"disabled"`
+}
+
+// BulkUser -> Create, update and delete many users of the organization in a single transaction
+// `{"atomic": false, "operations": [{"op": "update", "id": 1, "data": {"disabled": true}}]}`
+func (cc UserController) BulkUser(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	request := services.BulkRequest{}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		cc.logger.Zap.Error("Error [BulkUser] (ShouldBindJson) : ", err)
+		err := errors.BadRequest.Wrap(err, "Failed to bind bulk request")
+		responses.HandleError(c, err)
+		return
+	}
+	if err := cc.bulkService.Validate(request); err != nil {
+		responses.HandleError(c, err)
+		return
+	}
+
+	result := cc.bulkService.Run(trx, request, func(trx *gorm.DB, operation services.BulkOperation) (interface{}, error) {
+		service := cc.userService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).WithViewer(c.GetInt64(constants.UserID), c.GetString(constants.Role))
+		switch operation.Op {
+		case services.BulkOpCreate:
+			return cc.bulkCreateUser(service, operation.Data, c.GetString(constants.Role))
+		case services.BulkOpUpdate:
+			return cc.bulkUpdateUser(service, operation)
+		}
+		return nil, cc.bulkDeleteUser(service, operation)
+	})
+	bulkResponse(c, result)
+}
+
+// bulkCreateUser -> validates and creates a user of a bulk operation, only admins can create admins
+func (cc UserController) bulkCreateUser(service services.UserService, data json.RawMessage, creatorRole string) (*models.User, error) {
+	reqData := struct {
+		models.User
+		Password string `json:"password"`
+	}{}
+	if err := json.Unmarshal(data, &reqData); err != nil {
+		return nil, errors.BadRequest.Wrap(err, "Failed to bind user data")
+	}
+	user := reqData.User
+	user.Password = reqData.Password
+	user.OrganizationID = nil
+	user.Memberships = nil
+	if validationErr := cc.validator.Validate.Struct(user); validationErr != nil {
+		err := errors.BadRequest.Wrap(validationErr, "Validation error")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return nil, errors.AddErrorContextBlock(err, cc.validator.GenerateValidationResponse(validationErr))
+	}
+	if !utils.IsValidEmail(user.Email) {
+		err := errors.BadRequest.New("Invalid email")
+		return nil, errors.SetCustomMessage(err, "Invalid Email")
+	}
+	if err := services.ValidateRole(user.Role, creatorRole); err != nil {
+		return nil, err
+	}
+	password, err := bcrypt.GenerateFromPassword([]byte(user.Password), 10)
+	if err != nil {
+		return nil, errors.InternalError.Wrap(err, "Failed to create password hash")
+	}
+	user.Password = string(password)
+	return service.CreateUser(&user)
+}
+
+// bulkUpdateUser -> applies the given fields of a bulk operation and syncs the firebase account
+// every operation bumps the version, so the version is checked even when only disabled is given
+func (cc UserController) bulkUpdateUser(service services.UserService, operation services.BulkOperation) (*models.User, error) {
+	ID := operation.ID
+	reqData := bulkUserUpdate{}
+	if err := json.Unmarshal(operation.Data, &reqData); err != nil {
+		return nil, errors.BadRequest.Wrap(err, "Failed to bind user data")
+	}
+	if _, err := service.GetOneUser(ID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NotFound.Wrap(err, "user not found")
+		}
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	for column, value := range map[string]*string{
+		"username":  reqData.Username,
+		"full_name": reqData.FullName,
+		"phone":     reqData.Phone,
+		"address":   reqData.Address,
+	} {
+		if value != nil {
+			updates[column] = *value
+		}
+	}
+	user, err := service.UpdateUser(ID, updates, operation.Version)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) > 0 && user.FirebaseUID != "" {
+		if _, err := cc.firebaseService.UpdateUser(user.FirebaseUID, models.UserToUpdate{
+			Email:    user.Email,
+			FullName: user.FullName,
+			Username: user.Username,
+			Address:  user.Address,
+			Phone:    user.Phone,
+		}); err != nil {
+			return nil, errors.InternalError.Wrap(err, "Failed to update user in firebase")
+		}
+	}
+	if reqData.Disabled != nil && user.FirebaseUID != "" {
+		if err := cc.firebaseService.DisableUser(user.FirebaseUID, *reqData.Disabled); err != nil {
+			return nil, errors.InternalError.Wrap(err, "Failed to disable user in firebase")
+		}
+	}
+	return user, nil
+}
+
+// bulkDeleteUser -> deletes the user of a bulk operation and disables the firebase account
+func (cc UserController) bulkDeleteUser(service services.UserService, operation services.BulkOperation) error {
+	firebaseUID, err := service.DeleteOneUser(operation.ID, operation.Version)
+	if err == gorm.ErrRecordNotFound {
+		return errors.NotFound.Wrap(err, "user not found")
+	}
+	if err != nil {
+		return err
+	}
+	if *firebaseUID == "" {
+		return nil
+	}
+	if err := cc.firebaseService.DisableUser(*firebaseUID, true); err != nil {
+		return errors.InternalError.Wrap(err, "Failed to disable user in firebase")
+	}
+	return nil
+}
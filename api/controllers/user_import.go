@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// userExportBatchSize -> users fetched at once while exporting
+const userExportBatchSize = 500
+
+// ImportUsers -> Create or invite the users of a csv or xlsx file (form field `file`)
+// rows with a password are created, rows without one are invited, every row is reported on
+func (cc UserController) ImportUsers(c *gin.Context) {
+	trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+	file, fileHeader, err := c.Request.FormFile("file")
+	if err != nil {
+		cc.logger.Zap.Error("Error Get File from request :: ", err.Error())
+		err := errors.BadRequest.Wrap(err, "Failed to get file form request")
+		responses.HandleError(c, err)
+		return
+	}
+	defer file.Close()
+
+	format := utils.SpreadsheetFormat(fileHeader.Filename)
+	if format == "" {
+		err := errors.BadRequest.Newf("unsupported import file %s", fileHeader.Filename)
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		responses.HandleError(c, errors.AddErrorContext(err, "file", "File has to be a csv or xlsx file."))
+		return
+	}
+	rows, err := utils.ReadSpreadsheet(file, format)
+	if err != nil {
+		cc.logger.Zap.Error("Error [ImportUsers] (ReadSpreadsheet) : ", err)
+		err := errors.BadRequest.Wrap(err, "Failed to read import file")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		responses.HandleError(c, errors.AddErrorContext(err, "file", "File could not be read as "+format+"."))
+		return
+	}
+
+	importer, err := cc.userService.GetOneUser(c.GetInt64(constants.UserID))
+	if err != nil {
+		cc.logger.Zap.Error("Error [ImportUsers] [db GetOneUser]: ", err.Error())
+		err := errors.InternalError.Wrap(err, "Failed to get users data")
+		responses.HandleError(c, err)
+		return
+	}
+
+	result, err := cc.importService.WithTrx(trx).WithTenant(c.GetInt64(constants.TenantID)).Import(rows, *importer)
+	if err != nil {
+		cc.logger.Zap.Error("Error [ImportUsers] [Import]: ", err.Error())
+		responses.HandleError(c, err)
+		return
+	}
+	responses.JSON(c, http.StatusOK, result)
+}
+
+// ExportUsers -> Download the users matching the filters and keyword of GetAllUsers (`?format=csv|xlsx`, csv by default)
+func (cc UserController) ExportUsers(c *gin.Context) {
+	format := c.DefaultQuery("format", utils.SpreadsheetCSV)
+	if !utils.StringInList(format, utils.SpreadsheetFormats) {
+		err := errors.BadRequest.Newf("unsupported export format %s", format)
+		err = errors.SetCustomMessage(err, "Invalid query")
+		responses.HandleError(c, errors.AddErrorContext(err, "format", "Format has to be one of "+strings.Join(utils.SpreadsheetFormats, ", ")+"."))
+		return
+	}
+	pagination := utils.BuildPagination(c)
+
+	writer, err := utils.NewSpreadsheetWriter(c.Writer, format)
+	if err != nil {
+		cc.logger.Zap.Error("Error [ExportUsers] (NewSpreadsheetWriter) : ", err)
+		err := errors.InternalError.Wrap(err, "Failed to export users")
+		responses.HandleError(c, err)
+		return
+	}
+	// headers and the header row are written with the first batch so invalid filters can still be reported
+	started := false
+	start := func() error {
+		started = true
+		filename := "users-" + time.Now().UTC().Format("20060102-150405") + "." + format
+		c.Header("Content-Type", utils.SpreadsheetContentTypes[format])
+		c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+		return writer.Write(services.UserExportColumns)
+	}
+	err = cc.userService.WithTenant(c.GetInt64(constants.TenantID)).ExportUsers(pagination, userExportBatchSize, func(users []models.User) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		for _, user := range users {
+			if err := writer.Write(userExportRow(user)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil && !started {
+		cc.logger.Zap.Error("Error [ExportUsers] [db ExportUsers]: ", err.Error())
+		if errors.GetErrorType(err) == errors.BadRequest {
+			responses.HandleError(c, err)
+			return
+		}
+		err := errors.InternalError.Wrap(err, "Failed to export users")
+		responses.HandleError(c, err)
+		return
+	}
+	if err != nil {
+		cc.logger.Zap.Error("Error [ExportUsers] export aborted: ", err.Error())
+		return
+	}
+	if !started {
+		if err := start(); err != nil {
+			cc.logger.Zap.Error("Error [ExportUsers] (Write) : ", err)
+			return
+		}
+	}
+	if err := writer.Close(); err != nil {
+		cc.logger.Zap.Error("Error [ExportUsers] (Close) : ", err)
+	}
+}
+
+// userExportRow -> user as a row of UserExportColumns
+func userExportRow(user models.User) []string {
+	return []string{
+		strconv.FormatInt(user.ID, 10),
+		user.Username,
+		user.FullName,
+		user.Email,
+		user.Phone,
+		user.Address,
+		user.Role,
+		strconv.FormatBool(user.EmailVerified),
+		strconv.FormatBool(user.PhoneVerified),
+		user.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,134 @@
+package middlewares
+
+import (
+	"boilerplate-api/api/responses"
+	"boilerplate-api/api/services"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/utils"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// IdempotencyMiddleware -> replays the stored response when a request is retried with the same Idempotency-Key
+type IdempotencyMiddleware struct {
+	logger  infrastructure.Logger
+	service services.IdempotencyService
+}
+
+// NewIdempotencyMiddleware -> new instance of idempotency middleware
+func NewIdempotencyMiddleware(
+	logger infrastructure.Logger,
+	service services.IdempotencyService,
+) IdempotencyMiddleware {
+	return IdempotencyMiddleware{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// Handle -> has to come after DBTransactionHandle, the key is stored in the transaction of the request
+// so the response is only kept when the changes it reports are committed
+// requests without Idempotency-Key are passed through
+func (m IdempotencyMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader("Idempotency-Key"))
+		if key == "" {
+			c.Next()
+			return
+		}
+		trx := c.MustGet(constants.DBTransaction).(*gorm.DB)
+
+		body, err := ioutil.ReadAll(c.Request.Body)
+		if err != nil {
+			responses.HandleError(c, errors.BadRequest.Wrap(err, "failed to read request body"))
+			c.Abort()
+			return
+		}
+		c.Request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		service := m.service.WithTrx(trx)
+		record, replay, err := service.Begin(c.GetInt64(constants.UserID), key, c.Request.Method, c.Request.URL.Path, body)
+		if err != nil {
+			m.logger.Zap.Error("Error [Idempotency] [Begin]: ", err.Error())
+			responses.HandleError(c, err)
+			c.Abort()
+			return
+		}
+		if replay {
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(record.Status, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		// the response is held back until it is stored, so a failure to store it can still be reported
+		recorder := &responseRecorder{ResponseWriter: c.Writer, status: http.StatusOK}
+		c.Writer = recorder
+		c.Next()
+		c.Writer = recorder.ResponseWriter
+
+		if utils.StatusInList(recorder.status, []int{http.StatusOK, http.StatusCreated}) {
+			if err := service.Complete(record, recorder.status, recorder.Header().Get("Content-Type"), recorder.body.Bytes()); err != nil {
+				m.logger.Zap.Error("Error [Idempotency] [Complete]: ", err.Error())
+				responses.HandleError(c, errors.InternalError.Wrap(err, "failed to store idempotent response"))
+				return
+			}
+		}
+		c.Writer.WriteHeader(recorder.status)
+		if _, err := c.Writer.Write(recorder.body.Bytes()); err != nil {
+			m.logger.Zap.Error("Error [Idempotency] writing response: ", err.Error())
+		}
+	}
+}
+
+// responseRecorder -> buffers the status and body written by the handlers, headers go to the wrapped writer
+type responseRecorder struct {
+	gin.ResponseWriter
+	status  int
+	written bool
+	body    bytes.Buffer
+}
+
+func (w *responseRecorder) WriteHeader(code int) {
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *responseRecorder) WriteHeaderNow() {
+	w.written = true
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.written = true
+	return w.body.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.written = true
+	return w.body.WriteString(s)
+}
+
+func (w *responseRecorder) Status() int {
+	return w.status
+}
+
+func (w *responseRecorder) Size() int {
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *responseRecorder) Written() bool {
+	return w.written
+}
+
+func (w *responseRecorder) Flush() {}
@@ -3,8 +3,11 @@ package middlewares
 import (
 	"boilerplate-api/api/responses"
 	"boilerplate-api/api/services"
+	"boilerplate-api/constants"
 	"boilerplate-api/errors"
 	"boilerplate-api/infrastructure"
+	"boilerplate-api/utils"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +21,9 @@ type JWTAuthMiddleWare struct {
 	env         infrastructure.Env
 	db          infrastructure.Database
 	userService services.UserService
+	dpopService services.DPoPService
+
+	organizationService services.OrganizationService
 }
 
 func NewJWTAuthMiddleWare(
@@ -26,7 +32,8 @@ func NewJWTAuthMiddleWare(
 	env infrastructure.Env,
 	db infrastructure.Database,
 	userService services.UserService,
-
+	dpopService services.DPoPService,
+	organizationService services.OrganizationService,
 ) JWTAuthMiddleWare {
 	return JWTAuthMiddleWare{
 		jwtService:  jwtService,
@@ -34,18 +41,30 @@ func NewJWTAuthMiddleWare(
 		env:         env,
 		db:          db,
 		userService: userService,
+		dpopService: dpopService,
+
+		organizationService: organizationService,
 	}
 }
 
-type JWTClaims struct {
-	Username string `json:"username"`
-	Role     string `json:"role"`
-	jwt.StandardClaims
+// GetJWTClaims -> typed access to the claims of the authenticated request
+func GetJWTClaims(c *gin.Context) (*services.JWTClaims, bool) {
+	value, ok := c.Get(constants.Claims)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*services.JWTClaims)
+	return claims, ok
 }
 
 func (m JWTAuthMiddleWare) ParseToken(tokenString string) (*jwt.Token, error) {
+	// Unwrap encrypted (JWE) tokens before verifying the inner signature
+	tokenString, err := m.jwtService.DecryptToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
 	// Parse the token using the secret key
-	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &services.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		return []byte(m.env.JWT_SECRET), nil
 	})
 	if err != nil {
@@ -57,14 +76,17 @@ func (m JWTAuthMiddleWare) ParseToken(tokenString string) (*jwt.Token, error) {
 
 func (m JWTAuthMiddleWare) verifyToken(c *gin.Context) (bool, error) {
 	// Get the token from the request header
-	header := c.GetHeader("Authorization")
-	tokenString := strings.TrimSpace(strings.Replace(header, "Bearer", "", 1))
+	header := strings.TrimSpace(c.GetHeader("Authorization"))
+	scheme, tokenString := "Bearer", header
+	if i := strings.IndexByte(header, ' '); i > 0 {
+		scheme, tokenString = header[:i], strings.TrimSpace(header[i+1:])
+	}
 	token, err := m.ParseToken(tokenString)
 	if err != nil {
 		m.logger.Zap.Error("Error parsing token", err.Error())
 		return false, err
 	}
-	claims, ok := token.Claims.(*JWTClaims)
+	claims, ok := token.Claims.(*services.JWTClaims)
 	if ok && token.Valid {
 		// check if the token expires or not
 		if float64(time.Now().Unix()) > float64(claims.ExpiresAt) {
@@ -78,20 +100,70 @@ func (m JWTAuthMiddleWare) verifyToken(c *gin.Context) (bool, error) {
 		err = errors.SetCustomMessage(err, "Invalid token")
 		return false, err
 	}
+	if err := m.verifyDPoP(c, scheme, tokenString, claims); err != nil {
+		m.logger.Zap.Error("Error verifying DPoP proof", err.Error())
+		return false, err
+	}
 	// Get user from claims and set
-	user, err := m.userService.GetOneUser(claims.Id)
+	userID, err := strconv.ParseInt(claims.Subject, 10, 64)
+	if err != nil {
+		err := errors.BadRequest.Wrap(err, "Invalid token subject")
+		err = errors.SetCustomMessage(err, "Invalid token")
+		return false, err
+	}
+	user, err := m.userService.GetOneUser(userID)
 	if err != nil {
 		m.logger.Zap.Error("Error finding user records", err.Error())
 		err := errors.InternalError.Wrap(err, "Failed to get users data")
 		m.logger.Zap.Error("error finding user record")
 		return false, err
 	}
+	// Tenant comes from the token, falling back to the user's current organization
+	tenantID := claims.Tenant
+	if tenantID == 0 && user.OrganizationID != nil {
+		tenantID = *user.OrganizationID
+	}
+	// Membership is checked on every request, within an organization the membership role applies
+	role, err := m.organizationService.ActingRole(tenantID, *user)
+	if err != nil {
+		m.logger.Zap.Error("Error verifying organization membership", err.Error())
+		return false, err
+	}
 	// Can set anything in the request context and passes the request to the next handler.
-	c.Set("user_id", user.ID)
+	c.Set(constants.UserID, user.ID)
+	c.Set(constants.Role, role)
+	c.Set(constants.Claims, claims)
+	c.Set(constants.TenantID, tenantID)
 	return true, nil
 
 }
 
+// verifyDPoP checks the DPoP proof for sender-constrained tokens
+func (m JWTAuthMiddleWare) verifyDPoP(c *gin.Context, scheme string, tokenString string, claims *services.JWTClaims) error {
+	if claims.Confirmation == nil || claims.Confirmation.JKT == "" {
+		if strings.EqualFold(scheme, "DPoP") {
+			return errors.Unauthorized.New("DPoP scheme used with unbound token")
+		}
+		return nil
+	}
+	if !strings.EqualFold(scheme, "DPoP") {
+		return errors.Unauthorized.New("DPoP bound token presented as bearer token")
+	}
+	jkt, err := m.dpopService.VerifyProof(services.DPoPProof{
+		Proof:       c.GetHeader("DPoP"),
+		Method:      c.Request.Method,
+		URL:         utils.RequestURL(c),
+		AccessToken: tokenString,
+	})
+	if err != nil {
+		return err
+	}
+	if jkt != claims.Confirmation.JKT {
+		return errors.Unauthorized.New("DPoP proof key does not match token binding")
+	}
+	return nil
+}
+
 func (m JWTAuthMiddleWare) Handle() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ok, err := m.verifyToken(c)
@@ -106,3 +178,46 @@ func (m JWTAuthMiddleWare) Handle() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// HandleAdminOnly handles middleware for privileged roles only, within an organization the membership role decides
+func (m JWTAuthMiddleWare) HandleAdminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := m.verifyToken(c)
+		if !ok {
+			m.logger.Zap.Error("Error verifying auth token")
+			err = errors.Unauthorized.Wrap(err, "Error verifying auth token")
+			err = errors.SetCustomMessage(err, "Unauthorised")
+			responses.HandleError(c, err)
+			c.Abort()
+			return
+		}
+		if !utils.StringInList(c.GetString(constants.Role), constants.RolePrivileged) {
+			err := errors.Forbidden.New("un-authorized request")
+			err = errors.SetCustomMessage(err, "Admin access required")
+			responses.HandleError(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequirePermission -> allows the request when the role of the user grants the permission
+// tokens carrying a permissions claim must list it too, so a token never grants more than it was issued with
+// runs after Handle or HandleAdminOnly
+func (m JWTAuthMiddleWare) RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		granted := utils.StringInList(permission, constants.RolePermissions[c.GetString(constants.Role)])
+		if claims, ok := GetJWTClaims(c); ok && claims.Permissions != nil {
+			granted = granted && claims.HasPermission(permission)
+		}
+		if !granted {
+			err := errors.Forbidden.Newf("missing permission %s", permission)
+			err = errors.SetCustomMessage(err, "Permission denied")
+			responses.HandleError(c, err)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
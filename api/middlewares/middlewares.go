@@ -8,6 +8,7 @@ var Module = fx.Options(
 	fx.Provide(NewMiddlewares),
 	fx.Provide(NewDBTransactionMiddleware),
 	fx.Provide(NewJWTAuthMiddleWare),
+	fx.Provide(NewIdempotencyMiddleware),
 )
 
 // IMiddleware middleware interface
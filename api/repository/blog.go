@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// BlogFilter -> which blogs a listing may return
+type BlogFilter struct {
+	// PublishedOnly -> public listings only see published blogs
+	PublishedOnly bool
+	// DraftsOnly -> only blogs that are not published yet
+	DraftsOnly bool
+	// AuthorID -> only blogs created by the user, 0 for all authors
+	AuthorID int64
+	// CategoryID -> only blogs in the category, 0 for all categories
+	CategoryID int64
+}
+
+// blogColumns -> columns of the blog table the generic queries use
+var blogColumns = Columns{Table: "blog", ID: "id", CreatedAt: "created_at", DeletedAt: "deleted_at"}
+
+// BlogRepository -> database structure
+type BlogRepository struct {
+	base     Repository[models.Blog]
+	logger   infrastructure.Logger
+	fieldset utils.Fieldset
+}
+
+// NewBlogRepository -> creates a new Blog repository
+func NewBlogRepository(db infrastructure.Database, logger infrastructure.Logger) BlogRepository {
+	return BlogRepository{
+		base:   NewRepository[models.Blog](db, logger, blogColumns),
+		logger: logger,
+	}
+}
+
+// WithTrx enables repository with transaction
+func (c BlogRepository) WithTrx(trxHandle *gorm.DB) BlogRepository {
+	c.base = c.base.WithTrx(trxHandle)
+	return c
+}
+
+// WithFieldset limits the selected fields and preloads the included relations
+func (c BlogRepository) WithFieldset(fieldset utils.Fieldset) BlogRepository {
+	c.fieldset = fieldset
+	return c
+}
+
+// scope -> applies the blog filter
+func (f BlogFilter) scope(db *gorm.DB) *gorm.DB {
+	if f.PublishedOnly {
+		db = db.Where("? = ?", blogColumns.column("is_published"), true)
+	}
+	if f.DraftsOnly {
+		db = db.Where("? = ?", blogColumns.column("is_published"), false)
+	}
+	if f.AuthorID != 0 {
+		db = db.Where("? = ?", blogColumns.column("created_by_id"), f.AuthorID)
+	}
+	if f.CategoryID != 0 {
+		categories := db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.BlogCategories{}).
+			Select("blog_id").
+			Where("? = ?", column("blog_categories", "category_id"), f.CategoryID)
+		db = db.Where("? IN (?)", blogColumns.column("id"), categories)
+	}
+	return db
+}
+
+// fieldsetScope -> compiles the fieldset, categories are preloaded unless other includes were asked for
+func (c BlogRepository) fieldsetScope() (func(db *gorm.DB) *gorm.DB, error) {
+	fieldset, err := BlogFieldsetRules.Compile(c.base.DB(), &models.Blog{}, c.fieldset)
+	if err != nil {
+		return nil, err
+	}
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Scopes(fieldset.scope())
+		if len(c.fieldset.Include) == 0 {
+			db = db.Preload("Categories")
+		}
+		return db
+	}, nil
+}
+
+// Create -> Blog along with its categories
+func (c BlogRepository) Create(blog *models.Blog) error {
+	if err := c.base.DB().Omit(clause.Associations).Create(blog).Error; err != nil {
+		return err
+	}
+	return c.SetCategories(blog.ID, blog.CategoryIds)
+}
+
+// SetCategories -> replaces the categories of the blog
+func (c BlogRepository) SetCategories(blogID int64, categoryIDs []int64) error {
+	if err := c.base.DB().Unscoped().
+		Where("? = ?", column("blog_categories", "blog_id"), blogID).
+		Delete(&models.BlogCategories{}).Error; err != nil {
+		return err
+	}
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+	blogCategories := make([]models.BlogCategories, 0, len(categoryIDs))
+	seen := map[int64]bool{}
+	for _, categoryID := range categoryIDs {
+		if seen[categoryID] {
+			continue
+		}
+		seen[categoryID] = true
+		blogCategories = append(blogCategories, models.BlogCategories{BlogId: blogID, CategoryId: categoryID})
+	}
+	return c.base.DB().Create(&blogCategories).Error
+}
+
+// GetAllBlogs -> Get All blogs matching the filter
+func (c BlogRepository) GetAllBlogs(pagination utils.Pagination, filter BlogFilter) ([]models.Blog, int64, error) {
+	query, err := BlogQueryFields.Compile(pagination.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	fieldsetScope, err := c.fieldsetScope()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	queryBuilder := c.base.Query().Scopes(filter.scope, query.filterScope())
+	if pagination.Keyword != "" {
+		queryBuilder = queryBuilder.Where(contains(blogColumns.column("title"), pagination.Keyword))
+	}
+	// blog listings are offset pages with a total count
+	pagination.CursorMode, pagination.SkipCount = false, false
+	blogs, page, err := c.base.Page(queryBuilder, pagination, query.orderScope(desc(blogColumns.column("created_at"))), fieldsetScope)
+	if err != nil {
+		return nil, 0, err
+	}
+	return blogs, *page.Count, nil
+}
+
+// GetOneBlog -> Get One Blog By Id
+func (c BlogRepository) GetOneBlog(ID int64, filter BlogFilter) (models.Blog, error) {
+	fieldsetScope, err := c.fieldsetScope()
+	if err != nil {
+		return models.Blog{}, err
+	}
+	return c.base.FindByID(ID, filter.scope, fieldsetScope)
+}
+
+// UpdateOneBlog -> Update One Blog By Id along with its categories
+func (c BlogRepository) UpdateOneBlog(blog models.Blog) error {
+	if _, err := c.base.UpdateByID(blog.ID, map[string]interface{}{
+		"title":            blog.Title,
+		"content":          blog.Content,
+		"thumbnail_image":  blog.ThumbnailImage,
+		"meta_description": blog.MetaDescription,
+		"publish_at":       blog.PublishAt,
+		"updated_by_id":    blog.UpdatedById,
+	}); err != nil {
+		return err
+	}
+	return c.SetCategories(blog.ID, blog.CategoryIds)
+}
+
+// SetPublished -> publishes or moves the blog back to draft
+func (c BlogRepository) SetPublished(ID int64, published bool, userID int64) error {
+	var publishedAt *time.Time
+	if published {
+		now := time.Now()
+		publishedAt = &now
+	}
+	_, err := c.base.UpdateByID(ID, map[string]interface{}{
+		"is_published":  published,
+		"published_at":  publishedAt,
+		"publish_at":    nil,
+		"updated_by_id": userID,
+	})
+	return err
+}
+
+// PublishDue -> publishes the drafts whose publish_at has passed
+func (c BlogRepository) PublishDue(now time.Time) (int64, error) {
+	publishAt := blogColumns.column("publish_at")
+	result := c.base.Query().
+		Where("? = ? AND ? IS NOT NULL AND ? <= ?", blogColumns.column("is_published"), false, publishAt, publishAt, now).
+		Updates(map[string]interface{}{
+			"is_published": true,
+			"published_at": gorm.Expr("?", clause.Column{Name: "publish_at"}),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// SetImages -> stores the thumbnail and open graph image of the blog
+func (c BlogRepository) SetImages(ID int64, thumbnailImage string, ogImage string, userID int64) error {
+	_, err := c.base.UpdateByID(ID, map[string]interface{}{
+		"thumbnail_image": thumbnailImage,
+		"og_image":        ogImage,
+		"updated_by_id":   userID,
+	})
+	return err
+}
+
+// GetOneBlogBySlug -> Get One Blog by its current slug
+func (c BlogRepository) GetOneBlogBySlug(slug string, filter BlogFilter) (models.Blog, error) {
+	blog := models.Blog{}
+	fieldsetScope, err := c.fieldsetScope()
+	if err != nil {
+		return blog, err
+	}
+	return blog, c.base.Query().
+		Scopes(filter.scope, fieldsetScope).
+		Where("? = ?", blogColumns.column("slug"), slug).
+		First(&blog).Error
+}
+
+// GetOneBlogByOldSlug -> Get One Blog that used the slug before
+func (c BlogRepository) GetOneBlogByOldSlug(slug string, filter BlogFilter) (models.Blog, error) {
+	blog := models.Blog{}
+	history := c.base.DB().Session(&gorm.Session{NewDB: true}).
+		Model(&models.BlogSlug{}).
+		Select("blog_id").
+		Where("? = ?", column("blog_slug", "slug"), slug)
+	return blog, c.base.Query().
+		Scopes(filter.scope).
+		Where("? IN (?)", blogColumns.column("id"), history).
+		First(&blog).Error
+}
+
+// IsSlugTaken -> whether the slug is used, now or before, by another blog
+func (c BlogRepository) IsSlugTaken(slug string, blogID int64) (bool, error) {
+	var count int64
+	if err := c.base.Query().Unscoped().
+		Where("? = ? AND ? <> ?", blogColumns.column("slug"), slug, blogColumns.column("id"), blogID).
+		Count(&count).Error; err != nil || count > 0 {
+		return count > 0, err
+	}
+	err := c.base.DB().Unscoped().Model(&models.BlogSlug{}).
+		Where("? = ? AND ? <> ?", column("blog_slug", "slug"), slug, column("blog_slug", "blog_id"), blogID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ChangeSlug -> sets the new slug and keeps the old one in the history
+func (c BlogRepository) ChangeSlug(blogID int64, oldSlug string, newSlug string) error {
+	// a slug taken back from the history is current again
+	if err := c.base.DB().Unscoped().
+		Where("? = ? AND ? = ?", column("blog_slug", "blog_id"), blogID, column("blog_slug", "slug"), newSlug).
+		Delete(&models.BlogSlug{}).Error; err != nil {
+		return err
+	}
+	if err := c.base.DB().Create(&models.BlogSlug{BlogId: blogID, Slug: oldSlug}).Error; err != nil {
+		return err
+	}
+	_, err := c.base.UpdateByID(blogID, map[string]interface{}{"slug": newSlug})
+	return err
+}
+
+// DeleteOneBlog -> Delete One Blog By Id
+func (c BlogRepository) DeleteOneBlog(ID int64) error {
+	_, err := c.base.DeleteByID(ID)
+	return err
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// CategoryRepository -> database structure
+type CategoryRepository struct {
+	db     infrastructure.Database
+	logger infrastructure.Logger
+}
+
+// NewCategoryRepository -> creates a new Category repository
+func NewCategoryRepository(db infrastructure.Database, logger infrastructure.Logger) CategoryRepository {
+	return CategoryRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithTrx enables repository with transaction
+func (c CategoryRepository) WithTrx(trxHandle *gorm.DB) CategoryRepository {
+	if trxHandle == nil {
+		c.logger.Zap.Error("Transaction Database not found in gin context. ")
+		return c
+	}
+	c.db.DB = trxHandle
+	return c
+}
+
+// Create -> Category
+func (c CategoryRepository) Create(category models.Category) (models.Category, error) {
+	return category, c.db.DB.Create(&category).Error
+}
+
+// GetAllCategory -> Get All categories
+func (c CategoryRepository) GetAllCategory(pagination utils.Pagination) ([]models.Category, int64, error) {
+	var categories []models.Category
+	var totalRows int64 = 0
+	query, err := CategoryQueryFields.Compile(pagination.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	queryBuilder := c.db.DB.Model(&models.Category{}).Scopes(query.filterScope())
+	if pagination.Keyword != "" {
+		queryBuilder = queryBuilder.Where(contains(column("category", "title"), pagination.Keyword))
+	}
+	queryBuilder = queryBuilder.Session(&gorm.Session{})
+
+	if err := queryBuilder.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	queryBuilder = queryBuilder.
+		Offset(pagination.Offset).
+		Scopes(query.orderScope(asc(column("category", "title"))))
+	if !pagination.All {
+		queryBuilder = queryBuilder.Limit(pagination.PageSize)
+	}
+	err = queryBuilder.Find(&categories).Error
+	return categories, totalRows, err
+}
+
+// GetOneCategory -> Get One Category By Id
+func (c CategoryRepository) GetOneCategory(ID int64) (models.Category, error) {
+	category := models.Category{}
+	return category, c.db.DB.
+		Where("id = ?", ID).First(&category).Error
+}
+
+// CountExisting -> number of categories that exist among the ids
+func (c CategoryRepository) CountExisting(IDs []int64) (int64, error) {
+	var count int64
+	return count, c.db.DB.Model(&models.Category{}).
+		Where("id IN (?)", IDs).
+		Count(&count).Error
+}
+
+// UpdateOneCategory -> Update One Category By Id
+func (c CategoryRepository) UpdateOneCategory(category models.Category) error {
+	return c.db.DB.Model(&models.Category{}).
+		Where("id = ?", category.ID).
+		Updates(map[string]interface{}{
+			"title": category.Title,
+		}).Error
+}
+
+// DeleteOneCategory -> Delete One Category By Id, blogs are detached from it
+func (c CategoryRepository) DeleteOneCategory(ID int64) error {
+	if err := c.db.DB.Unscoped().
+		Where("category_id = ?", ID).
+		Delete(&models.BlogCategories{}).Error; err != nil {
+		return err
+	}
+	return c.db.DB.
+		Where("id = ?", ID).
+		Delete(&models.Category{}).
+		Error
+}
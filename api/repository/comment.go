@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentRepository -> database structure
+type CommentRepository struct {
+	db     infrastructure.Database
+	logger infrastructure.Logger
+}
+
+// NewCommentRepository -> creates a new Comment repository
+func NewCommentRepository(db infrastructure.Database, logger infrastructure.Logger) CommentRepository {
+	return CommentRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithTrx enables repository with transaction
+func (c CommentRepository) WithTrx(trxHandle *gorm.DB) CommentRepository {
+	if trxHandle == nil {
+		c.logger.Zap.Error("Transaction Database not found in gin context. ")
+		return c
+	}
+	c.db.DB = trxHandle
+	return c
+}
+
+// commentAuthorScope -> preloads the public columns of the comment author
+func commentAuthorScope(db *gorm.DB) *gorm.DB {
+	return db.Preload("User", publicUserColumns)
+}
+
+// Create -> Comment
+func (c CommentRepository) Create(comment *models.Comment) error {
+	return c.db.DB.Omit("User").Create(comment).Error
+}
+
+// GetOneComment -> Get One Comment By Id
+func (c CommentRepository) GetOneComment(ID int64) (models.Comment, error) {
+	comment := models.Comment{}
+	return comment, c.db.DB.
+		Where("id = ?", ID).
+		First(&comment).Error
+}
+
+// GetThreads -> paginated top level comments of the blog with the given status, oldest first
+func (c CommentRepository) GetThreads(blogID int64, status string, pagination utils.Pagination) ([]models.Comment, int64, error) {
+	var comments []models.Comment
+	var totalRows int64 = 0
+
+	queryBuilder := c.db.DB.Model(&models.Comment{}).
+		Where("blog_id = ? AND parent_id IS NULL AND status = ?", blogID, status).
+		Session(&gorm.Session{})
+
+	if err := queryBuilder.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	queryBuilder = queryBuilder.
+		Scopes(commentAuthorScope).
+		Offset(pagination.Offset).
+		Order("created_at asc, id asc")
+	if !pagination.All {
+		queryBuilder = queryBuilder.Limit(pagination.PageSize)
+	}
+	err := queryBuilder.Find(&comments).Error
+	return comments, totalRows, err
+}
+
+// GetReplies -> replies with the given status in the threads, oldest first
+func (c CommentRepository) GetReplies(rootIDs []int64, status string) ([]models.Comment, error) {
+	var comments []models.Comment
+	if len(rootIDs) == 0 {
+		return comments, nil
+	}
+	return comments, c.db.DB.
+		Scopes(commentAuthorScope).
+		Where("root_id IN ? AND status = ?", rootIDs, status).
+		Order("created_at asc, id asc").
+		Find(&comments).Error
+}
+
+// GetThread -> every comment of the thread regardless of status, root included
+func (c CommentRepository) GetThread(rootID int64) ([]models.Comment, error) {
+	var comments []models.Comment
+	return comments, c.db.DB.
+		Where("id = ? OR root_id = ?", rootID, rootID).
+		Find(&comments).Error
+}
+
+// GetModerationQueue -> paginated comments waiting for moderation, oldest first
+func (c CommentRepository) GetModerationQueue(pagination utils.Pagination) ([]models.Comment, int64, error) {
+	var comments []models.Comment
+	var totalRows int64 = 0
+
+	queryBuilder := c.db.DB.Model(&models.Comment{}).
+		Where("status = ?", models.CommentStatusPending).
+		Session(&gorm.Session{})
+	if pagination.Keyword != "" {
+		queryBuilder = queryBuilder.Where(contains(column("comment", "content"), pagination.Keyword))
+	}
+
+	if err := queryBuilder.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	queryBuilder = queryBuilder.
+		Scopes(commentAuthorScope).
+		Offset(pagination.Offset).
+		Order("created_at asc, id asc")
+	if !pagination.All {
+		queryBuilder = queryBuilder.Limit(pagination.PageSize)
+	}
+	err := queryBuilder.Find(&comments).Error
+	return comments, totalRows, err
+}
+
+// CountSince -> number of comments the user wrote since the given time, deleted ones included
+func (c CommentRepository) CountSince(userID int64, since time.Time) (int64, error) {
+	var count int64
+	err := c.db.DB.Unscoped().Model(&models.Comment{}).
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Count(&count).Error
+	return count, err
+}
+
+// LockAuthor -> locks the row of the user until the transaction ends, so the comments of a user are counted and created one at a time
+// SQLite has no row locks and serializes the writing transactions instead
+func (c CommentRepository) LockAuthor(userID int64) error {
+	return c.db.DB.Model(&models.User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("? = ?", userColumns.column("id"), userID).
+		Take(&models.User{}).Error
+}
+
+// UpdateContent -> replaces the content and moves the comment to the given status
+func (c CommentRepository) UpdateContent(ID int64, content string, status string) error {
+	return c.db.DB.Model(&models.Comment{}).
+		Where("id = ?", ID).
+		Updates(map[string]interface{}{
+			"content": content,
+			"status":  status,
+		}).Error
+}
+
+// SetStatus -> records the moderation decision
+func (c CommentRepository) SetStatus(ID int64, status string, moderatorID int64) error {
+	return c.db.DB.Model(&models.Comment{}).
+		Where("id = ?", ID).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"moderated_by_id": moderatorID,
+			"moderated_at":    time.Now(),
+		}).Error
+}
+
+// DeleteComments -> Delete the comments By Ids
+func (c CommentRepository) DeleteComments(IDs []int64) error {
+	return c.db.DB.
+		Where("id IN ?", IDs).
+		Delete(&models.Comment{}).
+		Error
+}
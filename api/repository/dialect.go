@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// column -> column qualified with the table, quoted for the dialect of the connection when the query is built
+// raw conditions take it as a var, so identifiers never carry MySQL backticks or PostgreSQL double quotes
+func column(table string, name string) clause.Column {
+	return clause.Column{Table: table, Name: name}
+}
+
+// asc -> ascending ordering on the column
+func asc(column clause.Column) clause.OrderByColumn {
+	return clause.OrderByColumn{Column: column}
+}
+
+// desc -> descending ordering on the column
+func desc(column clause.Column) clause.OrderByColumn {
+	return clause.OrderByColumn{Column: column, Desc: true}
+}
+
+// likeEscape -> escape character of LIKE patterns, not a backslash since MySQL treats it specially in string literals
+const likeEscape = "!"
+
+// likeEscaper -> escapes the wildcards of user input, so it is matched literally
+var likeEscaper = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+// iLike -> case insensitive LIKE, MySQL and SQLite compare case insensitively already while PostgreSQL needs ILIKE
+// the value is a pattern escaped with likeEscape
+type iLike struct {
+	Column interface{}
+	Value  interface{}
+}
+
+// contains -> column containing the keyword, wildcards in the keyword match literally
+func contains(column clause.Column, keyword string) iLike {
+	return iLike{Column: column, Value: "%" + likeEscaper.Replace(keyword) + "%"}
+}
+
+func (like iLike) Build(builder clause.Builder) {
+	builder.WriteQuoted(like.Column)
+	builder.WriteString(" " + like.operator(builder) + " ")
+	builder.AddVar(builder, like.Value)
+	builder.WriteString(" ESCAPE '" + likeEscape + "'")
+}
+
+func (like iLike) NegationBuild(builder clause.Builder) {
+	builder.WriteQuoted(like.Column)
+	builder.WriteString(" NOT " + like.operator(builder) + " ")
+	builder.AddVar(builder, like.Value)
+	builder.WriteString(" ESCAPE '" + likeEscape + "'")
+}
+
+func (like iLike) operator(builder clause.Builder) string {
+	if stmt, ok := builder.(*gorm.Statement); ok && stmt.Dialector.Name() == "postgres" {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"boilerplate-api/models"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// dryRunDialectors -> dialectors of every supported driver, none of them connects in dry run mode
+var dryRunDialectors = map[string]gorm.Dialector{
+	"mysql":    mysql.New(mysql.Config{DSN: "user:password@tcp(localhost:3306)/test", SkipInitializeWithVersion: true}),
+	"postgres": postgres.Open("host=localhost user=user dbname=test"),
+	"sqlite":   sqlite.Open(":memory:"),
+}
+
+// dryRunDB -> database building the statements of the driver without running them
+func dryRunDB(t *testing.T, dialector gorm.Dialector) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(dialector, &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// renderWhere -> where clause the driver builds for the condition, with its vars
+func renderWhere(t *testing.T, dialector gorm.Dialector, condition clause.Expression) (string, []interface{}) {
+	t.Helper()
+	stmt := dryRunDB(t, dialector).Model(&models.Todo{}).Unscoped().Where(condition).Find(&[]models.Todo{}).Statement
+	sql := stmt.SQL.String()
+	i := strings.Index(sql, " WHERE ")
+	if i < 0 {
+		t.Fatalf("no where clause in %q", sql)
+	}
+	return sql[i+len(" WHERE "):], stmt.Vars
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		keyword string
+		want    string
+	}{
+		{keyword: "milk", want: "%milk%"},
+		{keyword: "100%", want: "%100!%%"},
+		{keyword: "snake_case", want: "%snake!_case%"},
+		{keyword: "wow!", want: "%wow!!%"},
+		{keyword: `back\slash`, want: `%back\slash%`},
+		{keyword: "", want: "%%"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.keyword, func(t *testing.T) {
+			if got := contains(column("Todo", "Task"), test.keyword).Value; got != test.want {
+				t.Errorf("contains(%q) = %q, want %q", test.keyword, got, test.want)
+			}
+		})
+	}
+}
+
+func TestILikeBuild(t *testing.T) {
+	tests := []struct {
+		driver    string
+		condition clause.Expression
+		want      string
+	}{
+		{driver: "mysql", condition: contains(column("Todo", "Task"), "50%"), want: "`Todo`.`Task` LIKE ? ESCAPE '!'"},
+		{driver: "postgres", condition: contains(column("Todo", "Task"), "50%"), want: `"Todo"."Task" ILIKE $1 ESCAPE '!'`},
+		{driver: "sqlite", condition: contains(column("Todo", "Task"), "50%"), want: "`Todo`.`Task` LIKE ? ESCAPE '!'"},
+		{driver: "mysql", condition: clause.Not(contains(column("Todo", "Task"), "50%")), want: "`Todo`.`Task` NOT LIKE ? ESCAPE '!'"},
+		{driver: "postgres", condition: clause.Not(contains(column("Todo", "Task"), "50%")), want: `"Todo"."Task" NOT ILIKE $1 ESCAPE '!'`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.driver+" "+test.want, func(t *testing.T) {
+			got, vars := renderWhere(t, dryRunDialectors[test.driver], test.condition)
+			if got != test.want {
+				t.Errorf("where = %s, want %s", got, test.want)
+			}
+			if !reflect.DeepEqual(vars, []interface{}{"%50!%%"}) {
+				t.Errorf("vars = %v, want the escaped pattern", vars)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/utils"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// FieldsetRules -> what a client may ask for through `?fields=` and `?include=`
+// fields are the json names of the model columns, fields hidden from json are never selectable
+type FieldsetRules struct {
+	// Includes -> json names of relations that may be preloaded
+	Includes []string
+	// Computed -> json names of fields that are not columns but may be requested
+	Computed []string
+	// Required -> columns always selected, e.g. the ones cursors are built from
+	Required []string
+	// Conditions -> scopes applied when preloading an include, keyed by its json name
+	Conditions map[string]func(db *gorm.DB) *gorm.DB
+}
+
+// UserFieldsetRules -> fieldset rules of users
+var UserFieldsetRules = FieldsetRules{
+	Includes: []string{"memberships"},
+	Computed: []string{"relevance", "highlights"},
+	// version is sent as the ETag
+	Required: []string{"created_at", "version"},
+}
+
+// BlogFieldsetRules -> fieldset rules of blogs, authors are limited to their public columns
+var BlogFieldsetRules = FieldsetRules{
+	Includes: []string{"categories", "created_by", "updated_by"},
+	Required: []string{"created_at"},
+	Conditions: map[string]func(db *gorm.DB) *gorm.DB{
+		"created_by": publicUserColumns,
+		"updated_by": publicUserColumns,
+	},
+}
+
+// TodoFieldsetRules -> fieldset rules of todos
+var TodoFieldsetRules = FieldsetRules{
+	Computed: []string{"tags", "due_at_local"},
+	// due_at_local is computed from the due columns, version is sent as the ETag
+	Required: []string{"CreateDateTime", "DueAt", "DueTimezone", "Version"},
+}
+
+// compiledFieldset -> validated columns to select and relations to preload, names are quoted for the dialect
+type compiledFieldset struct {
+	table    string
+	columns  []string
+	preloads []fieldsetPreload
+}
+
+// fieldsetPreload -> relation to preload with its conditions
+type fieldsetPreload struct {
+	relation   string
+	conditions []interface{}
+}
+
+// Compile validates the fieldset against the GORM schema of the model, every
+// unknown field or include is reported in the error context of a BadRequest error
+func (r FieldsetRules) Compile(db *gorm.DB, model interface{}, fieldset utils.Fieldset) (compiledFieldset, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return compiledFieldset{}, err
+	}
+	compiled := compiledFieldset{table: stmt.Quote(stmt.Schema.Table)}
+	var invalid []errors.ErrorContext
+
+	selected := map[string]bool{}
+	addColumn := func(field *schema.Field) {
+		if field == nil || field.DBName == "" || selected[field.DBName] {
+			return
+		}
+		selected[field.DBName] = true
+		compiled.columns = append(compiled.columns, compiled.table+"."+stmt.Quote(field.DBName))
+	}
+
+	if len(fieldset.Fields) > 0 {
+		for _, field := range stmt.Schema.PrimaryFields {
+			addColumn(field)
+		}
+		for _, name := range r.Required {
+			addColumn(stmt.Schema.LookUpField(name))
+		}
+	}
+	for _, name := range fieldset.Fields {
+		if utils.StringInList(name, r.Computed) {
+			continue
+		}
+		field := lookUpJSONField(stmt.Schema, name)
+		if field == nil {
+			invalid = append(invalid, errors.ErrorContext{
+				Field:   "fields",
+				Message: fmt.Sprintf("Field '%s' is not available.", name),
+			})
+			continue
+		}
+		addColumn(field)
+	}
+
+	for _, name := range fieldset.Include {
+		relation := lookUpJSONRelation(stmt.Schema, name)
+		if relation == nil || !utils.StringInList(name, r.Includes) {
+			invalid = append(invalid, errors.ErrorContext{
+				Field:   "include",
+				Message: fmt.Sprintf("Relation '%s' can not be included.", name),
+			})
+			continue
+		}
+		preload := fieldsetPreload{relation: relation.Name}
+		if condition, ok := r.Conditions[name]; ok {
+			preload.conditions = append(preload.conditions, condition)
+		}
+		compiled.preloads = append(compiled.preloads, preload)
+		// keys of the relation have to be selected for the preload to match records
+		if len(fieldset.Fields) > 0 {
+			for _, reference := range relation.References {
+				if reference.OwnPrimaryKey {
+					addColumn(reference.PrimaryKey)
+				} else if reference.ForeignKey.Schema == stmt.Schema {
+					addColumn(reference.ForeignKey)
+				}
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		err := errors.BadRequest.New("invalid fields or include query")
+		err = errors.SetCustomMessage(err, "Invalid fields or include query")
+		return compiled, errors.AddErrorContextBlock(err, invalid)
+	}
+	return compiled, nil
+}
+
+// selectColumns -> columns for the select clause, all columns of the table when no fields were asked for
+func (f compiledFieldset) selectColumns() string {
+	if len(f.columns) == 0 {
+		return f.table + ".*"
+	}
+	return strings.Join(f.columns, ", ")
+}
+
+// scope -> applies the select and preloads
+func (f compiledFieldset) scope() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if len(f.columns) > 0 {
+			db = db.Select(f.columns)
+		}
+		for _, preload := range f.preloads {
+			db = db.Preload(preload.relation, preload.conditions...)
+		}
+		return db
+	}
+}
+
+// jsonName -> name of the field in json responses, empty when hidden
+func jsonName(field *schema.Field) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// lookUpJSONField -> readable column of the schema by its json name
+func lookUpJSONField(s *schema.Schema, name string) *schema.Field {
+	for _, field := range s.Fields {
+		if field.DBName != "" && field.Readable && jsonName(field) == name {
+			return field
+		}
+	}
+	return nil
+}
+
+// lookUpJSONRelation -> relation of the schema by its json name
+func lookUpJSONRelation(s *schema.Schema, name string) *schema.Relationship {
+	for _, relation := range s.Relationships.Relations {
+		if jsonName(relation.Field) == name {
+			return relation
+		}
+	}
+	return nil
+}
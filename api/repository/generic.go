@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Scope -> condition or clause applied to a query
+type Scope = func(db *gorm.DB) *gorm.DB
+
+// Model -> what the generic repository needs from a model, keyset pages are built from the cursors
+type Model interface {
+	PageCursor() utils.Cursor
+}
+
+// Columns -> table and columns of a model the generic queries use
+type Columns struct {
+	Table     string
+	ID        string
+	CreatedAt string
+	DeletedAt string
+}
+
+// column -> column qualified with the table
+func (c Columns) column(name string) clause.Column {
+	return column(c.Table, name)
+}
+
+// Repository -> CRUD, pagination and soft delete queries shared by the model repositories
+// scopes added with Scoped apply to every query of the repository, the soft delete helpers included
+type Repository[T Model] struct {
+	db      infrastructure.Database
+	logger  infrastructure.Logger
+	columns Columns
+	scopes  []Scope
+}
+
+// NewRepository -> creates a new generic repository of the model
+func NewRepository[T Model](db infrastructure.Database, logger infrastructure.Logger, columns Columns) Repository[T] {
+	return Repository[T]{
+		db:      db,
+		logger:  logger,
+		columns: columns,
+	}
+}
+
+// WithTrx enables repository with transaction
+func (r Repository[T]) WithTrx(trxHandle *gorm.DB) Repository[T] {
+	if trxHandle == nil {
+		r.logger.Zap.Error("Transaction Database not found in gin context. ")
+		return r
+	}
+	r.db.DB = trxHandle
+	return r
+}
+
+// Scoped -> copy of the repository that also applies the scopes
+func (r Repository[T]) Scoped(scopes ...Scope) Repository[T] {
+	r.scopes = append(append([]Scope{}, r.scopes...), scopes...)
+	return r
+}
+
+// DB -> database handle of the repository, without the scopes
+func (r Repository[T]) DB() *gorm.DB {
+	return r.db.DB
+}
+
+// Query -> query on the model with the scopes of the repository
+func (r Repository[T]) Query() *gorm.DB {
+	return r.db.DB.Model(new(T)).Scopes(r.scopes...)
+}
+
+// byID -> limits the query to the record
+func (r Repository[T]) byID(ID int64) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("? = ?", r.columns.column(r.columns.ID), ID)
+	}
+}
+
+// Create -> inserts the record
+func (r Repository[T]) Create(record *T) error {
+	return r.db.DB.Create(record).Error
+}
+
+// FindByID -> record by id, gorm.ErrRecordNotFound when the scopes exclude it
+func (r Repository[T]) FindByID(ID int64, scopes ...Scope) (T, error) {
+	var record T
+	err := r.Query().Scopes(scopes...).Scopes(r.byID(ID)).First(&record).Error
+	return record, err
+}
+
+// UpdateByID -> updates the columns of the record, no rows are affected when the scopes exclude it
+func (r Repository[T]) UpdateByID(ID int64, updates interface{}, scopes ...Scope) (int64, error) {
+	result := r.Query().Scopes(scopes...).Scopes(r.byID(ID)).Updates(updates)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByID -> soft deletes the record, no rows are affected when the scopes exclude it
+func (r Repository[T]) DeleteByID(ID int64, scopes ...Scope) (int64, error) {
+	result := r.db.DB.Scopes(r.scopes...).Scopes(scopes...).Scopes(r.byID(ID)).Delete(new(T))
+	return result.RowsAffected, result.Error
+}
+
+// Page -> page of the query, the matches are counted unless the pagination skips it
+// offset pages are sorted by order, cursor pages by created at and id, selects apply to both
+func (r Repository[T]) Page(query *gorm.DB, pagination utils.Pagination, order Scope, selects ...Scope) ([]T, utils.PageInfo, error) {
+	var records []T
+	page := utils.PageInfo{}
+	query = query.Session(&gorm.Session{})
+
+	if !pagination.SkipCount {
+		var totalRows int64 = 0
+		if err := query.Count(&totalRows).Error; err != nil {
+			return nil, page, err
+		}
+		page.Count = &totalRows
+	}
+
+	if !pagination.CursorMode {
+		query = query.Offset(pagination.Offset).Scopes(selects...).Scopes(order)
+		if !pagination.All {
+			query = query.Limit(pagination.PageSize)
+		}
+		err := query.Find(&records).Error
+		return records, page, err
+	}
+
+	keyset := keysetScope(r.columns.column(r.columns.CreatedAt), r.columns.column(r.columns.ID), pagination)
+	if err := query.Scopes(selects...).Scopes(keyset).Find(&records).Error; err != nil {
+		return nil, page, err
+	}
+	hasMore := len(records) > pagination.PageSize
+	if hasMore {
+		records = records[:pagination.PageSize]
+	}
+	if pagination.Cursor != nil && pagination.Cursor.Backward {
+		for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+			records[i], records[j] = records[j], records[i]
+		}
+	}
+	if len(records) > 0 {
+		cursors := keysetPageInfo(pagination, hasMore, records[0].PageCursor(), records[len(records)-1].PageCursor())
+		page.Next, page.Prev = cursors.Next, cursors.Prev
+	}
+	return records, page, nil
+}
+
+// deleted -> limits the query to soft deleted records
+func (r Repository[T]) deleted(db *gorm.DB) *gorm.DB {
+	return db.Unscoped().Where("? IS NOT NULL", r.columns.column(r.columns.DeletedAt))
+}
+
+// FindDeleted -> page of the soft deleted records, offset pages most recently deleted first
+func (r Repository[T]) FindDeleted(pagination utils.Pagination, scopes ...Scope) ([]T, utils.PageInfo, error) {
+	query := r.Query().Scopes(r.deleted).Scopes(scopes...)
+	return r.Page(query, pagination, func(db *gorm.DB) *gorm.DB {
+		return db.Order(desc(r.columns.column(r.columns.DeletedAt)))
+	})
+}
+
+// FindDeletedByID -> soft deleted record by id
+func (r Repository[T]) FindDeletedByID(ID int64) (T, error) {
+	var record T
+	err := r.Query().Scopes(r.deleted, r.byID(ID)).First(&record).Error
+	return record, err
+}
+
+// FindDeletedBefore -> records soft deleted before the time, oldest first
+func (r Repository[T]) FindDeletedBefore(before time.Time, limit int) ([]T, error) {
+	var records []T
+	err := r.Query().Unscoped().
+		Where("? < ?", r.columns.column(r.columns.DeletedAt), before).
+		Order(asc(r.columns.column(r.columns.DeletedAt))).
+		Limit(limit).
+		Find(&records).Error
+	return records, err
+}
+
+// Restore -> clears the soft delete of the record along with the updates, false when there is no such deleted record
+func (r Repository[T]) Restore(ID int64, updates map[string]interface{}) (bool, error) {
+	restore := map[string]interface{}{r.columns.DeletedAt: nil}
+	for column, value := range updates {
+		restore[column] = value
+	}
+	result := r.Query().Scopes(r.deleted, r.byID(ID)).Updates(restore)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Purge -> permanently deletes a soft deleted record, false when there is no such deleted record
+func (r Repository[T]) Purge(ID int64) (bool, error) {
+	result := r.db.DB.Scopes(r.scopes...).Scopes(r.deleted, r.byID(ID)).Delete(new(T))
+	return result.RowsAffected > 0, result.Error
+}
+
+// PurgeDeletedBefore -> permanently deletes the records soft deleted before the time
+func (r Repository[T]) PurgeDeletedBefore(before time.Time) (int64, error) {
+	result := r.db.DB.Scopes(r.scopes...).Unscoped().
+		Where("? < ?", r.columns.column(r.columns.DeletedAt), before).
+		Delete(new(T))
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var categoryColumns = Columns{Table: "category", ID: "id", CreatedAt: "created_at", DeletedAt: "deleted_at"}
+
+// newTestCategoryRepository -> generic repository on a sqlite category table holding c1 to c5, created a minute apart
+func newTestCategoryRepository(t *testing.T) Repository[models.Category] {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&models.Category{}); err != nil {
+		t.Fatal(err)
+	}
+	repository := NewRepository[models.Category](
+		infrastructure.Database{DB: db},
+		infrastructure.Logger{Zap: zap.NewNop().Sugar()},
+		categoryColumns,
+	)
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, title := range []string{"c1", "c2", "c3", "c4", "c5"} {
+		category := models.Category{Title: title}
+		category.CreatedAt = created
+		if err := repository.Create(&category); err != nil {
+			t.Fatal(err)
+		}
+		created = created.Add(time.Minute)
+	}
+	return repository
+}
+
+func categoryTitles(categories []models.Category) []string {
+	titles := []string{}
+	for _, category := range categories {
+		titles = append(titles, category.Title)
+	}
+	return titles
+}
+
+func TestRepositoryPage(t *testing.T) {
+	repository := newTestCategoryRepository(t)
+	byID := func(db *gorm.DB) *gorm.DB { return db.Order("id") }
+
+	categories, page, err := repository.Page(repository.Query(), utils.Pagination{Offset: 2, PageSize: 2}, byID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := categoryTitles(categories); !reflect.DeepEqual(got, []string{"c3", "c4"}) || page.Count == nil || *page.Count != 5 {
+		t.Errorf("offset page = %v of %v, want [c3 c4] of 5", got, page.Count)
+	}
+
+	categories, page, err = repository.Page(repository.Query(), utils.Pagination{PageSize: 2, SkipCount: true, All: true}, byID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(categories) != 5 || page.Count != nil {
+		t.Errorf("all = %d records counted %v, want 5 without count", len(categories), page.Count)
+	}
+
+	// cursor pages run newest first and move back and forth through the cursors
+	pages := []struct {
+		name     string
+		cursor   func(page utils.PageInfo) *utils.Cursor
+		want     []string
+		wantNext bool
+		wantPrev bool
+	}{
+		{name: "first", cursor: func(utils.PageInfo) *utils.Cursor { return nil }, want: []string{"c5", "c4"}, wantNext: true},
+		{name: "second", cursor: func(page utils.PageInfo) *utils.Cursor { return page.Next }, want: []string{"c3", "c2"}, wantNext: true, wantPrev: true},
+		{name: "last", cursor: func(page utils.PageInfo) *utils.Cursor { return page.Next }, want: []string{"c1"}, wantPrev: true},
+		{name: "back", cursor: func(page utils.PageInfo) *utils.Cursor { return page.Prev }, want: []string{"c3", "c2"}, wantNext: true, wantPrev: true},
+	}
+	page = utils.PageInfo{}
+	for _, test := range pages {
+		pagination := utils.Pagination{PageSize: 2, CursorMode: true, SkipCount: true, Cursor: test.cursor(page)}
+		categories, page, err = repository.Page(repository.Query(), pagination, byID)
+		if err != nil {
+			t.Fatalf("%s page: %v", test.name, err)
+		}
+		if got := categoryTitles(categories); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s page = %v, want %v", test.name, got, test.want)
+		}
+		if (page.Next != nil) != test.wantNext || (page.Prev != nil) != test.wantPrev {
+			t.Errorf("%s page next %v prev %v, want next %v prev %v", test.name, page.Next, page.Prev, test.wantNext, test.wantPrev)
+		}
+	}
+}
+
+func TestRepositorySoftDelete(t *testing.T) {
+	repository := newTestCategoryRepository(t)
+	for _, id := range []int64{1, 2, 3} {
+		if _, err := repository.DeleteByID(id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleted, page, err := repository.FindDeleted(utils.Pagination{PageSize: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 3 || page.Count == nil || *page.Count != 3 {
+		t.Errorf("deleted = %v of %v, want 3", categoryTitles(deleted), page.Count)
+	}
+	if _, err := repository.FindByID(1); err != gorm.ErrRecordNotFound {
+		t.Errorf("find deleted record error = %v, want not found", err)
+	}
+	if _, err := repository.FindDeletedByID(4); err != gorm.ErrRecordNotFound {
+		t.Errorf("find live record as deleted error = %v, want not found", err)
+	}
+
+	// scopes of the repository apply to restore and purge as well
+	scoped := repository.Scoped(func(db *gorm.DB) *gorm.DB { return db.Where("title <> ?", "c1") })
+	tests := []struct {
+		name   string
+		action func() (bool, error)
+		want   bool
+	}{
+		{name: "restore outside the scopes", action: func() (bool, error) { return scoped.Restore(1, nil) }},
+		{name: "purge outside the scopes", action: func() (bool, error) { return scoped.Purge(1) }},
+		{name: "restore", action: func() (bool, error) { return scoped.Restore(2, map[string]interface{}{"title": "restored"}) }, want: true},
+		{name: "restore a live record", action: func() (bool, error) { return repository.Restore(2, nil) }},
+		{name: "purge", action: func() (bool, error) { return repository.Purge(1) }, want: true},
+		{name: "purge a live record", action: func() (bool, error) { return repository.Purge(4) }},
+		{name: "purge twice", action: func() (bool, error) { return repository.Purge(1) }},
+	}
+	for _, test := range tests {
+		got, err := test.action()
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if got != test.want {
+			t.Errorf("%s = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	restored, err := repository.FindByID(2)
+	if err != nil || restored.Title != "restored" {
+		t.Errorf("restored record = %q, %v, want it live with the updates", restored.Title, err)
+	}
+	var stored int64
+	if err := repository.DB().Unscoped().Model(&models.Category{}).Count(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored != 4 {
+		t.Errorf("stored records = %d, want 4 after purging one", stored)
+	}
+
+	purged, err := repository.PurgeDeletedBefore(time.Now().Add(time.Minute))
+	if err != nil || purged != 1 {
+		t.Errorf("purged before = %d, %v, want the remaining deleted record", purged, err)
+	}
+}
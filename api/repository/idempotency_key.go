@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// IdempotencyKeyRepository -> database structure
+type IdempotencyKeyRepository struct {
+	db     infrastructure.Database
+	logger infrastructure.Logger
+}
+
+// NewIdempotencyKeyRepository -> creates a new IdempotencyKey repository
+func NewIdempotencyKeyRepository(db infrastructure.Database, logger infrastructure.Logger) IdempotencyKeyRepository {
+	return IdempotencyKeyRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithTrx enables repository with transaction
+func (c IdempotencyKeyRepository) WithTrx(trxHandle *gorm.DB) IdempotencyKeyRepository {
+	if trxHandle == nil {
+		c.logger.Zap.Error("Transaction Database not found in gin context. ")
+		return c
+	}
+	c.db.DB = trxHandle
+	return c
+}
+
+// GetKey -> unexpired key of the user, read from the primary so a retry sees the key reserved just before
+func (c IdempotencyKeyRepository) GetKey(userID int64, key string) (models.IdempotencyKey, error) {
+	record := models.IdempotencyKey{}
+	err := c.db.Primary().Where(map[string]interface{}{"user_id": userID, "key": key}).
+		Where("expires_at > ?", time.Now()).
+		First(&record).Error
+	return record, err
+}
+
+// Reserve -> inserts the key before the request is processed, replacing an expired one
+// a key reserved by a transaction still running blocks until it ends and then fails as duplicate
+func (c IdempotencyKeyRepository) Reserve(record *models.IdempotencyKey) error {
+	if err := c.db.DB.Where(map[string]interface{}{"user_id": record.UserID, "key": record.Key}).
+		Where("expires_at <= ?", time.Now()).
+		Delete(&models.IdempotencyKey{}).Error; err != nil {
+		return err
+	}
+	return c.db.DB.Create(record).Error
+}
+
+// Complete -> stores the response of the request
+func (c IdempotencyKeyRepository) Complete(ID int64, status int, contentType string, body []byte) error {
+	return c.db.DB.Model(&models.IdempotencyKey{}).
+		Where("id = ?", ID).
+		Updates(map[string]interface{}{
+			"status":       status,
+			"content_type": contentType,
+			"body":         body,
+		}).Error
+}
+
+// PurgeExpired -> deletes keys that expired before the time
+func (c IdempotencyKeyRepository) PurgeExpired(before time.Time) (int64, error) {
+	result := c.db.DB.Where("expires_at <= ?", before).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvitationRepository -> database structure
+type InvitationRepository struct {
+	db       infrastructure.Database
+	logger   infrastructure.Logger
+	tenantID int64
+}
+
+// NewInvitationRepository -> creates a new Invitation repository
+func NewInvitationRepository(db infrastructure.Database, logger infrastructure.Logger) InvitationRepository {
+	return InvitationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithTrx enables repository with transaction
+func (c InvitationRepository) WithTrx(trxHandle *gorm.DB) InvitationRepository {
+	if trxHandle == nil {
+		c.logger.Zap.Error("Transaction Database not found in gin context. ")
+		return c
+	}
+	c.db.DB = trxHandle
+	return c
+}
+
+// WithTenant limits repository queries to the organization
+func (c InvitationRepository) WithTenant(tenantID int64) InvitationRepository {
+	c.tenantID = tenantID
+	return c
+}
+
+// tenantScope -> limits invitation queries to the organization
+func (c InvitationRepository) tenantScope(db *gorm.DB) *gorm.DB {
+	if c.tenantID == 0 {
+		return db
+	}
+	return db.Where("organization_id = ?", c.tenantID)
+}
+
+// Create -> Invitation
+func (c InvitationRepository) Create(invitation *models.Invitation) error {
+	if c.tenantID != 0 {
+		invitation.OrganizationID = &c.tenantID
+	}
+	return c.db.DB.Create(invitation).Error
+}
+
+// GetAllPending -> Get all pending invitations
+func (c InvitationRepository) GetAllPending(pagination utils.Pagination) ([]models.Invitation, int64, error) {
+	var invitations []models.Invitation
+	var totalRows int64 = 0
+	queryBuilder := c.db.DB.Model(&models.Invitation{}).
+		Scopes(c.tenantScope).
+		Where("accepted_at IS NULL").
+		Offset(pagination.Offset).
+		Order("created_at desc")
+
+	if !pagination.All {
+		queryBuilder = queryBuilder.Limit(pagination.PageSize)
+	}
+	if pagination.Keyword != "" {
+		queryBuilder = queryBuilder.Where(contains(column("invitation", "email"), pagination.Keyword))
+	}
+
+	err := queryBuilder.
+		Find(&invitations).
+		Offset(-1).
+		Limit(-1).
+		Count(&totalRows).Error
+	return invitations, totalRows, err
+}
+
+// GetOnePending -> Get one pending invitation by id
+func (c InvitationRepository) GetOnePending(ID int64) (models.Invitation, error) {
+	invitation := models.Invitation{}
+	return invitation, c.db.DB.
+		Scopes(c.tenantScope).
+		Where("id = ? AND accepted_at IS NULL", ID).
+		First(&invitation).Error
+}
+
+// GetPendingByEmail -> Get pending invitation of the organization for the email
+func (c InvitationRepository) GetPendingByEmail(email string) (models.Invitation, error) {
+	invitation := models.Invitation{}
+	return invitation, c.db.DB.
+		Scopes(c.tenantScope).
+		Where("email = ? AND accepted_at IS NULL", email).
+		First(&invitation).Error
+}
+
+// GetPendingByToken -> Get pending invitation by token
+func (c InvitationRepository) GetPendingByToken(token string) (models.Invitation, error) {
+	invitation := models.Invitation{}
+	return invitation, c.db.DB.
+		Where("token = ? AND accepted_at IS NULL", token).
+		First(&invitation).Error
+}
+
+// UpdateToken -> stores the regenerated token of the invitation
+func (c InvitationRepository) UpdateToken(invitation models.Invitation) error {
+	return c.db.DB.Model(&models.Invitation{}).
+		Where("id = ?", invitation.ID).
+		Updates(map[string]interface{}{
+			"token":      invitation.Token,
+			"expires_at": invitation.ExpiresAt,
+			"sent_at":    invitation.SentAt,
+		}).Error
+}
+
+// MarkAccepted -> sets accepted time of the invitation
+func (c InvitationRepository) MarkAccepted(ID int64) error {
+	return c.db.DB.Model(&models.Invitation{}).
+		Where("id = ?", ID).
+		Update("accepted_at", time.Now()).Error
+}
+
+// Delete -> revokes the invitation
+func (c InvitationRepository) Delete(ID int64) error {
+	result := c.db.DB.
+		Scopes(c.tenantScope).
+		Where("id = ? AND accepted_at IS NULL", ID).
+		Delete(&models.Invitation{})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return result.Error
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+
+	"gorm.io/gorm"
+)
+
+// OrganizationRepository -> database structure
+type OrganizationRepository struct {
+	db     infrastructure.Database
+	logger infrastructure.Logger
+}
+
+// NewOrganizationRepository -> creates a new Organization repository
+func NewOrganizationRepository(db infrastructure.Database, logger infrastructure.Logger) OrganizationRepository {
+	return OrganizationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithTrx enables repository with transaction
+func (c OrganizationRepository) WithTrx(trxHandle *gorm.DB) OrganizationRepository {
+	if trxHandle == nil {
+		c.logger.Zap.Error("Transaction Database not found in gin context. ")
+		return c
+	}
+	c.db.DB = trxHandle
+	return c
+}
+
+// Create -> Organization
+func (c OrganizationRepository) Create(organization *models.Organization) error {
+	return c.db.DB.Create(organization).Error
+}
+
+// GetUserOrganizations -> organizations the user is a member of
+func (c OrganizationRepository) GetUserOrganizations(userID int64) ([]models.OrganizationMember, error) {
+	var memberships []models.OrganizationMember
+	return memberships, c.db.DB.
+		Preload("Organization").
+		Where("user_id = ?", userID).
+		Find(&memberships).Error
+}
+
+// GetMembership -> membership of the user in the organization
+func (c OrganizationRepository) GetMembership(organizationID int64, userID int64) (models.OrganizationMember, error) {
+	membership := models.OrganizationMember{}
+	return membership, c.db.DB.
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&membership).Error
+}
+
+// CreateMembership -> adds the user to the organization
+func (c OrganizationRepository) CreateMembership(membership *models.OrganizationMember) error {
+	return c.db.DB.Create(membership).Error
+}
+
+// SetCurrentOrganization -> sets the organization the user acts in
+func (c OrganizationRepository) SetCurrentOrganization(userID int64, organizationID int64) error {
+	return c.db.DB.Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("organization_id", organizationID).Error
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"boilerplate-api/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// keysetScope -> applies cursor conditions and ordering on the created_at,id columns
+// one extra row is fetched to know if there are more records after the page
+func keysetScope(createdAtColumn clause.Column, idColumn clause.Column, pagination utils.Pagination) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		cursor := pagination.Cursor
+		descending := true
+		if cursor != nil {
+			if cursor.Backward {
+				descending = false
+				db = db.Where(
+					"(? > ? OR (? = ? AND ? > ?))",
+					createdAtColumn, cursor.CreatedAt, createdAtColumn, cursor.CreatedAt, idColumn, cursor.ID,
+				)
+			} else {
+				db = db.Where(
+					"(? < ? OR (? = ? AND ? < ?))",
+					createdAtColumn, cursor.CreatedAt, createdAtColumn, cursor.CreatedAt, idColumn, cursor.ID,
+				)
+			}
+		}
+		return db.
+			Order(clause.OrderByColumn{Column: createdAtColumn, Desc: descending}).
+			Order(clause.OrderByColumn{Column: idColumn, Desc: descending}).
+			Limit(pagination.PageSize + 1)
+	}
+}
+
+// keysetPageInfo -> next and previous cursors from the first and last record of the page
+func keysetPageInfo(pagination utils.Pagination, hasMore bool, first utils.Cursor, last utils.Cursor) utils.PageInfo {
+	page := utils.PageInfo{}
+	backward := pagination.Cursor != nil && pagination.Cursor.Backward
+	if hasMore || backward {
+		page.Next = &utils.Cursor{CreatedAt: last.CreatedAt, ID: last.ID, Fingerprint: pagination.Fingerprint}
+	}
+	if (pagination.Cursor != nil && !backward) || (backward && hasMore) {
+		page.Prev = &utils.Cursor{CreatedAt: first.CreatedAt, ID: first.ID, Backward: true, Fingerprint: pagination.Fingerprint}
+	}
+	return page
+}
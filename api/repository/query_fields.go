@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/utils"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// queryFieldType -> how filter values of a field are parsed
+type queryFieldType int
+
+const (
+	stringField queryFieldType = iota
+	intField
+	boolField
+	timeField
+)
+
+// queryOperators -> filter operators allowed for each field type
+var queryOperators = map[queryFieldType][]string{
+	stringField: {"eq", "ne", "like", "in"},
+	intField:    {"eq", "ne", "gt", "gte", "lt", "lte", "in"},
+	boolField:   {"eq", "ne"},
+	timeField:   {"eq", "ne", "gt", "gte", "lt", "lte"},
+}
+
+// QueryField -> column a public field maps to and what clients may do with it
+type QueryField struct {
+	Column     clause.Column
+	Type       queryFieldType
+	Sortable   bool
+	Filterable bool
+}
+
+// QueryFields -> whitelist of fields for sorting and filtering, keyed by the public field name
+type QueryFields map[string]QueryField
+
+// TodoQueryFields -> sortable and filterable fields of todos
+var TodoQueryFields = QueryFields{
+	"id":            {Column: column("Todo", "ID"), Type: intField, Sortable: true, Filterable: true},
+	"task":          {Column: column("Todo", "Task"), Type: stringField, Sortable: true, Filterable: true},
+	"is_completed":  {Column: column("Todo", "IsCompleted"), Type: boolField, Sortable: true, Filterable: true},
+	"list_id":       {Column: column("Todo", "ListID"), Type: intField, Sortable: true, Filterable: true},
+	"priority":      {Column: column("Todo", "Priority"), Type: intField, Sortable: true, Filterable: true},
+	"due_at":        {Column: column("Todo", "DueAt"), Type: timeField, Sortable: true, Filterable: true},
+	"recurrence_id": {Column: column("Todo", "RecurrenceID"), Type: intField, Filterable: true},
+	"created_at":    {Column: column("Todo", "CreateDateTime"), Type: timeField, Sortable: true, Filterable: true},
+	"updated_at":    {Column: column("Todo", "UpdateDateTime"), Type: timeField, Sortable: true, Filterable: true},
+}
+
+// UserQueryFields -> sortable and filterable fields of users
+var UserQueryFields = QueryFields{
+	"id":             {Column: column("user", "id"), Type: intField, Sortable: true, Filterable: true},
+	"username":       {Column: column("user", "username"), Type: stringField, Sortable: true, Filterable: true},
+	"email":          {Column: column("user", "email"), Type: stringField, Sortable: true, Filterable: true},
+	"full_name":      {Column: column("user", "full_name"), Type: stringField, Sortable: true, Filterable: true},
+	"role":           {Column: column("user", "role"), Type: stringField, Sortable: true, Filterable: true},
+	"email_verified": {Column: column("user", "email_verified"), Type: boolField, Filterable: true},
+	"phone_verified": {Column: column("user", "phone_verified"), Type: boolField, Filterable: true},
+	"created_at":     {Column: column("user", "created_at"), Type: timeField, Sortable: true, Filterable: true},
+	"updated_at":     {Column: column("user", "updated_at"), Type: timeField, Sortable: true, Filterable: true},
+}
+
+// BlogQueryFields -> sortable and filterable fields of blogs
+var BlogQueryFields = QueryFields{
+	"id":            {Column: column("blog", "id"), Type: intField, Sortable: true, Filterable: true},
+	"title":         {Column: column("blog", "title"), Type: stringField, Sortable: true, Filterable: true},
+	"slug":          {Column: column("blog", "slug"), Type: stringField, Filterable: true},
+	"is_published":  {Column: column("blog", "is_published"), Type: boolField, Filterable: true},
+	"published_at":  {Column: column("blog", "published_at"), Type: timeField, Sortable: true, Filterable: true},
+	"publish_at":    {Column: column("blog", "publish_at"), Type: timeField, Sortable: true, Filterable: true},
+	"created_by_id": {Column: column("blog", "created_by_id"), Type: intField, Filterable: true},
+	"created_at":    {Column: column("blog", "created_at"), Type: timeField, Sortable: true, Filterable: true},
+	"updated_at":    {Column: column("blog", "updated_at"), Type: timeField, Sortable: true, Filterable: true},
+}
+
+// CategoryQueryFields -> sortable and filterable fields of categories
+var CategoryQueryFields = QueryFields{
+	"id":         {Column: column("category", "id"), Type: intField, Sortable: true, Filterable: true},
+	"title":      {Column: column("category", "title"), Type: stringField, Sortable: true, Filterable: true},
+	"created_at": {Column: column("category", "created_at"), Type: timeField, Sortable: true, Filterable: true},
+}
+
+// TodoListQueryFields -> sortable and filterable fields of todo lists
+var TodoListQueryFields = QueryFields{
+	"id":         {Column: column("TodoList", "ID"), Type: intField, Sortable: true, Filterable: true},
+	"name":       {Column: column("TodoList", "Name"), Type: stringField, Sortable: true, Filterable: true},
+	"created_at": {Column: column("TodoList", "CreateDateTime"), Type: timeField, Sortable: true, Filterable: true},
+	"updated_at": {Column: column("TodoList", "UpdateDateTime"), Type: timeField, Sortable: true, Filterable: true},
+}
+
+// compiledQuery -> validated conditions and ordering of a query spec
+type compiledQuery struct {
+	conditions []clause.Expression
+	orders     []clause.OrderByColumn
+}
+
+// Compile validates the spec against the whitelist, every offending field is
+// reported in the error context of a BadRequest error
+func (f QueryFields) Compile(spec utils.QuerySpec) (compiledQuery, error) {
+	query := compiledQuery{}
+	var invalid []errors.ErrorContext
+
+	for _, sortField := range spec.Sort {
+		field, ok := f[sortField.Field]
+		if !ok || !field.Sortable {
+			invalid = append(invalid, errors.ErrorContext{
+				Field:   "sort",
+				Message: fmt.Sprintf("Field '%s' can not be sorted.", sortField.Field),
+			})
+			continue
+		}
+		query.orders = append(query.orders, clause.OrderByColumn{
+			Column: field.Column,
+			Desc:   sortField.Desc,
+		})
+	}
+
+	for _, filter := range spec.Filters {
+		field, ok := f[filter.Field]
+		if filter.Field == "" {
+			invalid = append(invalid, errors.ErrorContext{
+				Field:   filter.Key,
+				Message: "Filter must be in the form filter[field] or filter[field][operator].",
+			})
+			continue
+		}
+		if !ok || !field.Filterable {
+			invalid = append(invalid, errors.ErrorContext{
+				Field:   filter.Key,
+				Message: fmt.Sprintf("Field '%s' can not be filtered.", filter.Field),
+			})
+			continue
+		}
+		if !utils.StringInList(filter.Operator, queryOperators[field.Type]) {
+			invalid = append(invalid, errors.ErrorContext{
+				Field:   filter.Key,
+				Message: fmt.Sprintf("Operator '%s' is not supported for field '%s'.", filter.Operator, filter.Field),
+			})
+			continue
+		}
+		condition, err := field.condition(filter.Operator, filter.Value)
+		if err != nil {
+			invalid = append(invalid, errors.ErrorContext{
+				Field:   filter.Key,
+				Message: fmt.Sprintf("Value '%s' is not valid for field '%s'.", filter.Value, filter.Field),
+			})
+			continue
+		}
+		query.conditions = append(query.conditions, condition)
+	}
+
+	if len(invalid) > 0 {
+		err := errors.BadRequest.New("invalid sort or filter query")
+		err = errors.SetCustomMessage(err, "Invalid sort or filter query")
+		return query, errors.AddErrorContextBlock(err, invalid)
+	}
+	return query, nil
+}
+
+// condition builds the clause for the operator, the value is parsed as the field type
+func (q QueryField) condition(operator string, raw string) (clause.Expression, error) {
+	column := q.Column
+	if operator == "in" {
+		var values []interface{}
+		for _, item := range strings.Split(raw, ",") {
+			value, err := q.parse(strings.TrimSpace(item))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+		return clause.IN{Column: column, Values: values}, nil
+	}
+	if operator == "like" {
+		return contains(column, raw), nil
+	}
+
+	value, err := q.parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	switch operator {
+	case "ne":
+		return clause.Neq{Column: column, Value: value}, nil
+	case "gt":
+		return clause.Gt{Column: column, Value: value}, nil
+	case "gte":
+		return clause.Gte{Column: column, Value: value}, nil
+	case "lt":
+		return clause.Lt{Column: column, Value: value}, nil
+	case "lte":
+		return clause.Lte{Column: column, Value: value}, nil
+	default:
+		return clause.Eq{Column: column, Value: value}, nil
+	}
+}
+
+// parse converts the raw query value to the field type
+func (q QueryField) parse(raw string) (interface{}, error) {
+	switch q.Type {
+	case intField:
+		return strconv.ParseInt(raw, 10, 64)
+	case boolField:
+		return strconv.ParseBool(raw)
+	case timeField:
+		if value, err := time.Parse(time.RFC3339, raw); err == nil {
+			return value, nil
+		}
+		return time.Parse("2006-01-02", raw)
+	default:
+		return raw, nil
+	}
+}
+
+// compileListQuery -> compiles the query of a list request, cursor pagination keeps its own ordering
+func compileListQuery(fields QueryFields, pagination utils.Pagination) (compiledQuery, error) {
+	query, err := fields.Compile(pagination.Query)
+	if err != nil {
+		return query, err
+	}
+	if pagination.CursorMode && query.hasOrder() {
+		err := errors.BadRequest.New("sort is not supported with cursor pagination")
+		err = errors.SetCustomMessage(err, "Invalid sort or filter query")
+		return query, errors.AddErrorContext(err, "sort", "Sorting is not supported with cursor pagination.")
+	}
+	return query, nil
+}
+
+// hasOrder reports whether the client asked for a sort order
+func (q compiledQuery) hasOrder() bool {
+	return len(q.orders) > 0
+}
+
+// filterScope -> applies the validated filter conditions
+func (q compiledQuery) filterScope() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, condition := range q.conditions {
+			db = db.Where(condition)
+		}
+		return db
+	}
+}
+
+// orderScope -> applies the validated ordering, or the default ordering when none was asked for
+func (q compiledQuery) orderScope(defaultOrder ...clause.OrderByColumn) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		orders := q.orders
+		if !q.hasOrder() {
+			orders = defaultOrder
+		}
+		for _, order := range orders {
+			db = db.Order(order)
+		}
+		return db
+	}
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/utils"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/clause"
+)
+
+func querySpec(t *testing.T, query string) utils.QuerySpec {
+	t.Helper()
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return utils.ParseQuerySpec(values)
+}
+
+func TestQueryFieldsCompile(t *testing.T) {
+	task, priority, due := column("Todo", "Task"), column("Todo", "Priority"), column("Todo", "DueAt")
+	tests := []struct {
+		name           string
+		query          string
+		wantConditions []clause.Expression
+		wantOrders     []clause.OrderByColumn
+		wantInvalid    []string
+	}{
+		{name: "empty query", query: ""},
+		{
+			name:       "sort",
+			query:      "sort=-priority,task",
+			wantOrders: []clause.OrderByColumn{desc(priority), asc(task)},
+		},
+		{
+			name:  "typed filters",
+			query: "filter[priority][gte]=2&filter[is_completed]=false&filter[due_at][lt]=2024-03-01&filter[task][ne]=milk",
+			wantConditions: []clause.Expression{
+				clause.Lt{Column: due, Value: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+				clause.Eq{Column: column("Todo", "IsCompleted"), Value: false},
+				clause.Gte{Column: priority, Value: int64(2)},
+				clause.Neq{Column: task, Value: "milk"},
+			},
+		},
+		{
+			name:           "in and like",
+			query:          "filter[id][in]=1, 2,3&filter[task][like]=50%25",
+			wantConditions: []clause.Expression{clause.IN{Column: column("Todo", "ID"), Values: []interface{}{int64(1), int64(2), int64(3)}}, contains(task, "50%")},
+		},
+		{
+			name:           "RFC 3339 time",
+			query:          "filter[created_at][gt]=2024-03-01T10:00:00Z",
+			wantConditions: []clause.Expression{clause.Gt{Column: column("Todo", "CreateDateTime"), Value: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)}},
+		},
+		{name: "unknown sort field", query: "sort=owner_id", wantInvalid: []string{"sort"}},
+		{name: "filter only field is not sortable", query: "sort=recurrence_id", wantInvalid: []string{"sort"}},
+		{name: "unknown filter field", query: "filter[owner_id]=1", wantInvalid: []string{"filter[owner_id]"}},
+		{name: "malformed filter", query: "filter[task", wantInvalid: []string{"filter[task"}},
+		{name: "operator of another type", query: "filter[is_completed][gt]=true", wantInvalid: []string{"filter[is_completed][gt]"}},
+		{name: "unknown operator", query: "filter[task][regex]=a", wantInvalid: []string{"filter[task][regex]"}},
+		{name: "invalid int", query: "filter[priority]=high", wantInvalid: []string{"filter[priority]"}},
+		{name: "invalid int in list", query: "filter[id][in]=1,x", wantInvalid: []string{"filter[id][in]"}},
+		{name: "invalid time", query: "filter[due_at][gt]=tomorrow", wantInvalid: []string{"filter[due_at][gt]"}},
+		{name: "every offending field is reported", query: "sort=owner_id&filter[priority]=high&filter[task]=ok", wantInvalid: []string{"sort", "filter[priority]"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := TodoQueryFields.Compile(querySpec(t, test.query))
+			if len(test.wantInvalid) > 0 {
+				if errors.GetErrorType(err) != errors.BadRequest {
+					t.Fatalf("error = %v, want BadRequest", err)
+				}
+				var fields []string
+				for _, context := range errors.GetErrorContext(err) {
+					fields = append(fields, context.Field)
+				}
+				if !reflect.DeepEqual(fields, test.wantInvalid) {
+					t.Errorf("invalid fields = %v, want %v", fields, test.wantInvalid)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(query.conditions, test.wantConditions) {
+				t.Errorf("conditions = %+v, want %+v", query.conditions, test.wantConditions)
+			}
+			if !reflect.DeepEqual(query.orders, test.wantOrders) {
+				t.Errorf("orders = %+v, want %+v", query.orders, test.wantOrders)
+			}
+		})
+	}
+}
+
+func TestCompileListQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		cursorMode bool
+		wantErr    bool
+	}{
+		{name: "sort with offset pagination", query: "sort=task"},
+		{name: "filter with cursor pagination", query: "filter[task]=milk", cursorMode: true},
+		{name: "sort with cursor pagination", query: "sort=task", cursorMode: true, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pagination := utils.Pagination{Query: querySpec(t, test.query), CursorMode: test.cursorMode}
+			_, err := compileListQuery(TodoQueryFields, pagination)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("error = %v, want error %v", err, test.wantErr)
+			}
+			if err != nil && errors.GetErrorType(err) != errors.BadRequest {
+				t.Errorf("error type = %v, want BadRequest", errors.GetErrorType(err))
+			}
+		})
+	}
+}
+
+func TestCompiledQueryOrderScope(t *testing.T) {
+	defaultOrder := desc(column("Todo", "CreateDateTime"))
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "default order", query: "", want: "`Todo`.`CreateDateTime` DESC"},
+		{name: "requested order", query: "sort=-priority,task", want: "`Todo`.`Priority` DESC,`Todo`.`Task`"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query, err := TodoQueryFields.Compile(querySpec(t, test.query))
+			if err != nil {
+				t.Fatal(err)
+			}
+			db := dryRunDB(t, dryRunDialectors["sqlite"])
+			sql := db.Table("Todo").Scopes(query.orderScope(defaultOrder)).Find(&[]map[string]interface{}{}).Statement.SQL.String()
+			if want := "SELECT * FROM `Todo` ORDER BY " + test.want; sql != want {
+				t.Errorf("sql = %s, want %s", sql, want)
+			}
+		})
+	}
+}
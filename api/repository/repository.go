@@ -6,4 +6,12 @@ import "go.uber.org/fx"
 var Module = fx.Options(
 	fx.Provide(NewUserRepository),
 	fx.Provide(NewTodoRepository),
+	fx.Provide(NewTodoListRepository),
+	fx.Provide(NewTodoShareRepository),
+	fx.Provide(NewOrganizationRepository),
+	fx.Provide(NewInvitationRepository),
+	fx.Provide(NewBlogRepository),
+	fx.Provide(NewCategoryRepository),
+	fx.Provide(NewCommentRepository),
+	fx.Provide(NewIdempotencyKeyRepository),
 )
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"boilerplate-api/models"
+
+	"gorm.io/gorm"
+)
+
+// userTenantScope -> limits user queries to members of the organization
+// outside of an organization a viewer only sees their own record
+func userTenantScope(tenantID int64, viewerID int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenantID == 0 {
+			if viewerID != 0 {
+				return db.Where("? = ?", userColumns.column("id"), viewerID)
+			}
+			return db
+		}
+		members := db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.OrganizationMember{}).
+			Select("user_id").
+			Where("organization_id = ?", tenantID)
+		return db.Where("? IN (?)", userColumns.column("id"), members)
+	}
+}
+
+// todoTenantScope -> limits todo queries to the organization
+func todoTenantScope(tenantID int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenantID == 0 {
+			return db
+		}
+		return db.Where("? = ?", todoColumns.column("OrganizationID"), tenantID)
+	}
+}
+
+// todoListTenantScope -> limits todo list queries to the organization
+func todoListTenantScope(tenantID int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenantID == 0 {
+			return db
+		}
+		return db.Where("? = ?", column("TodoList", "OrganizationID"), tenantID)
+	}
+}
+
+// tagTenantScope -> limits tags to the organization, tags without organization belong to users outside of one
+func tagTenantScope(tenantID int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if tenantID == 0 {
+			return db.Where("? IS NULL", column("Tag", "OrganizationID"))
+		}
+		return db.Where("? = ?", column("Tag", "OrganizationID"), tenantID)
+	}
+}
@@ -4,63 +4,410 @@ import (
 	"boilerplate-api/infrastructure"
 	"boilerplate-api/models"
 	"boilerplate-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// Due date filters of todos
+	TodoDueOverdue = "overdue"
+	TodoDueToday   = "today"
+	TodoDueWeek    = "week"
+	TodoDueNone    = "none"
+)
+
+// TodoDueFilters -> accepted values of the due date filter
+var TodoDueFilters = []string{TodoDueOverdue, TodoDueToday, TodoDueWeek, TodoDueNone}
+
+const (
+	// Relations of the user to todos
+	TodoRelationOwned    = "owned"
+	TodoRelationAssigned = "assigned"
+	TodoRelationShared   = "shared"
 )
 
+// TodoRelations -> accepted values of the relation filter
+var TodoRelations = []string{TodoRelationOwned, TodoRelationAssigned, TodoRelationShared}
+
+// TodoFilter -> which todos a listing may return
+type TodoFilter struct {
+	// Tags -> only todos having all of the tags
+	Tags []string
+	// Due -> one of TodoDueFilters, empty for all todos
+	Due string
+	// Location -> timezone the days of the due filter are computed in
+	Location *time.Location
+	// Relation -> one of TodoRelations for the user, empty for all todos the user can see
+	Relation string
+	// UserID -> user the relation filter is applied for
+	UserID int64
+}
+
+// todoColumns -> columns of the todo table the generic queries use
+var todoColumns = Columns{Table: "Todo", ID: "ID", CreatedAt: "CreateDateTime", DeletedAt: "DeleteDateTime"}
+
 // TodoRepository database structure
 type TodoRepository struct {
-	db     infrastructure.Database
-	logger infrastructure.Logger
+	base     Repository[models.Todo]
+	logger   infrastructure.Logger
+	tenantID int64
+	viewerID int64
+	fieldset utils.Fieldset
 }
 
 // NewTodoRepository creates a new Todo repository
 func NewTodoRepository(db infrastructure.Database, logger infrastructure.Logger) TodoRepository {
 	return TodoRepository{
-		db:     db,
+		base:   NewRepository[models.Todo](db, logger, todoColumns),
 		logger: logger,
 	}
 }
 
+// WithTenant limits repository queries to the organization
+func (c TodoRepository) WithTenant(tenantID int64) TodoRepository {
+	c.tenantID = tenantID
+	return c
+}
+
+// WithViewer limits repository queries to todos the user owns, is assigned to or collaborates on
+func (c TodoRepository) WithViewer(userID int64) TodoRepository {
+	c.viewerID = userID
+	return c
+}
+
+// collaborationsOf -> ids of the todos shared with the user
+func collaborationsOf(db *gorm.DB, userID int64) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Model(&models.TodoCollaborator{}).
+		Select("?", column("TodoCollaborator", "TodoID")).
+		Where("? = ?", column("TodoCollaborator", "UserID"), userID)
+}
+
+// todoAccessScope -> limits todo queries to the ones the user can see, todos without owner are visible to everyone
+func todoAccessScope(userID int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if userID == 0 {
+			return db
+		}
+		owner := todoColumns.column("OwnerID")
+		return db.Where(
+			"(? IS NULL OR ? = ? OR ? = ? OR ? IN (?))",
+			owner, owner, userID, todoColumns.column("AssigneeID"), userID,
+			todoColumns.column("ID"), collaborationsOf(db, userID),
+		)
+	}
+}
+
+// WithFieldset limits the selected fields of todos
+func (c TodoRepository) WithFieldset(fieldset utils.Fieldset) TodoRepository {
+	c.fieldset = fieldset
+	return c
+}
+
+// WithTrx enables repository with transaction
+func (c TodoRepository) WithTrx(trxHandle *gorm.DB) TodoRepository {
+	c.base = c.base.WithTrx(trxHandle)
+	return c
+}
+
+// scoped -> queries limited to the organization
+func (c TodoRepository) scoped() Repository[models.Todo] {
+	return c.base.Scoped(todoTenantScope(c.tenantID))
+}
+
+// visible -> queries limited to the todos of the organization the viewer can see
+func (c TodoRepository) visible() Repository[models.Todo] {
+	return c.scoped().Scoped(todoAccessScope(c.viewerID))
+}
+
+// todoIncompleteScope -> limits todo queries to the ones not completed yet
+func todoIncompleteScope(db *gorm.DB) *gorm.DB {
+	completed := todoColumns.column("IsCompleted")
+	return db.Where("(? IS NULL OR ? = ?)", completed, completed, false)
+}
+
+// joinTags -> joins the tags of todo tag queries
+func joinTags(db *gorm.DB) *gorm.DB {
+	return db.Joins("JOIN ? ON ? = ?", clause.Table{Name: "Tag"}, column("Tag", "ID"), column("TodoTag", "TagID"))
+}
+
+// scope -> applies the todo filter
+func (f TodoFilter) scope(db *gorm.DB) *gorm.DB {
+	if len(f.Tags) > 0 {
+		tagged := db.Session(&gorm.Session{NewDB: true}).
+			Model(&models.TodoTag{}).
+			Select("?", column("TodoTag", "TodoID")).
+			Scopes(joinTags).
+			Where("? IN ?", column("Tag", "Name"), f.Tags).
+			Group("TodoTag.TodoID").
+			Having("COUNT(DISTINCT ?) = ?", column("Tag", "Name"), len(f.Tags))
+		db = db.Where("? IN (?)", todoColumns.column("ID"), tagged)
+	}
+
+	switch f.Relation {
+	case TodoRelationOwned:
+		db = db.Where("? = ?", todoColumns.column("OwnerID"), f.UserID)
+	case TodoRelationAssigned:
+		db = db.Where("? = ?", todoColumns.column("AssigneeID"), f.UserID)
+	case TodoRelationShared:
+		db = db.Where("? IN (?)", todoColumns.column("ID"), collaborationsOf(db, f.UserID))
+	}
+
+	loc := f.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	now := time.Now()
+	dueAt := todoColumns.column("DueAt")
+	switch f.Due {
+	case TodoDueOverdue:
+		db = db.Where("? < ?", dueAt, now.UTC()).Scopes(todoIncompleteScope)
+	case TodoDueToday:
+		db = db.Where("? BETWEEN ? AND ?", dueAt, utils.StartOfDay(now, loc).UTC(), utils.EndOfDay(now, loc).UTC())
+	case TodoDueWeek:
+		db = db.Where("? BETWEEN ? AND ?", dueAt, utils.StartOfDay(now, loc).UTC(), utils.EndOfDay(now.AddDate(0, 0, 6), loc).UTC())
+	case TodoDueNone:
+		db = db.Where("? IS NULL", dueAt)
+	}
+	return db
+}
+
+// decorate -> attaches the tags and the local due date to the todos
+func (c TodoRepository) decorate(todos []models.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	IDs := make([]int64, 0, len(todos))
+	for _, todo := range todos {
+		IDs = append(IDs, todo.ID)
+	}
+	var rows []struct {
+		TodoID int64
+		Name   string
+	}
+	if err := c.base.DB().Model(&models.TodoTag{}).
+		Select("? AS todo_id, ? AS name", column("TodoTag", "TodoID"), column("Tag", "Name")).
+		Scopes(joinTags).
+		Where("? IN ?", column("TodoTag", "TodoID"), IDs).
+		Order(asc(column("Tag", "Name"))).
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+	tags := map[int64][]string{}
+	for _, row := range rows {
+		tags[row.TodoID] = append(tags[row.TodoID], row.Name)
+	}
+	for i := range todos {
+		todos[i].Tags = tags[todos[i].ID]
+		if todos[i].Tags == nil {
+			todos[i].Tags = []string{}
+		}
+		todos[i].SetDueAtLocal()
+	}
+	return nil
+}
+
+// SetTags -> replaces the tags of the todo, missing tags are created in the organization
+func (c TodoRepository) SetTags(todoID int64, names []string) error {
+	if err := c.base.DB().
+		Where("? = ?", column("TodoTag", "TodoID"), todoID).
+		Delete(&models.TodoTag{}).Error; err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	findTags := func() ([]models.Tag, error) {
+		var tags []models.Tag
+		return tags, c.base.DB().
+			Scopes(tagTenantScope(c.tenantID)).
+			Where("? IN ?", column("Tag", "Name"), names).
+			Find(&tags).Error
+	}
+	existing, err := findTags()
+	if err != nil {
+		return err
+	}
+	found := map[string]bool{}
+	for _, tag := range existing {
+		found[tag.Name] = true
+	}
+	var missing []models.Tag
+	for _, name := range names {
+		if found[name] {
+			continue
+		}
+		tag := models.Tag{Name: name}
+		if c.tenantID != 0 {
+			tag.OrganizationID = &c.tenantID
+		}
+		missing = append(missing, tag)
+	}
+	if len(missing) > 0 {
+		// tags created concurrently by another request are picked up below
+		if err := c.base.DB().Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error; err != nil {
+			return err
+		}
+		if existing, err = findTags(); err != nil {
+			return err
+		}
+	}
+
+	todoTags := make([]models.TodoTag, 0, len(existing))
+	seen := map[string]bool{}
+	for _, tag := range existing {
+		if seen[tag.Name] {
+			continue
+		}
+		seen[tag.Name] = true
+		todoTags = append(todoTags, models.TodoTag{TodoID: todoID, TagID: tag.ID})
+	}
+	return c.base.DB().Create(&todoTags).Error
+}
+
+// GetAllTags -> tags of the organization
+func (c TodoRepository) GetAllTags() ([]models.Tag, error) {
+	var tags []models.Tag
+	return tags, c.base.DB().
+		Scopes(tagTenantScope(c.tenantID)).
+		Order(asc(column("Tag", "Name"))).
+		Find(&tags).Error
+}
+
+// CompleteRecurring -> completes the occurrence and takes the rule off it, false when it was completed already
+// the rule lives on in the next occurrence, so completing the same occurrence twice never creates two of them
+func (c TodoRepository) CompleteRecurring(ID int64) (bool, error) {
+	updated, err := c.scoped().UpdateByID(ID, map[string]interface{}{
+		"IsCompleted": true,
+		"RRule":       nil,
+		"Version":     increment("Version"),
+	}, todoIncompleteScope)
+	return updated == 1, err
+}
+
+// SetAssignee -> assigns the todo to the user, nil unassigns it
+func (c TodoRepository) SetAssignee(ID int64, assigneeID *int64) error {
+	_, err := c.scoped().UpdateByID(ID, map[string]interface{}{
+		"AssigneeID": assigneeID,
+		"Version":    increment("Version"),
+	})
+	return err
+}
+
+// BumpVersion -> increments the version of the todo, version 0 skips the check
+// a todo no longer at the version fails with PreconditionFailed
+func (c TodoRepository) BumpVersion(ID int64, version int64) error {
+	return bumpVersion(c.scoped().Query().Scopes(c.base.byID(ID)), "Version", version)
+}
+
+// ClearColumns -> sets the columns of the todo to NULL
+func (c TodoRepository) ClearColumns(ID int64, columns []string) error {
+	updates := make(map[string]interface{}, len(columns))
+	for _, column := range columns {
+		updates[column] = nil
+	}
+	_, err := c.scoped().UpdateByID(ID, updates)
+	return err
+}
+
+// ClearList -> moves the todos of the list out of it
+func (c TodoRepository) ClearList(listID int64) error {
+	return c.scoped().Query().
+		Where("? = ?", todoColumns.column("ListID"), listID).
+		Updates(map[string]interface{}{
+			"ListID":  nil,
+			"Version": increment("Version"),
+		}).Error
+}
+
 // Create Todo
 func (c TodoRepository) Create(Todo models.Todo) (models.Todo, error) {
-	return Todo, c.db.DB.Create(&Todo).Error
+	if c.tenantID != 0 {
+		Todo.OrganizationID = &c.tenantID
+	}
+	Todo.Version = 1
+	return Todo, c.base.Create(&Todo)
 }
 
 // GetAllTodo -> Get All todos
-func (c TodoRepository) GetAllTodo(pagination utils.Pagination) ([]models.Todo, int64, error) {
-	var todos []models.Todo
-	var totalRows int64 = 0
-	queryBuider := c.db.DB.Model(&models.Todo{}).Offset(pagination.Offset).Order(pagination.Sort)
-
-	if !pagination.All {
-		queryBuider = queryBuider.Limit(pagination.PageSize)
+func (c TodoRepository) GetAllTodo(pagination utils.Pagination, filter TodoFilter) ([]models.Todo, utils.PageInfo, error) {
+	page := utils.PageInfo{}
+	query, err := compileListQuery(TodoQueryFields, pagination)
+	if err != nil {
+		return nil, page, err
 	}
-
-	err := queryBuider.
-		Find(&todos).
-		Offset(-1).
-		Limit(-1).
-		Count(&totalRows).Error
-	return todos, totalRows, err
+	fieldset, err := TodoFieldsetRules.Compile(c.base.DB(), &models.Todo{}, c.fieldset)
+	if err != nil {
+		return nil, page, err
+	}
+	todos, page, err := c.visible().Page(
+		c.visible().Query().Scopes(filter.scope, query.filterScope()),
+		pagination,
+		query.orderScope(desc(todoColumns.column("CreateDateTime"))),
+		fieldset.scope(),
+	)
+	if err != nil {
+		return nil, page, err
+	}
+	return todos, page, c.decorate(todos)
 }
 
 // GetOneTodo -> Get One Todo By Id
 func (c TodoRepository) GetOneTodo(ID int64) (models.Todo, error) {
-	Todo := models.Todo{}
-	return Todo, c.db.DB.
-		Where("id = ?", ID).First(&Todo).Error
+	fieldset, err := TodoFieldsetRules.Compile(c.base.DB(), &models.Todo{}, c.fieldset)
+	if err != nil {
+		return models.Todo{}, err
+	}
+	Todo, err := c.visible().FindByID(ID, fieldset.scope())
+	if err != nil {
+		return Todo, err
+	}
+	todos := []models.Todo{Todo}
+	err = c.decorate(todos)
+	return todos[0], err
 }
 
 // UpdateOneTodo -> Update One Todo By Id
 func (c TodoRepository) UpdateOneTodo(Todo models.Todo) error {
-	return c.db.DB.Model(&models.Todo{}).
-		Where("id = ?", Todo.ID).
-		Updates(Todo).Error
+	_, err := c.scoped().UpdateByID(Todo.ID, Todo)
+	return err
+}
+
+// DeleteOneTodo -> Delete One Todo By Id, version 0 skips the version check
+func (c TodoRepository) DeleteOneTodo(ID int64, version int64) error {
+	deleted, err := c.scoped().DeleteByID(ID, versionScope(todoColumns, "Version", version))
+	if err == nil && version != 0 && deleted == 0 {
+		return versionConflict()
+	}
+	return err
+}
+
+// GetDeletedTodos -> soft deleted todos, most recently deleted first
+func (c TodoRepository) GetDeletedTodos(pagination utils.Pagination) ([]models.Todo, utils.PageInfo, error) {
+	return c.scoped().FindDeleted(pagination, func(db *gorm.DB) *gorm.DB {
+		if pagination.Keyword == "" {
+			return db
+		}
+		return db.Where(contains(todoColumns.column("Task"), pagination.Keyword))
+	})
+}
+
+// RestoreTodo -> clears the soft delete of the todo, false when there is no such deleted todo
+func (c TodoRepository) RestoreTodo(ID int64) (bool, error) {
+	return c.scoped().Restore(ID, map[string]interface{}{
+		"Version": increment("Version"),
+	})
+}
+
+// PurgeTodo -> permanently deletes a soft deleted todo, false when there is no such deleted todo
+func (c TodoRepository) PurgeTodo(ID int64) (bool, error) {
+	return c.scoped().Purge(ID)
 }
 
-// DeleteOneTodo -> Delete One Todo By Id
-func (c TodoRepository) DeleteOneTodo(ID int64) error {
-	return c.db.DB.
-		Where("id = ?", ID).
-		Delete(&models.Todo{}).
-		Error
+// PurgeExpiredTodos -> permanently deletes todos of every organization soft deleted before the time
+func (c TodoRepository) PurgeExpiredTodos(before time.Time) (int64, error) {
+	return c.base.PurgeDeletedBefore(before)
 }
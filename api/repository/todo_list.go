@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// TodoListRepository database structure
+type TodoListRepository struct {
+	db       infrastructure.Database
+	logger   infrastructure.Logger
+	tenantID int64
+}
+
+// NewTodoListRepository creates a new TodoList repository
+func NewTodoListRepository(db infrastructure.Database, logger infrastructure.Logger) TodoListRepository {
+	return TodoListRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithTrx enables repository with transaction
+func (c TodoListRepository) WithTrx(trxHandle *gorm.DB) TodoListRepository {
+	if trxHandle == nil {
+		c.logger.Zap.Error("Transaction Database not found in gin context. ")
+		return c
+	}
+	c.db.DB = trxHandle
+	return c
+}
+
+// WithTenant limits repository queries to the organization
+func (c TodoListRepository) WithTenant(tenantID int64) TodoListRepository {
+	c.tenantID = tenantID
+	return c
+}
+
+// Create TodoList
+func (c TodoListRepository) Create(list models.TodoList) (models.TodoList, error) {
+	list.OrganizationID = nil
+	if c.tenantID != 0 {
+		list.OrganizationID = &c.tenantID
+	}
+	return list, c.db.DB.Create(&list).Error
+}
+
+// GetAllTodoList -> Get All todo lists
+func (c TodoListRepository) GetAllTodoList(pagination utils.Pagination) ([]models.TodoList, int64, error) {
+	var lists []models.TodoList
+	var totalRows int64 = 0
+	query, err := TodoListQueryFields.Compile(pagination.Query)
+	if err != nil {
+		return nil, 0, err
+	}
+	queryBuilder := c.db.DB.Model(&models.TodoList{}).Scopes(todoListTenantScope(c.tenantID), query.filterScope())
+	if pagination.Keyword != "" {
+		queryBuilder = queryBuilder.Where(contains(column("TodoList", "Name"), pagination.Keyword))
+	}
+	queryBuilder = queryBuilder.Session(&gorm.Session{})
+
+	if err := queryBuilder.Count(&totalRows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	queryBuilder = queryBuilder.
+		Offset(pagination.Offset).
+		Scopes(query.orderScope(asc(column("TodoList", "Name"))))
+	if !pagination.All {
+		queryBuilder = queryBuilder.Limit(pagination.PageSize)
+	}
+	err = queryBuilder.Find(&lists).Error
+	return lists, totalRows, err
+}
+
+// GetOneTodoList -> Get One TodoList By Id
+func (c TodoListRepository) GetOneTodoList(ID int64) (models.TodoList, error) {
+	list := models.TodoList{}
+	return list, c.db.DB.Scopes(todoListTenantScope(c.tenantID)).
+		Where("? = ?", column("TodoList", "ID"), ID).First(&list).Error
+}
+
+// UpdateOneTodoList -> Update One TodoList By Id
+func (c TodoListRepository) UpdateOneTodoList(list models.TodoList) error {
+	return c.db.DB.Model(&models.TodoList{}).Scopes(todoListTenantScope(c.tenantID)).
+		Where("? = ?", column("TodoList", "ID"), list.ID).
+		Updates(map[string]interface{}{
+			"Name":        list.Name,
+			"Description": list.Description,
+		}).Error
+}
+
+// DeleteOneTodoList -> Delete One TodoList By Id
+func (c TodoListRepository) DeleteOneTodoList(ID int64) error {
+	return c.db.DB.Scopes(todoListTenantScope(c.tenantID)).
+		Where("? = ?", column("TodoList", "ID"), ID).
+		Delete(&models.TodoList{}).
+		Error
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TodoShareRepository -> collaborators and share links of todos
+type TodoShareRepository struct {
+	db     infrastructure.Database
+	logger infrastructure.Logger
+}
+
+// NewTodoShareRepository -> creates a new TodoShare repository
+func NewTodoShareRepository(db infrastructure.Database, logger infrastructure.Logger) TodoShareRepository {
+	return TodoShareRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithTrx enables repository with transaction
+func (c TodoShareRepository) WithTrx(trxHandle *gorm.DB) TodoShareRepository {
+	if trxHandle == nil {
+		c.logger.Zap.Error("Transaction Database not found in gin context. ")
+		return c
+	}
+	c.db.DB = trxHandle
+	return c
+}
+
+// GetCollaborators -> collaborators of the todo with their public columns
+func (c TodoShareRepository) GetCollaborators(todoID int64) ([]models.TodoCollaborator, error) {
+	var collaborators []models.TodoCollaborator
+	return collaborators, c.db.DB.
+		Preload("User", publicUserColumns).
+		Where(map[string]interface{}{"TodoID": todoID}).
+		Order(asc(column("TodoCollaborator", "CreateDateTime"))).
+		Find(&collaborators).Error
+}
+
+// GetCollaborator -> collaboration of the user on the todo
+func (c TodoShareRepository) GetCollaborator(todoID int64, userID int64) (models.TodoCollaborator, error) {
+	collaborator := models.TodoCollaborator{}
+	return collaborator, c.db.DB.
+		Where(map[string]interface{}{"TodoID": todoID, "UserID": userID}).
+		First(&collaborator).Error
+}
+
+// SetCollaborator -> adds the collaborator or changes its permission
+func (c TodoShareRepository) SetCollaborator(collaborator models.TodoCollaborator) error {
+	return c.db.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "TodoID"}, {Name: "UserID"}},
+		DoUpdates: clause.AssignmentColumns([]string{"Permission"}),
+	}).Omit("User").Create(&collaborator).Error
+}
+
+// CopyCollaborators -> shares the todo with the collaborators of another one
+func (c TodoShareRepository) CopyCollaborators(fromTodoID int64, toTodoID int64) error {
+	collaborators, err := c.GetCollaborators(fromTodoID)
+	if err != nil || len(collaborators) == 0 {
+		return err
+	}
+	for i := range collaborators {
+		collaborators[i].TodoID = toTodoID
+		collaborators[i].User = nil
+	}
+	return c.db.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&collaborators).Error
+}
+
+// DeleteCollaborator -> stops sharing the todo with the user
+func (c TodoShareRepository) DeleteCollaborator(todoID int64, userID int64) error {
+	return c.db.DB.
+		Where(map[string]interface{}{"TodoID": todoID, "UserID": userID}).
+		Delete(&models.TodoCollaborator{}).Error
+}
+
+// CreateShare -> TodoShare
+func (c TodoShareRepository) CreateShare(share *models.TodoShare) error {
+	return c.db.DB.Create(share).Error
+}
+
+// GetShares -> share links of the todo
+func (c TodoShareRepository) GetShares(todoID int64) ([]models.TodoShare, error) {
+	var shares []models.TodoShare
+	return shares, c.db.DB.
+		Where(map[string]interface{}{"TodoID": todoID}).
+		Order(desc(column("TodoShare", "CreateDateTime"))).
+		Find(&shares).Error
+}
+
+// GetShareByToken -> share link with the token
+func (c TodoShareRepository) GetShareByToken(token string) (models.TodoShare, error) {
+	share := models.TodoShare{}
+	return share, c.db.DB.
+		Where(map[string]interface{}{"Token": token}).
+		First(&share).Error
+}
+
+// DeleteShare -> revokes the share link of the todo
+func (c TodoShareRepository) DeleteShare(todoID int64, ID int64) (bool, error) {
+	result := c.db.DB.
+		Where(map[string]interface{}{"TodoID": todoID, "ID": ID}).
+		Delete(&models.TodoShare{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -1,104 +1,162 @@
 package repository
 
 import (
+	"boilerplate-api/constants"
 	"boilerplate-api/errors"
 	"boilerplate-api/infrastructure"
 	"boilerplate-api/models"
 	"boilerplate-api/utils"
-	"encoding/json"
-	"fmt"
-	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// userColumns -> columns of the user table the generic queries use
+var userColumns = Columns{Table: "user", ID: "id", CreatedAt: "created_at", DeletedAt: "deleted_at"}
+
+// publicUserColumns -> columns of a user shown to other users, for preloaded authors, commenters and collaborators
+func publicUserColumns(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "username", "full_name")
+}
+
 // UserRepository -> database structure
 type UserRepository struct {
-	db     infrastructure.Database
-	logger infrastructure.Logger
+	base     Repository[models.User]
+	logger   infrastructure.Logger
+	tenantID int64
+	viewerID int64
+	fieldset utils.Fieldset
 }
 
 // NewUserRepository -> creates a new User repository
 func NewUserRepository(db infrastructure.Database, logger infrastructure.Logger) UserRepository {
 	return UserRepository{
-		db:     db,
+		base:   NewRepository[models.User](db, logger, userColumns),
 		logger: logger,
 	}
 }
 
 // WithTrx enables repository with transaction
 func (c UserRepository) WithTrx(trxHandle *gorm.DB) UserRepository {
-	if trxHandle == nil {
-		c.logger.Zap.Error("Transaction Database not found in gin context. ")
-		return c
-	}
-	c.db.DB = trxHandle
+	c.base = c.base.WithTrx(trxHandle)
 	return c
 }
 
+// WithTenant limits repository queries to the organization
+func (c UserRepository) WithTenant(tenantID int64) UserRepository {
+	c.tenantID = tenantID
+	return c
+}
+
+// WithViewer limits repository queries outside of an organization to the user's own record
+func (c UserRepository) WithViewer(userID int64) UserRepository {
+	c.viewerID = userID
+	return c
+}
+
+// WithFieldset limits the selected fields and preloads the included relations
+func (c UserRepository) WithFieldset(fieldset utils.Fieldset) UserRepository {
+	c.fieldset = fieldset
+	return c
+}
+
+// scoped -> queries limited to the organization
+func (c UserRepository) scoped() Repository[models.User] {
+	return c.base.Scoped(userTenantScope(c.tenantID, c.viewerID))
+}
+
 // Save -> User
 func (c UserRepository) Create(User *models.User) (*models.User, error) {
-	c.logger.Zap.Info(User, "---User")
-	b, err := json.MarshalIndent(User, "", "")
-	if err == nil {
-		fmt.Println(string(b))
+	if c.tenantID != 0 {
+		User.OrganizationID = &c.tenantID
 	}
-	if err := c.db.DB.Create(&User).Error; err != nil {
+	User.Version = 1
+	if err := c.base.Create(User); err != nil {
 
 		return nil, err
 	}
+	if c.tenantID != 0 {
+		membership := models.OrganizationMember{
+			OrganizationID: c.tenantID,
+			UserID:         User.ID,
+			Role:           User.Role,
+		}
+		if membership.Role == "" {
+			membership.Role = constants.RoleClientUser
+		}
+		if err := c.base.DB().Create(&membership).Error; err != nil {
+			return nil, err
+		}
+	}
 	return User, nil
 }
 
 // GetAllUser -> Get All users
-func (c UserRepository) GetAllUsers(pagination utils.Pagination) ([]models.User, int64, error) {
-	var users []models.User
-	var totalRows int64 = 0
-	queryBuilder := c.db.DB.Limit(pagination.PageSize).Offset(pagination.Offset).Order("created_at desc")
-	queryBuilder = queryBuilder.Model(&models.User{})
-
-	if pagination.Keyword != "" {
-		searchQuery := "%" + pagination.Keyword + "%"
-		queryBuilder.Where(c.db.DB.Where("`user`.`username` LIKE ?", searchQuery).
-			Or("`user`.`username` LIKE ?", searchQuery).
-			Or("`user`.`email` LIKE ?", searchQuery).
-			Or("`user`.`full_name` LIKE ?", searchQuery))
+func (c UserRepository) GetAllUsers(pagination utils.Pagination) ([]models.User, utils.PageInfo, error) {
+	page := utils.PageInfo{}
+	query, err := compileListQuery(UserQueryFields, pagination)
+	if err != nil {
+		return nil, page, err
+	}
+	search, err := newUserSearch(c.base.DB(), pagination)
+	if err != nil {
+		return nil, page, err
 	}
+	fieldset, err := UserFieldsetRules.Compile(c.base.DB(), &models.User{}, c.fieldset)
+	if err != nil {
+		return nil, page, err
+	}
+	users, page, err := c.scoped().Page(
+		c.scoped().Query().Scopes(query.filterScope(), search.filterScope()),
+		pagination,
+		query.orderScope(search.defaultOrder()...),
+		fieldset.scope(), search.relevanceScope(fieldset.selectColumns()),
+	)
+	if err != nil {
+		return nil, page, err
+	}
+	search.highlight(users)
+	return users, page, nil
+}
 
-	err := queryBuilder.
-		Find(&users).
-		Offset(-1).
-		Limit(-1).
-		Count(&totalRows).Error
-	return users, totalRows, err
+// ExportUsers -> hands the users matching the filters and keyword of GetAllUsers to fn in batches, ordered by id
+func (c UserRepository) ExportUsers(pagination utils.Pagination, batchSize int, fn func(users []models.User) error) error {
+	query, err := compileListQuery(UserQueryFields, pagination)
+	if err != nil {
+		return err
+	}
+	search, err := newUserSearch(c.base.DB(), pagination)
+	if err != nil {
+		return err
+	}
+	var users []models.User
+	return c.scoped().Query().
+		Scopes(query.filterScope(), search.filterScope()).
+		FindInBatches(&users, batchSize, func(tx *gorm.DB, batch int) error {
+			return fn(users)
+		}).Error
 }
 
 // Partial update of user
 func (c UserRepository) UpdatePartial(ID int64, map_update map[string]interface{}) (*models.User, error) {
-	user := models.User{}
-	if err := c.db.DB.Model(&models.User{}).
-		Where("id = ?", ID).
-		Updates(map_update).Find(&user).Error; err != nil {
-		if strings.Contains(err.Error(), "1062") {
-			err = errors.BadRequest.Wrap(err, "Error updating user")
-			custom_msg := ""
-			if strings.Contains(err.Error(), "UQ_user_email") {
-				custom_msg = "Email address already taken"
-			} else if strings.Contains(err.Error(), "users.UQ_user_phone") {
-				custom_msg = "Phone number already taken"
-			}
-			err = errors.SetCustomMessage(err, custom_msg)
-		} else {
-			err = errors.InternalError.Wrap(err, "Error updating user")
-		}
-		return nil, err
+	if _, err := c.scoped().UpdateByID(ID, withVersionBump(map_update, "version")); err != nil {
+		return nil, userUpdateError(err)
+	}
+	user, err := c.scoped().FindByID(ID)
+	if err != nil {
+		return nil, userUpdateError(err)
 	}
 	return &user, nil
 }
 
-func (c UserRepository) GetOneUser(Id string) (*models.User, error) {
-	user := models.User{}
-	if err := c.db.DB.First(&user, Id).Error; err != nil {
+// GetOneUser -> user by id, with the fields of the fieldset
+func (c UserRepository) GetOneUser(ID int64) (*models.User, error) {
+	fieldset, err := UserFieldsetRules.Compile(c.base.DB(), &models.User{}, c.fieldset)
+	if err != nil {
+		return nil, err
+	}
+	user, err := c.scoped().FindByID(ID, fieldset.scope())
+	if err != nil {
 		return nil, err
 	}
 	return &user, nil
@@ -107,37 +165,83 @@ func (c UserRepository) GetOneUser(Id string) (*models.User, error) {
 
 func (c UserRepository) GetOneUserWithEmail(Email string) (*models.User, error) {
 	user := models.User{}
-	if err := c.db.DB.First(&user, "email = ?", Email).Error; err != nil {
+	if err := c.base.DB().Preload("Memberships").First(&user, "email = ?", Email).Error; err != nil {
 		return nil, err
 	}
 	return &user, nil
 
 }
 
-func (c UserRepository) DeleteOneUser(Id string) (*string, error) {
-	user := models.User{}
-	err := c.db.DB.First(&user, Id).Delete(&user, Id).Error
+// DeleteOneUser -> soft deletes the user, version 0 skips the version check
+func (c UserRepository) DeleteOneUser(ID int64, version int64) (*string, error) {
+	user, err := c.scoped().FindByID(ID)
+	if err != nil {
+		return &user.FirebaseUID, err
+	}
+	deleted, err := c.scoped().DeleteByID(ID, versionScope(userColumns, "version", version))
+	if err == nil && version != 0 && deleted == 0 {
+		return nil, versionConflict()
+	}
 	return &user.FirebaseUID, err
 }
 
-func (c UserRepository) UpdateUser(Id string, mapData map[string]interface{}) (*models.User, error) {
-	user := models.User{}
-	if err := c.db.DB.First(&user, Id).
-		Updates(mapData).
-		Find(&user, Id).Error; err != nil {
-		if strings.Contains(err.Error(), "1062") {
-			err = errors.BadRequest.Wrap(err, "Error updating user")
-			custom_msg := ""
-			if strings.Contains(err.Error(), "UQ_user_email") {
-				custom_msg = "Email address already taken"
-			} else if strings.Contains(err.Error(), "users.UQ_user_phone") {
-				custom_msg = "Phone number already taken"
-			}
-			err = errors.SetCustomMessage(err, custom_msg)
-		} else {
-			err = errors.InternalError.Wrap(err, "Error updating user")
-		}
-		return nil, err
+// UpdateUser -> updates the columns of the user, version 0 skips the version check
+func (c UserRepository) UpdateUser(ID int64, mapData map[string]interface{}, version int64) (*models.User, error) {
+	if _, err := c.scoped().FindByID(ID); err != nil {
+		return nil, userUpdateError(err)
+	}
+	updated, err := c.scoped().UpdateByID(ID, withVersionBump(mapData, "version"), versionScope(userColumns, "version", version))
+	if err != nil {
+		return nil, userUpdateError(err)
+	}
+	if updated == 0 {
+		return nil, versionConflict()
+	}
+	user, err := c.scoped().FindByID(ID)
+	if err != nil {
+		return nil, userUpdateError(err)
 	}
 	return &user, nil
 }
+
+// userUpdateError -> duplicate email or phone stay Conflict, anything else as InternalError
+func userUpdateError(err error) error {
+	if errors.GetErrorType(err) == errors.Conflict {
+		return err
+	}
+	return errors.InternalError.Wrap(err, "Error updating user")
+}
+
+// GetDeletedUsers -> soft deleted users, most recently deleted first
+func (c UserRepository) GetDeletedUsers(pagination utils.Pagination) ([]models.User, utils.PageInfo, error) {
+	return c.scoped().FindDeleted(pagination, func(db *gorm.DB) *gorm.DB {
+		if pagination.Keyword == "" {
+			return db
+		}
+		return db.Where(contains(userColumns.column("email"), pagination.Keyword))
+	})
+}
+
+// GetDeletedUser -> soft deleted user by id
+func (c UserRepository) GetDeletedUser(ID int64) (models.User, error) {
+	return c.scoped().FindDeletedByID(ID)
+}
+
+// RestoreUser -> clears the soft delete of the user
+func (c UserRepository) RestoreUser(ID int64) error {
+	_, err := c.scoped().Restore(ID, map[string]interface{}{
+		"version": increment("version"),
+	})
+	return err
+}
+
+// PurgeUser -> permanently deletes a soft deleted user
+func (c UserRepository) PurgeUser(ID int64) error {
+	_, err := c.scoped().Purge(ID)
+	return err
+}
+
+// GetExpiredUsers -> users of every organization soft deleted before the time
+func (c UserRepository) GetExpiredUsers(before time.Time, limit int) ([]models.User, error) {
+	return c.base.FindDeletedBefore(before, limit)
+}
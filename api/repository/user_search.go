@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// SearchModeNatural -> natural language full-text search, ranked by relevance
+	SearchModeNatural = "natural"
+
+	// SearchModeBoolean -> full-text search supporting +, -, * and "phrase" operators
+	SearchModeBoolean = "boolean"
+
+	// fulltextMinTokenLength -> innodb_ft_min_token_size, shorter words are not indexed
+	fulltextMinTokenLength = 3
+)
+
+// userSearchColumns -> columns covered by the `IDX_user_search` FULLTEXT index, only searched on MySQL
+const userSearchColumns = "`user`.`username`, `user`.`email`, `user`.`full_name`"
+
+// userSearch -> keyword search on users, full-text on MySQL and LIKE elsewhere
+type userSearch struct {
+	keyword  string
+	mode     string
+	fulltext bool
+	include  []string
+	exclude  []string
+}
+
+// newUserSearch validates the search mode and decides whether the FULLTEXT index can be used
+// full-text search is only paged with offsets, LIKE matches keep the created_at order and cursors
+func newUserSearch(db *gorm.DB, pagination utils.Pagination) (userSearch, error) {
+	search := userSearch{keyword: strings.TrimSpace(pagination.Keyword), mode: pagination.SearchMode}
+	if search.mode == "" {
+		search.mode = SearchModeNatural
+	}
+	if search.mode != SearchModeNatural && search.mode != SearchModeBoolean {
+		err := errors.BadRequest.Newf("unknown search mode %s", search.mode)
+		err = errors.SetCustomMessage(err, "Invalid search mode")
+		return search, errors.AddErrorContext(err, "searchMode", "Search mode must be 'natural' or 'boolean'.")
+	}
+	search.include, search.exclude = utils.SearchTerms(search.keyword)
+
+	search.fulltext = db.Dialector.Name() == "mysql" && len(search.include) > 0
+	for _, term := range search.include {
+		if len(term) < fulltextMinTokenLength {
+			search.fulltext = false
+		}
+	}
+	// full-text matches are ranked by relevance, which a created_at cursor can not page through
+	if search.fulltext && pagination.CursorMode {
+		err := errors.BadRequest.New("full-text search is not supported with cursor pagination")
+		err = errors.SetCustomMessage(err, "Invalid search query")
+		return search, errors.AddErrorContext(err, "cursor", "Keyword search is ranked by relevance, use page instead of cursor.")
+	}
+	return search, nil
+}
+
+// active reports whether a keyword was given
+func (s userSearch) active() bool {
+	return len(s.include) > 0 || len(s.exclude) > 0
+}
+
+// against -> MATCH ... AGAINST expression for the search mode
+func (s userSearch) against() string {
+	if s.mode == SearchModeBoolean {
+		return "MATCH(" + userSearchColumns + ") AGAINST (? IN BOOLEAN MODE)"
+	}
+	return "MATCH(" + userSearchColumns + ") AGAINST (? IN NATURAL LANGUAGE MODE)"
+}
+
+// filterScope -> limits users to the ones matching the keyword
+func (s userSearch) filterScope() func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !s.active() {
+			return db
+		}
+		if s.fulltext {
+			return db.Where(s.against(), s.keyword)
+		}
+		// LIKE fallback, every term has to match one of the columns
+		for _, term := range s.include {
+			db = db.Where(s.matches(term))
+		}
+		for _, term := range s.exclude {
+			db = db.Where(clause.Not(s.matches(term)))
+		}
+		return db
+	}
+}
+
+// matches -> the term is contained in one of the searched columns
+func (s userSearch) matches(term string) clause.Expression {
+	return clause.Or(
+		contains(userColumns.column("username"), term),
+		contains(userColumns.column("email"), term),
+		contains(userColumns.column("full_name"), term),
+	)
+}
+
+// relevanceScope -> selects the relevance of full-text matches along with the columns
+func (s userSearch) relevanceScope(columns string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !s.active() || !s.fulltext {
+			return db
+		}
+		return db.Select(columns+", "+s.against()+" AS relevance", s.keyword)
+	}
+}
+
+// defaultOrder -> most relevant first when searching full-text, newest first otherwise
+func (s userSearch) defaultOrder() []clause.OrderByColumn {
+	if s.active() && s.fulltext {
+		return []clause.OrderByColumn{desc(clause.Column{Name: "relevance"}), desc(userColumns.column("created_at"))}
+	}
+	return []clause.OrderByColumn{desc(userColumns.column("created_at"))}
+}
+
+// highlight marks the matched terms in the searched fields of the users
+func (s userSearch) highlight(users []models.User) {
+	if len(s.include) == 0 {
+		return
+	}
+	for i := range users {
+		highlights := map[string]string{}
+		fields := map[string]string{
+			"username":  users[i].Username,
+			"email":     users[i].Email,
+			"full_name": users[i].FullName,
+		}
+		for name, value := range fields {
+			if marked, ok := utils.Highlight(value, s.include); ok {
+				highlights[name] = marked
+			}
+		}
+		if len(highlights) > 0 {
+			users[i].Highlights = highlights
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/utils"
+	"testing"
+)
+
+func TestNewUserSearch(t *testing.T) {
+	tests := []struct {
+		name         string
+		driver       string
+		pagination   utils.Pagination
+		wantFulltext bool
+		wantErr      bool
+		wantField    string
+	}{
+		{name: "mysql keyword", driver: "mysql", pagination: utils.Pagination{Keyword: "jane doe"}, wantFulltext: true},
+		{name: "mysql short term falls back to LIKE", driver: "mysql", pagination: utils.Pagination{Keyword: "jo"}},
+		{name: "postgres keyword", driver: "postgres", pagination: utils.Pagination{Keyword: "jane doe"}},
+		{name: "mysql keyword with cursor", driver: "mysql", pagination: utils.Pagination{Keyword: "jane", CursorMode: true}, wantErr: true, wantField: "cursor"},
+		{name: "mysql LIKE search with cursor", driver: "mysql", pagination: utils.Pagination{Keyword: "jo", CursorMode: true}},
+		{name: "sqlite keyword with cursor", driver: "sqlite", pagination: utils.Pagination{Keyword: "jane", CursorMode: true}},
+		{name: "mysql cursor without keyword", driver: "mysql", pagination: utils.Pagination{CursorMode: true}},
+		{name: "unknown search mode", driver: "mysql", pagination: utils.Pagination{Keyword: "jane", SearchMode: "fuzzy"}, wantErr: true, wantField: "searchMode"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			search, err := newUserSearch(dryRunDB(t, dryRunDialectors[test.driver]), test.pagination)
+			if test.wantErr {
+				if errors.GetErrorType(err) != errors.BadRequest {
+					t.Fatalf("error = %v, want BadRequest", err)
+				}
+				contexts := errors.GetErrorContext(err)
+				if len(contexts) != 1 || contexts[0].Field != test.wantField {
+					t.Errorf("error context = %+v, want %s", contexts, test.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if search.fulltext != test.wantFulltext {
+				t.Errorf("fulltext = %v, want %v", search.fulltext, test.wantFulltext)
+			}
+		})
+	}
+}
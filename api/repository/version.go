@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"boilerplate-api/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// bumpVersion -> increments the version column of the rows the query matches
+// version 0 skips the check, otherwise the row has to still be at the version
+func bumpVersion(query *gorm.DB, column string, version int64) error {
+	if version != 0 {
+		query = query.Where("? = ?", clause.Column{Name: column}, version)
+	}
+	result := query.UpdateColumn(column, increment(column))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return versionConflict()
+	}
+	return nil
+}
+
+// versionConflict -> the resource changed since the version the client has
+func versionConflict() error {
+	err := errors.PreconditionFailed.New("version mismatch")
+	return errors.SetCustomMessage(err, "The resource has been modified, fetch it again")
+}
+
+// withVersionBump -> copy of the updates that also increments the version column
+func withVersionBump(updates map[string]interface{}, column string) map[string]interface{} {
+	bumped := make(map[string]interface{}, len(updates)+1)
+	for key, value := range updates {
+		bumped[key] = value
+	}
+	bumped[column] = increment(column)
+	return bumped
+}
+
+// versionScope -> limits the query to rows still at the version, 0 skips the check
+func versionScope(columns Columns, column string, version int64) Scope {
+	return func(db *gorm.DB) *gorm.DB {
+		if version == 0 {
+			return db
+		}
+		return db.Where("? = ?", columns.column(column), version)
+	}
+}
+
+// increment -> expression adding one to the column
+func increment(column string) clause.Expr {
+	return gorm.Expr("? + 1", clause.Column{Name: column})
+}
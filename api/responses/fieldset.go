@@ -0,0 +1,51 @@
+package responses
+
+import (
+	"boilerplate-api/utils"
+	"encoding/json"
+)
+
+// Sparse -> trims the data (a model or a list of models) to the requested fields and includes
+// data is returned as is when no fields were asked for
+func Sparse(data interface{}, fieldset utils.Fieldset) interface{} {
+	if len(fieldset.Fields) == 0 {
+		return data
+	}
+	keep := map[string]bool{"id": true}
+	for _, name := range fieldset.Fields {
+		keep[name] = true
+	}
+	for _, name := range fieldset.Include {
+		keep[name] = true
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return data
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return data
+	}
+	switch value := decoded.(type) {
+	case []interface{}:
+		for _, item := range value {
+			trimFields(item, keep)
+		}
+	default:
+		trimFields(value, keep)
+	}
+	return decoded
+}
+
+func trimFields(item interface{}, keep map[string]bool) {
+	object, ok := item.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for key := range object {
+		if !keep[key] {
+			delete(object, key)
+		}
+	}
+}
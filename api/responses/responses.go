@@ -27,6 +27,21 @@ func JSONCount(c *gin.Context, statusCode int, data interface{}, count int64) {
 	c.JSON(statusCode, gin.H{"data": data, "count": count})
 }
 
+// JSONPage : json response function for paginated lists, count and cursors are left out when empty
+func JSONPage(c *gin.Context, statusCode int, data interface{}, count *int64, next string, prev string) {
+	response := gin.H{"data": data}
+	if count != nil {
+		response["count"] = *count
+	}
+	if next != "" {
+		response["next"] = next
+	}
+	if prev != "" {
+		response["prev"] = prev
+	}
+	c.JSON(statusCode, response)
+}
+
 type errResponse struct {
 	Message string      `json:"message"`
 	Error   string      `json:"error"`
@@ -35,6 +50,12 @@ type errResponse struct {
 
 // HandleError func
 func HandleError(c *gin.Context, err error) {
+	status, response := ErrorResponse(err)
+	c.JSON(status, gin.H{"error": response})
+}
+
+// ErrorResponse -> status code and body HandleError responds with for the error
+func ErrorResponse(err error) (int, interface{}) {
 	errorType := errors.GetErrorType(err)
 	status := errors.GetStatusCode(errorType)
 
@@ -55,5 +76,5 @@ func HandleError(c *gin.Context, err error) {
 	if errorContext != nil {
 		response.Errors = errorContext
 	}
-	c.JSON(status, gin.H{"error": &response})
+	return status, response
 }
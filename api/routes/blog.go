@@ -0,0 +1,55 @@
+package routes
+
+import (
+	"boilerplate-api/api/controllers"
+	"boilerplate-api/api/middlewares"
+	"boilerplate-api/infrastructure"
+)
+
+// BlogRoutes -> struct
+type BlogRoutes struct {
+	logger            infrastructure.Logger
+	router            infrastructure.Router
+	blogController    controllers.BlogController
+	trxMiddleware     middlewares.DBTransactionMiddleware
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare
+}
+
+// NewBlogRoutes -> creates new Blog controller
+func NewBlogRoutes(
+	logger infrastructure.Logger,
+	router infrastructure.Router,
+	blogController controllers.BlogController,
+	trxMiddleware middlewares.DBTransactionMiddleware,
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare,
+) BlogRoutes {
+	return BlogRoutes{
+		router:            router,
+		logger:            logger,
+		blogController:    blogController,
+		trxMiddleware:     trxMiddleware,
+		jwtAuthMiddleware: jwtAuthMiddleware,
+	}
+}
+
+// Setup blog routes
+func (c BlogRoutes) Setup() {
+	c.logger.Zap.Info(" Setting up Blog routes")
+	blogs := c.router.Gin.Group("/blogs")
+	{
+		blogs.GET("", c.blogController.GetPublishedBlogs)
+		blogs.GET("/:id", c.blogController.GetOnePublishedBlog)
+		blogs.GET("/slug/:slug", c.blogController.GetPublishedBlogBySlug)
+	}
+	authored := c.router.Gin.Group("/blogs").Use(c.jwtAuthMiddleware.Handle())
+	{
+		authored.GET("/drafts", c.blogController.GetDraftBlogs)
+		authored.GET("/drafts/:id", c.blogController.GetOneDraftBlog)
+		authored.POST("", c.trxMiddleware.DBTransactionHandle(), c.blogController.CreateBlog)
+		authored.PUT("/:id", c.trxMiddleware.DBTransactionHandle(), c.blogController.UpdateOneBlog)
+		authored.POST("/:id/publish", c.trxMiddleware.DBTransactionHandle(), c.blogController.PublishBlog)
+		authored.POST("/:id/unpublish", c.trxMiddleware.DBTransactionHandle(), c.blogController.UnpublishBlog)
+		authored.POST("/:id/image", c.trxMiddleware.DBTransactionHandle(), c.blogController.UploadBlogImage)
+		authored.DELETE("/:id", c.trxMiddleware.DBTransactionHandle(), c.blogController.DeleteOneBlog)
+	}
+}
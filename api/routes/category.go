@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"boilerplate-api/api/controllers"
+	"boilerplate-api/api/middlewares"
+	"boilerplate-api/infrastructure"
+)
+
+// CategoryRoutes -> struct
+type CategoryRoutes struct {
+	logger             infrastructure.Logger
+	router             infrastructure.Router
+	categoryController controllers.CategoryController
+	trxMiddleware      middlewares.DBTransactionMiddleware
+	jwtAuthMiddleware  middlewares.JWTAuthMiddleWare
+}
+
+// NewCategoryRoutes -> creates new Category controller
+func NewCategoryRoutes(
+	logger infrastructure.Logger,
+	router infrastructure.Router,
+	categoryController controllers.CategoryController,
+	trxMiddleware middlewares.DBTransactionMiddleware,
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare,
+) CategoryRoutes {
+	return CategoryRoutes{
+		router:             router,
+		logger:             logger,
+		categoryController: categoryController,
+		trxMiddleware:      trxMiddleware,
+		jwtAuthMiddleware:  jwtAuthMiddleware,
+	}
+}
+
+// Setup category routes
+func (c CategoryRoutes) Setup() {
+	c.logger.Zap.Info(" Setting up Category routes")
+	categories := c.router.Gin.Group("/categories")
+	{
+		categories.GET("", c.categoryController.GetAllCategory)
+		categories.GET("/:id", c.categoryController.GetOneCategory)
+	}
+	admin := c.router.Gin.Group("/categories").Use(c.jwtAuthMiddleware.HandleAdminOnly())
+	{
+		admin.POST("", c.trxMiddleware.DBTransactionHandle(), c.categoryController.CreateCategory)
+		admin.PUT("/:id", c.trxMiddleware.DBTransactionHandle(), c.categoryController.UpdateOneCategory)
+		admin.DELETE("/:id", c.trxMiddleware.DBTransactionHandle(), c.categoryController.DeleteOneCategory)
+	}
+}
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"boilerplate-api/api/controllers"
+	"boilerplate-api/api/middlewares"
+	"boilerplate-api/infrastructure"
+)
+
+// CommentRoutes -> struct
+type CommentRoutes struct {
+	logger            infrastructure.Logger
+	router            infrastructure.Router
+	commentController controllers.CommentController
+	trxMiddleware     middlewares.DBTransactionMiddleware
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare
+}
+
+// NewCommentRoutes -> creates new Comment controller
+func NewCommentRoutes(
+	logger infrastructure.Logger,
+	router infrastructure.Router,
+	commentController controllers.CommentController,
+	trxMiddleware middlewares.DBTransactionMiddleware,
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare,
+) CommentRoutes {
+	return CommentRoutes{
+		router:            router,
+		logger:            logger,
+		commentController: commentController,
+		trxMiddleware:     trxMiddleware,
+		jwtAuthMiddleware: jwtAuthMiddleware,
+	}
+}
+
+// Setup comment routes
+func (c CommentRoutes) Setup() {
+	c.logger.Zap.Info(" Setting up Comment routes")
+	c.router.Gin.GET("/blogs/:id/comments", c.commentController.GetBlogComments)
+	c.router.Gin.POST("/blogs/:id/comments", c.jwtAuthMiddleware.Handle(), c.trxMiddleware.DBTransactionHandle(), c.commentController.CreateComment)
+
+	comments := c.router.Gin.Group("/comments").Use(c.jwtAuthMiddleware.Handle())
+	{
+		comments.PUT("/:id", c.commentController.UpdateComment)
+		comments.DELETE("/:id", c.trxMiddleware.DBTransactionHandle(), c.commentController.DeleteComment)
+	}
+	moderation := c.router.Gin.Group("/comments").Use(c.jwtAuthMiddleware.HandleAdminOnly())
+	{
+		moderation.GET("/moderation", c.commentController.GetModerationQueue)
+		moderation.POST("/:id/approve", c.commentController.ApproveComment)
+		moderation.POST("/:id/reject", c.commentController.RejectComment)
+	}
+}
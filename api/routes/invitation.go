@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"boilerplate-api/api/controllers"
+	"boilerplate-api/api/middlewares"
+	"boilerplate-api/infrastructure"
+)
+
+// InvitationRoutes -> struct
+type InvitationRoutes struct {
+	logger               infrastructure.Logger
+	router               infrastructure.Router
+	invitationController controllers.InvitationController
+	trxMiddleware        middlewares.DBTransactionMiddleware
+	jwtAuthMiddleware    middlewares.JWTAuthMiddleWare
+}
+
+// NewInvitationRoutes -> creates new invitation routes
+func NewInvitationRoutes(
+	logger infrastructure.Logger,
+	router infrastructure.Router,
+	invitationController controllers.InvitationController,
+	trxMiddleware middlewares.DBTransactionMiddleware,
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare,
+) InvitationRoutes {
+	return InvitationRoutes{
+		logger:               logger,
+		router:               router,
+		invitationController: invitationController,
+		trxMiddleware:        trxMiddleware,
+		jwtAuthMiddleware:    jwtAuthMiddleware,
+	}
+}
+
+// Setup invitation routes
+func (i InvitationRoutes) Setup() {
+	i.logger.Zap.Info(" Setting up invitation routes")
+	invitations := i.router.Gin.Group("/invitations").Use(i.jwtAuthMiddleware.HandleAdminOnly())
+	{
+		invitations.GET("", i.invitationController.GetPendingInvitations)
+		invitations.POST("", i.trxMiddleware.DBTransactionHandle(), i.invitationController.CreateInvitation)
+		invitations.POST("/:id/resend", i.trxMiddleware.DBTransactionHandle(), i.invitationController.ResendInvitation)
+		invitations.DELETE("/:id", i.invitationController.RevokeInvitation)
+	}
+	accept := i.router.Gin.Group("/invitation-accept")
+	{
+		accept.POST("", i.trxMiddleware.DBTransactionHandle(), i.invitationController.AcceptInvitation)
+	}
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"boilerplate-api/api/controllers"
+	"boilerplate-api/api/middlewares"
+	"boilerplate-api/infrastructure"
+)
+
+// OrganizationRoutes -> struct
+type OrganizationRoutes struct {
+	logger                 infrastructure.Logger
+	router                 infrastructure.Router
+	organizationController controllers.OrganizationController
+	trxMiddleware          middlewares.DBTransactionMiddleware
+	jwtAuthMiddleware      middlewares.JWTAuthMiddleWare
+}
+
+// NewOrganizationRoutes -> creates new organization routes
+func NewOrganizationRoutes(
+	logger infrastructure.Logger,
+	router infrastructure.Router,
+	organizationController controllers.OrganizationController,
+	trxMiddleware middlewares.DBTransactionMiddleware,
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare,
+) OrganizationRoutes {
+	return OrganizationRoutes{
+		logger:                 logger,
+		router:                 router,
+		organizationController: organizationController,
+		trxMiddleware:          trxMiddleware,
+		jwtAuthMiddleware:      jwtAuthMiddleware,
+	}
+}
+
+// Setup organization routes
+func (i OrganizationRoutes) Setup() {
+	i.logger.Zap.Info(" Setting up organization routes")
+	organizations := i.router.Gin.Group("/organization").Use(i.jwtAuthMiddleware.Handle())
+	{
+		organizations.GET("", i.organizationController.GetMyOrganizations)
+		organizations.POST("", i.trxMiddleware.DBTransactionHandle(), i.organizationController.CreateOrganization)
+	}
+}
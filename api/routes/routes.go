@@ -8,6 +8,13 @@ var Module = fx.Options(
 	fx.Provide(NewUtilityRoutes),
 	fx.Provide(NewUserRoutes),
 	fx.Provide(NewTodoRoutes),
+	fx.Provide(NewTodoListRoutes),
+	fx.Provide(NewOrganizationRoutes),
+	fx.Provide(NewInvitationRoutes),
+	fx.Provide(NewBlogRoutes),
+	fx.Provide(NewCategoryRoutes),
+	fx.Provide(NewCommentRoutes),
+	fx.Provide(NewTrashRoutes),
 )
 
 // Routes contains multiple routes
@@ -23,11 +30,25 @@ func NewRoutes(
 	utilityRoutes UtilityRoutes,
 	userRoutes UserRoutes,
 	todoRoutes TodoRoutes,
+	todoListRoutes TodoListRoutes,
+	organizationRoutes OrganizationRoutes,
+	invitationRoutes InvitationRoutes,
+	blogRoutes BlogRoutes,
+	categoryRoutes CategoryRoutes,
+	commentRoutes CommentRoutes,
+	trashRoutes TrashRoutes,
 ) Routes {
 	return Routes{
 		utilityRoutes,
 		userRoutes,
 		todoRoutes,
+		todoListRoutes,
+		organizationRoutes,
+		invitationRoutes,
+		blogRoutes,
+		categoryRoutes,
+		commentRoutes,
+		trashRoutes,
 	}
 }
 
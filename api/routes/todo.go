@@ -3,15 +3,19 @@ package routes
 import (
 	"boilerplate-api/api/controllers"
 	"boilerplate-api/api/middlewares"
+	"boilerplate-api/constants"
 	"boilerplate-api/infrastructure"
 )
 
 // TodoRoutes -> struct
 type TodoRoutes struct {
-	logger         infrastructure.Logger
-	router         infrastructure.Router
-	todoController controllers.TodoController
-	middleware     middlewares.FirebaseAuthMiddleware
+	logger            infrastructure.Logger
+	router            infrastructure.Router
+	todoController    controllers.TodoController
+	middleware        middlewares.FirebaseAuthMiddleware
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare
+	trxMiddleware     middlewares.DBTransactionMiddleware
+	idempotency       middlewares.IdempotencyMiddleware
 }
 
 // NewTodoRoutes -> creates new Todo controller
@@ -20,24 +24,45 @@ func NewTodoRoutes(
 	router infrastructure.Router,
 	todoController controllers.TodoController,
 	middleware middlewares.FirebaseAuthMiddleware,
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare,
+	trxMiddleware middlewares.DBTransactionMiddleware,
+	idempotency middlewares.IdempotencyMiddleware,
 ) TodoRoutes {
 	return TodoRoutes{
-		router:         router,
-		logger:         logger,
-		todoController: todoController,
-		middleware:     middleware,
+		router:            router,
+		logger:            logger,
+		todoController:    todoController,
+		middleware:        middleware,
+		jwtAuthMiddleware: jwtAuthMiddleware,
+		trxMiddleware:     trxMiddleware,
+		idempotency:       idempotency,
 	}
 }
 
 // Setup todo routes
 func (c TodoRoutes) Setup() {
 	c.logger.Zap.Info(" Setting up Todo routes")
-	todo := c.router.Gin.Group("/todo")
+	read := c.jwtAuthMiddleware.RequirePermission(constants.PermissionTodoRead)
+	write := c.jwtAuthMiddleware.RequirePermission(constants.PermissionTodoWrite)
+	todo := c.router.Gin.Group("/todo").Use(c.jwtAuthMiddleware.Handle())
 	{
-		todo.POST("", c.todoController.CreateTodo)
-		todo.GET("", c.todoController.GetAllTodo)
-		todo.GET("/:id", c.todoController.GetOneTodo)
-		todo.PUT("/:id", c.todoController.UpdateOneTodo)
-		todo.DELETE("/:id", c.todoController.DeleteOneTodo)
+		todo.POST("", write, c.trxMiddleware.DBTransactionHandle(), c.idempotency.Handle(), c.todoController.CreateTodo)
+		todo.GET("", read, c.todoController.GetAllTodo)
+		todo.POST("/bulk", write, c.trxMiddleware.DBTransactionHandle(), c.idempotency.Handle(), c.todoController.BulkTodo)
+		todo.GET("/tags", read, c.todoController.GetAllTags)
+		todo.GET("/recurrence/preview", read, c.todoController.PreviewOccurrences)
+		todo.GET("/:id", read, c.todoController.GetOneTodo)
+		todo.GET("/:id/occurrences", read, c.todoController.GetTodoOccurrences)
+		todo.PUT("/:id", write, c.trxMiddleware.DBTransactionHandle(), c.todoController.UpdateOneTodo)
+		todo.PATCH("/:id", write, c.trxMiddleware.DBTransactionHandle(), c.todoController.PatchOneTodo)
+		todo.DELETE("/:id", write, c.trxMiddleware.DBTransactionHandle(), c.todoController.DeleteOneTodo)
+		todo.PUT("/:id/assignee", write, c.trxMiddleware.DBTransactionHandle(), c.todoController.AssignTodo)
+		todo.GET("/:id/collaborators", read, c.todoController.GetCollaborators)
+		todo.PUT("/:id/collaborators", write, c.trxMiddleware.DBTransactionHandle(), c.todoController.SetCollaborator)
+		todo.DELETE("/:id/collaborators/:userId", write, c.trxMiddleware.DBTransactionHandle(), c.todoController.RemoveCollaborator)
+		todo.GET("/:id/shares", read, c.todoController.GetShares)
+		todo.POST("/:id/shares", write, c.trxMiddleware.DBTransactionHandle(), c.todoController.CreateShare)
+		todo.DELETE("/:id/shares/:shareId", write, c.trxMiddleware.DBTransactionHandle(), c.todoController.RevokeShare)
+		todo.POST("/shared/:token", read, c.trxMiddleware.DBTransactionHandle(), c.todoController.JoinShare)
 	}
 }
@@ -0,0 +1,49 @@
+package routes
+
+import (
+	"boilerplate-api/api/controllers"
+	"boilerplate-api/api/middlewares"
+	"boilerplate-api/constants"
+	"boilerplate-api/infrastructure"
+)
+
+// TodoListRoutes -> struct
+type TodoListRoutes struct {
+	logger             infrastructure.Logger
+	router             infrastructure.Router
+	todoListController controllers.TodoListController
+	trxMiddleware      middlewares.DBTransactionMiddleware
+	jwtAuthMiddleware  middlewares.JWTAuthMiddleWare
+}
+
+// NewTodoListRoutes -> creates new TodoList controller
+func NewTodoListRoutes(
+	logger infrastructure.Logger,
+	router infrastructure.Router,
+	todoListController controllers.TodoListController,
+	trxMiddleware middlewares.DBTransactionMiddleware,
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare,
+) TodoListRoutes {
+	return TodoListRoutes{
+		router:             router,
+		logger:             logger,
+		todoListController: todoListController,
+		trxMiddleware:      trxMiddleware,
+		jwtAuthMiddleware:  jwtAuthMiddleware,
+	}
+}
+
+// Setup todo list routes
+func (c TodoListRoutes) Setup() {
+	c.logger.Zap.Info(" Setting up Todo List routes")
+	read := c.jwtAuthMiddleware.RequirePermission(constants.PermissionTodoRead)
+	write := c.jwtAuthMiddleware.RequirePermission(constants.PermissionTodoWrite)
+	lists := c.router.Gin.Group("/todo-lists").Use(c.jwtAuthMiddleware.Handle())
+	{
+		lists.POST("", write, c.todoListController.CreateTodoList)
+		lists.GET("", read, c.todoListController.GetAllTodoList)
+		lists.GET("/:id", read, c.todoListController.GetOneTodoList)
+		lists.PUT("/:id", write, c.todoListController.UpdateOneTodoList)
+		lists.DELETE("/:id", write, c.trxMiddleware.DBTransactionHandle(), c.todoListController.DeleteOneTodoList)
+	}
+}
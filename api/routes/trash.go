@@ -0,0 +1,47 @@
+package routes
+
+import (
+	"boilerplate-api/api/controllers"
+	"boilerplate-api/api/middlewares"
+	"boilerplate-api/infrastructure"
+)
+
+// TrashRoutes -> struct
+type TrashRoutes struct {
+	logger            infrastructure.Logger
+	router            infrastructure.Router
+	trashController   controllers.TrashController
+	trxMiddleware     middlewares.DBTransactionMiddleware
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare
+}
+
+// NewTrashRoutes -> creates new Trash routes
+func NewTrashRoutes(
+	logger infrastructure.Logger,
+	router infrastructure.Router,
+	trashController controllers.TrashController,
+	trxMiddleware middlewares.DBTransactionMiddleware,
+	jwtAuthMiddleware middlewares.JWTAuthMiddleWare,
+) TrashRoutes {
+	return TrashRoutes{
+		router:            router,
+		logger:            logger,
+		trashController:   trashController,
+		trxMiddleware:     trxMiddleware,
+		jwtAuthMiddleware: jwtAuthMiddleware,
+	}
+}
+
+// Setup trash routes
+func (c TrashRoutes) Setup() {
+	c.logger.Zap.Info(" Setting up Trash routes")
+	trash := c.router.Gin.Group("/admin/trash").Use(c.jwtAuthMiddleware.HandleAdminOnly())
+	{
+		trash.GET("/users", c.trashController.GetDeletedUsers)
+		trash.POST("/users/:id/restore", c.trxMiddleware.DBTransactionHandle(), c.trashController.RestoreUser)
+		trash.DELETE("/users/:id", c.trxMiddleware.DBTransactionHandle(), c.trashController.PurgeUser)
+		trash.GET("/todos", c.trashController.GetDeletedTodos)
+		trash.POST("/todos/:id/restore", c.trashController.RestoreTodo)
+		trash.DELETE("/todos/:id", c.trashController.PurgeTodo)
+	}
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"boilerplate-api/api/controllers"
 	"boilerplate-api/api/middlewares"
+	"boilerplate-api/constants"
 	"boilerplate-api/infrastructure"
 )
 
@@ -14,18 +15,28 @@ type UserRoutes struct {
 	middleware        middlewares.FirebaseAuthMiddleware
 	trxMiddleware     middlewares.DBTransactionMiddleware
 	jwtAuthMiddleware middlewares.JWTAuthMiddleWare
+	idempotency       middlewares.IdempotencyMiddleware
 }
 
 // Setup user routes
 func (i UserRoutes) Setup() {
 	i.logger.Zap.Info(" Setting up user routes")
+	read := i.jwtAuthMiddleware.RequirePermission(constants.PermissionUserRead)
+	write := i.jwtAuthMiddleware.RequirePermission(constants.PermissionUserWrite)
 	users := i.router.Gin.Group("/user").Use(i.jwtAuthMiddleware.Handle())
 	{
-		users.GET("", i.userController.GetAllUsers)
-		users.GET("/:id", i.userController.GetOneUser)
-		users.PUT("/:id", i.trxMiddleware.DBTransactionHandle(), i.userController.UpdateUser)
-		users.DELETE("/:id", i.trxMiddleware.DBTransactionHandle(), i.userController.DeleteOneUser)
-		users.POST("", i.trxMiddleware.DBTransactionHandle(), i.userController.CreateUser)
+		users.GET("", read, i.userController.GetAllUsers)
+		users.GET("/:id", read, i.userController.GetOneUser)
+		users.PUT("/:id", write, i.trxMiddleware.DBTransactionHandle(), i.userController.UpdateUser)
+		users.PATCH("/:id", write, i.trxMiddleware.DBTransactionHandle(), i.userController.PatchUser)
+		users.DELETE("/:id", write, i.trxMiddleware.DBTransactionHandle(), i.userController.DeleteOneUser)
+		users.POST("", write, i.trxMiddleware.DBTransactionHandle(), i.idempotency.Handle(), i.userController.CreateUser)
+	}
+	i.router.Gin.POST("/user/bulk", i.jwtAuthMiddleware.HandleAdminOnly(), write, i.trxMiddleware.DBTransactionHandle(), i.idempotency.Handle(), i.userController.BulkUser)
+	admin := i.router.Gin.Group("/admin/users").Use(i.jwtAuthMiddleware.HandleAdminOnly())
+	{
+		admin.POST("/import", write, i.trxMiddleware.DBTransactionHandle(), i.userController.ImportUsers)
+		admin.GET("/export", read, i.userController.ExportUsers)
 	}
 	user := i.router.Gin.Group("/jwt-login")
 	{
@@ -43,6 +54,7 @@ func NewUserRoutes(
 	middleware middlewares.FirebaseAuthMiddleware,
 	trxMiddleware middlewares.DBTransactionMiddleware,
 	jwtAuthMiddleware middlewares.JWTAuthMiddleWare,
+	idempotency middlewares.IdempotencyMiddleware,
 ) UserRoutes {
 	return UserRoutes{
 		router:            router,
@@ -51,5 +63,6 @@ func NewUserRoutes(
 		middleware:        middleware,
 		trxMiddleware:     trxMiddleware,
 		jwtAuthMiddleware: jwtAuthMiddleware,
+		idempotency:       idempotency,
 	}
 }
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// BlogMetaDescriptionLength -> length of the meta description generated from the content
+	BlogMetaDescriptionLength = 160
+
+	// BlogOGImageWidth, BlogOGImageHeight -> size of the open graph image
+	BlogOGImageWidth  = 1200
+	BlogOGImageHeight = 630
+
+	// blogSlugAttempts -> numbered suffixes tried before a random one is used
+	blogSlugAttempts = 20
+)
+
+// BlogService -> struct
+type BlogService struct {
+	repository         repository.BlogRepository
+	categoryRepository repository.CategoryRepository
+	bucket             StorageBucketService
+	logger             infrastructure.Logger
+}
+
+// NewBlogService -> creates a new BlogService
+func NewBlogService(
+	repository repository.BlogRepository,
+	categoryRepository repository.CategoryRepository,
+	bucket StorageBucketService,
+	logger infrastructure.Logger,
+) BlogService {
+	return BlogService{
+		repository:         repository,
+		categoryRepository: categoryRepository,
+		bucket:             bucket,
+		logger:             logger,
+	}
+}
+
+// WithTrx -> enables repository with transaction
+func (c BlogService) WithTrx(trxHandle *gorm.DB) BlogService {
+	c.repository = c.repository.WithTrx(trxHandle)
+	c.categoryRepository = c.categoryRepository.WithTrx(trxHandle)
+	return c
+}
+
+// WithFieldset -> limits the fields of blogs returned by the service
+func (c BlogService) WithFieldset(fieldset utils.Fieldset) BlogService {
+	c.repository = c.repository.WithFieldset(fieldset)
+	return c
+}
+
+// CanEdit -> authors and privileged roles can change a blog
+func (c BlogService) CanEdit(blog models.Blog, userID int64, role string) bool {
+	return (blog.CreatedById != nil && *blog.CreatedById == userID) || utils.StringInList(role, constants.RolePrivileged)
+}
+
+// validateCategories -> all the categories have to exist
+func (c BlogService) validateCategories(categoryIDs []int64) error {
+	if len(categoryIDs) == 0 {
+		return nil
+	}
+	unique := map[int64]bool{}
+	for _, ID := range categoryIDs {
+		unique[ID] = true
+	}
+	count, err := c.categoryRepository.CountExisting(categoryIDs)
+	if err != nil {
+		return err
+	}
+	if count != int64(len(unique)) {
+		err := errors.BadRequest.New("unknown category")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return errors.AddErrorContext(err, "category_ids", "One or more categories do not exist.")
+	}
+	return nil
+}
+
+// uniqueSlug -> slug not used by any other blog, numbered on collision ("title", "title-2", ...)
+func (c BlogService) uniqueSlug(text string, blogID int64) (string, error) {
+	base := utils.Slugify(text)
+	if base == "" {
+		base = "blog"
+	}
+	for i := 1; i <= blogSlugAttempts; i++ {
+		slug := base
+		if i > 1 {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		taken, err := c.repository.IsSlugTaken(slug, blogID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return slug, nil
+		}
+	}
+	return base + "-" + utils.GenerateSecureToken(3), nil
+}
+
+// prepareSEO -> fills the meta description from the content when it is not given
+func (c BlogService) prepareSEO(blog *models.Blog) {
+	blog.MetaDescription = strings.TrimSpace(blog.MetaDescription)
+	if blog.MetaDescription == "" {
+		blog.MetaDescription = utils.Excerpt(blog.Content, BlogMetaDescriptionLength)
+	}
+}
+
+// CreateBlog -> creates the blog written by the author
+// published blogs are published right away, drafts with publish_at wait for the scheduler
+func (c BlogService) CreateBlog(blog *models.Blog, authorID int64) error {
+	if err := c.validateCategories(blog.CategoryIds); err != nil {
+		return err
+	}
+	slugSource := blog.Slug
+	if slugSource == "" {
+		slugSource = blog.Title
+	}
+	slug, err := c.uniqueSlug(slugSource, 0)
+	if err != nil {
+		return err
+	}
+	blog.Slug = slug
+	c.prepareSEO(blog)
+
+	blog.CreatedById = &authorID
+	blog.UpdatedById = &authorID
+	blog.PublishedAt = nil
+	blog.OGImage = ""
+	if blog.IsPublished {
+		now := time.Now()
+		blog.PublishedAt = &now
+		blog.PublishAt = nil
+	}
+	return c.repository.Create(blog)
+}
+
+// GetAllBlogs -> Get all blogs matching the filter
+func (c BlogService) GetAllBlogs(pagination utils.Pagination, filter repository.BlogFilter) ([]models.Blog, int64, error) {
+	return c.repository.GetAllBlogs(pagination, filter)
+}
+
+// GetOneBlog -> Get one blog matching the filter
+func (c BlogService) GetOneBlog(ID int64, filter repository.BlogFilter) (models.Blog, error) {
+	blog, err := c.repository.GetOneBlog(ID, filter)
+	if err == gorm.ErrRecordNotFound {
+		return blog, errors.NotFound.Wrap(err, "blog not found")
+	}
+	return blog, err
+}
+
+// getEditable -> blog the user is allowed to change
+func (c BlogService) getEditable(ID int64, userID int64, role string) (models.Blog, error) {
+	blog, err := c.GetOneBlog(ID, repository.BlogFilter{})
+	if err != nil {
+		return blog, err
+	}
+	if !c.CanEdit(blog, userID, role) {
+		err := errors.Forbidden.New("user can not edit the blog")
+		return blog, errors.SetCustomMessage(err, "Only the author can change this blog")
+	}
+	return blog, nil
+}
+
+// UpdateOneBlog -> updates content, seo fields, schedule and categories of the blog
+// drafts follow title changes in their slug, published blogs only change it when asked to
+func (c BlogService) UpdateOneBlog(blog models.Blog, userID int64, role string) error {
+	existing, err := c.getEditable(blog.ID, userID, role)
+	if err != nil {
+		return err
+	}
+	if err := c.validateCategories(blog.CategoryIds); err != nil {
+		return err
+	}
+
+	slug := existing.Slug
+	if blog.Slug != "" && utils.Slugify(blog.Slug) != existing.Slug {
+		if slug, err = c.uniqueSlug(blog.Slug, blog.ID); err != nil {
+			return err
+		}
+	} else if blog.Slug == "" && !existing.IsPublished && blog.Title != existing.Title {
+		if slug, err = c.uniqueSlug(blog.Title, blog.ID); err != nil {
+			return err
+		}
+	}
+	if slug != existing.Slug {
+		if err := c.repository.ChangeSlug(blog.ID, existing.Slug, slug); err != nil {
+			return err
+		}
+	}
+
+	c.prepareSEO(&blog)
+	if existing.IsPublished {
+		blog.PublishAt = existing.PublishAt
+	}
+	blog.UpdatedById = &userID
+	return c.repository.UpdateOneBlog(blog)
+}
+
+// GetPublishedBySlug -> published blog by its slug, when the slug is an old one
+// the current slug is returned to redirect to
+func (c BlogService) GetPublishedBySlug(slug string) (models.Blog, string, error) {
+	filter := repository.BlogFilter{PublishedOnly: true}
+	blog, err := c.repository.GetOneBlogBySlug(slug, filter)
+	if err == nil {
+		return blog, "", nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return blog, "", err
+	}
+	blog, err = c.repository.GetOneBlogByOldSlug(slug, filter)
+	if err == gorm.ErrRecordNotFound {
+		return blog, "", errors.NotFound.Wrap(err, "blog not found")
+	}
+	if err != nil {
+		return blog, "", err
+	}
+	return blog, blog.Slug, nil
+}
+
+// UploadImage -> stores the image as the blog thumbnail along with its open graph version
+func (c BlogService) UploadImage(ctx context.Context, ID int64, file multipart.File, fileType string, userID int64, role string) (models.Blog, error) {
+	blog, err := c.getEditable(ID, userID, role)
+	if err != nil {
+		return blog, err
+	}
+	extension := "png"
+	if fileType == "image/jpeg" || fileType == "image/jpg" {
+		extension = "jpg"
+	}
+	fileName := utils.GenerateRandomFileName() + "." + extension
+	originalName, ogName := "images/blogs/original/"+fileName, "images/blogs/og/"+fileName
+
+	// Both images are ready before anything is uploaded, uploads of a failed request are removed again
+	ogImage, err := utils.CreateThumbnail(file, fileType, BlogOGImageWidth, BlogOGImageHeight)
+	if err != nil {
+		return blog, errors.BadRequest.Wrap(err, "failed to create open graph image")
+	}
+	if _, err := file.Seek(0, 0); err != nil {
+		return blog, errors.BadRequest.Wrap(err, "failed to read image")
+	}
+	original, err := c.bucket.UploadFile(ctx, file, originalName)
+	if err != nil {
+		return blog, err
+	}
+	og, err := c.bucket.UploadThumbnailFile(ctx, ogImage, ogName, extension)
+	if err != nil {
+		c.removeUploads(ctx, originalName)
+		return blog, err
+	}
+	if err := c.repository.SetImages(ID, original, og, userID); err != nil {
+		c.removeUploads(ctx, originalName, ogName)
+		return blog, err
+	}
+	blog.ThumbnailImage, blog.OGImage = original, og
+	return blog, nil
+}
+
+// removeUploads -> deletes objects uploaded by a request that failed afterwards
+func (c BlogService) removeUploads(ctx context.Context, fileNames ...string) {
+	for _, fileName := range fileNames {
+		if err := c.bucket.RemoveObject(ctx, fileName); err != nil {
+			c.logger.Zap.Error("Error [UploadImage] removing uploaded image ", fileName, ": ", err.Error())
+		}
+	}
+}
+
+// SetPublished -> publishes the blog or moves it back to draft
+func (c BlogService) SetPublished(ID int64, published bool, userID int64, role string) error {
+	if _, err := c.getEditable(ID, userID, role); err != nil {
+		return err
+	}
+	return c.repository.SetPublished(ID, published, userID)
+}
+
+// DeleteOneBlog -> deletes the blog
+func (c BlogService) DeleteOneBlog(ID int64, userID int64, role string) error {
+	if _, err := c.getEditable(ID, userID, role); err != nil {
+		return err
+	}
+	return c.repository.DeleteOneBlog(ID)
+}
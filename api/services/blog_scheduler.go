@@ -0,0 +1,61 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/infrastructure"
+	"time"
+)
+
+// BlogPublishInterval -> how often drafts scheduled with publish_at are checked
+const BlogPublishInterval = time.Minute
+
+// BlogScheduler -> publishes scheduled blogs in the background
+type BlogScheduler struct {
+	logger     infrastructure.Logger
+	repository repository.BlogRepository
+	stop       chan struct{}
+}
+
+// NewBlogScheduler -> creates a new BlogScheduler
+func NewBlogScheduler(
+	logger infrastructure.Logger,
+	repository repository.BlogRepository,
+) BlogScheduler {
+	return BlogScheduler{
+		logger:     logger,
+		repository: repository,
+		stop:       make(chan struct{}),
+	}
+}
+
+// Start -> publishes due blogs every interval until Stop is called
+func (s BlogScheduler) Start() {
+	ticker := time.NewTicker(BlogPublishInterval)
+	defer ticker.Stop()
+
+	s.publishDue()
+	for {
+		select {
+		case <-ticker.C:
+			s.publishDue()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Stop -> stops the scheduler
+func (s BlogScheduler) Stop() {
+	close(s.stop)
+}
+
+func (s BlogScheduler) publishDue() {
+	published, err := s.repository.PublishDue(time.Now())
+	if err != nil {
+		s.logger.Zap.Error("Error [BlogScheduler] [db PublishDue]: ", err.Error())
+		return
+	}
+	if published > 0 {
+		s.logger.Zap.Infof("📰 published %d scheduled blogs", published)
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"testing"
+	"time"
+)
+
+func newTestBlogService(db infrastructure.Database) BlogService {
+	return NewBlogService(
+		repository.NewBlogRepository(db, testLogger),
+		repository.NewCategoryRepository(db, testLogger),
+		StorageBucketService{},
+		testLogger,
+	)
+}
+
+func TestBlogServicePermissions(t *testing.T) {
+	db := newTestDatabase(t)
+	author := createTestUser(t, db, "author")
+	other := createTestUser(t, db, "other")
+	service := newTestBlogService(db)
+
+	tests := []struct {
+		name    string
+		userID  int64
+		role    string
+		wantErr bool
+	}{
+		{name: "author", userID: author.ID, role: constants.RoleUser},
+		{name: "other user", userID: other.ID, role: constants.RoleUser, wantErr: true},
+		{name: "other client", userID: other.ID, role: constants.RoleClient, wantErr: true},
+		{name: "client admin", userID: other.ID, role: constants.RoleClientAdmin},
+		{name: "admin", userID: other.ID, role: constants.RoleAdmin},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			blog := models.Blog{Title: "title", Content: "content"}
+			if err := service.CreateBlog(&blog, author.ID); err != nil {
+				t.Fatal(err)
+			}
+
+			update := models.Blog{Title: "changed", Content: "changed"}
+			update.ID = blog.ID
+			actions := map[string]error{
+				"update":  service.UpdateOneBlog(update, test.userID, test.role),
+				"publish": service.SetPublished(blog.ID, true, test.userID, test.role),
+				"delete":  service.DeleteOneBlog(blog.ID, test.userID, test.role),
+			}
+			for action, err := range actions {
+				if test.wantErr && errors.GetErrorType(err) != errors.Forbidden {
+					t.Errorf("%s error = %v, want Forbidden", action, err)
+				}
+				if !test.wantErr && err != nil {
+					t.Errorf("%s error = %v", action, err)
+				}
+			}
+		})
+	}
+}
+
+func TestBlogServiceCategories(t *testing.T) {
+	db := newTestDatabase(t)
+	author := createTestUser(t, db, "author")
+	service := newTestBlogService(db)
+	category, err := repository.NewCategoryRepository(db, testLogger).Create(models.Category{Title: "news"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		categoryIDs []int64
+		wantErr     bool
+	}{
+		{name: "no categories"},
+		{name: "existing category", categoryIDs: []int64{category.ID}},
+		{name: "duplicated category", categoryIDs: []int64{category.ID, category.ID}},
+		{name: "unknown category", categoryIDs: []int64{category.ID, category.ID + 1}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			blog := models.Blog{Title: test.name, Content: "content", CategoryIds: test.categoryIDs}
+			err := service.CreateBlog(&blog, author.ID)
+			if test.wantErr {
+				if errors.GetErrorType(err) != errors.BadRequest {
+					t.Fatalf("error = %v, want BadRequest", err)
+				}
+				if contexts := errors.GetErrorContext(err); len(contexts) != 1 || contexts[0].Field != "category_ids" {
+					t.Errorf("error context = %+v, want category_ids", contexts)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestBlogServiceSlugs(t *testing.T) {
+	db := newTestDatabase(t)
+	author := createTestUser(t, db, "author")
+	service := newTestBlogService(db)
+
+	create := func(blog models.Blog) models.Blog {
+		t.Helper()
+		if err := service.CreateBlog(&blog, author.ID); err != nil {
+			t.Fatal(err)
+		}
+		return blog
+	}
+	update := func(blog models.Blog) models.Blog {
+		t.Helper()
+		if err := service.UpdateOneBlog(blog, author.ID, constants.RoleUser); err != nil {
+			t.Fatal(err)
+		}
+		updated, err := service.GetOneBlog(blog.ID, repository.BlogFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return updated
+	}
+
+	first := create(models.Blog{Title: "Hello World", Content: "content", IsPublished: true})
+	second := create(models.Blog{Title: "Hello, world!", Content: "content"})
+	if first.Slug != "hello-world" || second.Slug != "hello-world-2" {
+		t.Errorf("slugs = %q, %q, want hello-world, hello-world-2", first.Slug, second.Slug)
+	}
+	if first.MetaDescription != "content" {
+		t.Errorf("meta description = %q, want it taken from the content", first.MetaDescription)
+	}
+
+	draft := update(models.Blog{Base: second.Base, Title: "Draft title", Content: "content"})
+	if draft.Slug != "draft-title" {
+		t.Errorf("draft slug = %q, want it to follow the title", draft.Slug)
+	}
+
+	published := update(models.Blog{Base: first.Base, Title: "New title", Content: "content"})
+	if published.Slug != "hello-world" {
+		t.Errorf("published slug = %q, want it kept on title changes", published.Slug)
+	}
+	published = update(models.Blog{Base: first.Base, Title: "New title", Slug: "New Slug", Content: "content"})
+	if published.Slug != "new-slug" {
+		t.Errorf("published slug = %q, want new-slug", published.Slug)
+	}
+
+	blog, redirect, err := service.GetPublishedBySlug("hello-world")
+	if err != nil || blog.ID != first.ID || redirect != "new-slug" {
+		t.Errorf("old slug = blog %d, redirect %q, %v, want blog %d redirecting to new-slug", blog.ID, redirect, err, first.ID)
+	}
+	if _, _, err := service.GetPublishedBySlug("draft-title"); errors.GetErrorType(err) != errors.NotFound {
+		t.Errorf("draft by slug error = %v, want NotFound", err)
+	}
+
+	reused := create(models.Blog{Title: "Hello World", Content: "content"})
+	if reused.Slug != "hello-world-3" {
+		t.Errorf("slug = %q, want the old slugs to stay reserved", reused.Slug)
+	}
+}
+
+func TestBlogSchedulerPublishDue(t *testing.T) {
+	db := newTestDatabase(t)
+	author := createTestUser(t, db, "author")
+	service := newTestBlogService(db)
+	blogRepository := repository.NewBlogRepository(db, testLogger)
+
+	past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+	due := models.Blog{Title: "due", Content: "content", PublishAt: &past}
+	scheduled := models.Blog{Title: "scheduled", Content: "content", PublishAt: &future}
+	draft := models.Blog{Title: "draft", Content: "content"}
+	for _, blog := range []*models.Blog{&due, &scheduled, &draft} {
+		if err := service.CreateBlog(blog, author.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	NewBlogScheduler(testLogger, blogRepository).publishDue()
+
+	for _, test := range []struct {
+		blog          models.Blog
+		wantPublished bool
+	}{
+		{blog: due, wantPublished: true},
+		{blog: scheduled},
+		{blog: draft},
+	} {
+		blog, err := service.GetOneBlog(test.blog.ID, repository.BlogFilter{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blog.IsPublished != test.wantPublished || (blog.PublishedAt != nil) != test.wantPublished {
+			t.Errorf("blog %s published = %v at %v, want %v", blog.Title, blog.IsPublished, blog.PublishedAt, test.wantPublished)
+		}
+	}
+}
@@ -167,8 +167,8 @@ func (s StorageBucketService) GetObjectSignedURL(
 }
 
 // RemoveObject -> removes the file from the storage bucket
-func (s StorageBucketService) RemoveObject() {
-
+func (s StorageBucketService) RemoveObject(ctx context.Context, fileName string) error {
+	return s.client.Bucket(s.env.StorageBucketName).Object(fileName).Delete(ctx)
 }
 
 func(s StorageBucketService) UploadThumbnailFile(ctx context.Context,
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/utils"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// Operations of a bulk request
+	BulkOpCreate = "create"
+	BulkOpUpdate = "update"
+	BulkOpDelete = "delete"
+
+	// BulkMaxOperations -> most operations a single bulk request may carry
+	BulkMaxOperations = 500
+)
+
+// BulkOps -> accepted operations of a bulk request
+var BulkOps = []string{BulkOpCreate, BulkOpUpdate, BulkOpDelete}
+
+// BulkOperation -> single create, update or delete of a bulk request, id is required for update and delete
+// version works like If-Match, the operation fails when the record is no longer at the version (0 for any)
+type BulkOperation struct {
+	Op      string          `json:"op"`
+	ID      int64           `json:"id,omitempty"`
+	Version int64           `json:"version,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// BulkRequest -> operations run in a single transaction
+// atomic (the default) rolls everything back on the first failure, otherwise failed operations are skipped
+type BulkRequest struct {
+	Atomic     *bool           `json:"atomic"`
+	Operations []BulkOperation `json:"operations"`
+}
+
+// IsAtomic -> whether a failure rolls back the whole request
+func (r BulkRequest) IsAtomic() bool {
+	return r.Atomic == nil || *r.Atomic
+}
+
+// BulkItemResult -> outcome of an operation, status follows the status code the single endpoint would respond with
+// operations not run because an earlier one failed in atomic mode have status 424 (failed dependency)
+type BulkItemResult struct {
+	Index  int         `json:"index"`
+	Op     string      `json:"op"`
+	ID     int64       `json:"id,omitempty"`
+	Status int         `json:"status"`
+	Data   interface{} `json:"data,omitempty"`
+	Error  interface{} `json:"error,omitempty"`
+
+	Err error `json:"-"`
+}
+
+// BulkResult -> per operation outcomes of a bulk request
+type BulkResult struct {
+	Atomic    bool             `json:"atomic"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkItemResult `json:"results"`
+}
+
+// FirstError -> first failed operation, nil when all of them succeeded
+func (r BulkResult) FirstError() *BulkItemResult {
+	for i := range r.Results {
+		if r.Results[i].Err != nil {
+			return &r.Results[i]
+		}
+	}
+	return nil
+}
+
+// BulkApplyFunc -> runs a single operation inside the transaction, returning what the item responds with
+type BulkApplyFunc func(trx *gorm.DB, operation BulkOperation) (interface{}, error)
+
+// BulkService -> runs batches of operations in a single transaction
+type BulkService struct {
+	logger infrastructure.Logger
+}
+
+// NewBulkService -> creates a new BulkService
+func NewBulkService(logger infrastructure.Logger) BulkService {
+	return BulkService{
+		logger: logger,
+	}
+}
+
+// Validate -> checks the size of the batch and the shape of every operation before anything runs
+func (s BulkService) Validate(request BulkRequest) error {
+	if len(request.Operations) == 0 || len(request.Operations) > BulkMaxOperations {
+		err := errors.BadRequest.Newf("bulk request with %d operations", len(request.Operations))
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return errors.AddErrorContext(err, "operations", fmt.Sprintf("Operations has to contain between 1 and %d items.", BulkMaxOperations))
+	}
+	var validations []errors.ErrorContext
+	for i, operation := range request.Operations {
+		field := fmt.Sprintf("operations[%d]", i)
+		switch {
+		case !utils.StringInList(operation.Op, BulkOps):
+			validations = append(validations, errors.ErrorContext{Field: field + ".op", Message: "Op has to be one of " + strings.Join(BulkOps, ", ") + "."})
+		case operation.Op != BulkOpCreate && operation.ID == 0:
+			validations = append(validations, errors.ErrorContext{Field: field + ".id", Message: "Field 'id' is 'required'."})
+		case operation.Op != BulkOpDelete && len(operation.Data) == 0:
+			validations = append(validations, errors.ErrorContext{Field: field + ".data", Message: "Field 'data' is 'required'."})
+		}
+	}
+	if len(validations) == 0 {
+		return nil
+	}
+	err := errors.BadRequest.New("invalid bulk operations")
+	err = errors.SetCustomMessage(err, "Invalid input information")
+	return errors.AddErrorContextBlock(err, validations)
+}
+
+// Run -> applies the operations in order within the transaction
+// in partial mode every operation runs in its own savepoint so a failure only undoes that operation
+func (s BulkService) Run(trx *gorm.DB, request BulkRequest, apply BulkApplyFunc) BulkResult {
+	result := BulkResult{
+		Atomic:  request.IsAtomic(),
+		Results: make([]BulkItemResult, len(request.Operations)),
+	}
+	for i, operation := range request.Operations {
+		item := &result.Results[i]
+		item.Index, item.Op, item.ID = i, operation.Op, operation.ID
+		if result.Atomic && result.Failed > 0 {
+			item.Status = http.StatusFailedDependency
+			continue
+		}
+
+		savepoint := fmt.Sprintf("bulk_%d", i)
+		if !result.Atomic {
+			if err := trx.SavePoint(savepoint).Error; err != nil {
+				s.fail(item, &result, errors.InternalError.Wrap(err, "failed to create savepoint"))
+				continue
+			}
+		}
+		data, err := s.apply(trx, operation, apply)
+		if err != nil {
+			s.logger.Zap.Error("Error [Bulk] operation ", i, ": ", err.Error())
+			if !result.Atomic {
+				if rollbackErr := trx.RollbackTo(savepoint).Error; rollbackErr != nil {
+					s.logger.Zap.Error("Error [Bulk] rolling back to savepoint: ", rollbackErr.Error())
+				}
+			}
+			s.fail(item, &result, err)
+			continue
+		}
+		item.Status = http.StatusOK
+		item.Data = data
+		result.Succeeded++
+	}
+	return result
+}
+
+// apply -> runs the operation, turning panics into failures of the operation
+func (s BulkService) apply(trx *gorm.DB, operation BulkOperation, apply BulkApplyFunc) (data interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.InternalError.Newf("bulk operation panicked: %v", r)
+		}
+	}()
+	return apply(trx, operation)
+}
+
+func (s BulkService) fail(item *BulkItemResult, result *BulkResult, err error) {
+	item.Err = err
+	item.Status = errors.GetStatusCode(errors.GetErrorType(err))
+	result.Failed++
+}
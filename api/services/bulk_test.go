@@ -0,0 +1,161 @@
+package services
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/models"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestBulkServiceValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []BulkOperation
+		wantFields []string
+	}{
+		{
+			name: "valid operations",
+			operations: []BulkOperation{
+				{Op: BulkOpCreate, Data: json.RawMessage(`{}`)},
+				{Op: BulkOpUpdate, ID: 1, Data: json.RawMessage(`{}`)},
+				{Op: BulkOpDelete, ID: 1},
+			},
+		},
+		{name: "no operations", wantFields: []string{"operations"}},
+		{name: "too many operations", operations: make([]BulkOperation, BulkMaxOperations+1), wantFields: []string{"operations"}},
+		{
+			name: "invalid operations",
+			operations: []BulkOperation{
+				{Op: "upsert", ID: 1},
+				{Op: BulkOpUpdate, Data: json.RawMessage(`{}`)},
+				{Op: BulkOpCreate},
+				{Op: BulkOpDelete, ID: 1},
+			},
+			wantFields: []string{"operations[0].op", "operations[1].id", "operations[2].data"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := NewBulkService(testLogger).Validate(BulkRequest{Operations: test.operations})
+			if test.wantFields == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if errors.GetErrorType(err) != errors.BadRequest {
+				t.Fatalf("error = %v, want BadRequest", err)
+			}
+			var fields []string
+			for _, context := range errors.GetErrorContext(err) {
+				fields = append(fields, context.Field)
+			}
+			if !reflect.DeepEqual(fields, test.wantFields) {
+				t.Errorf("error fields = %v, want %v", fields, test.wantFields)
+			}
+		})
+	}
+}
+
+func TestBulkServiceRun(t *testing.T) {
+	// every operation stores a category before failing or panicking, so undone operations leave no row behind
+	apply := func(trx *gorm.DB, operation BulkOperation) (interface{}, error) {
+		var data struct {
+			Title string `json:"title"`
+			Fail  bool   `json:"fail"`
+			Panic bool   `json:"panic"`
+		}
+		if err := json.Unmarshal(operation.Data, &data); err != nil {
+			return nil, err
+		}
+		category := models.Category{Title: data.Title}
+		if err := trx.Create(&category).Error; err != nil {
+			return nil, err
+		}
+		if data.Panic {
+			panic("boom")
+		}
+		if data.Fail {
+			return nil, errors.BadRequest.New("invalid category")
+		}
+		return category, nil
+	}
+	operations := []BulkOperation{
+		{Op: BulkOpCreate, Data: json.RawMessage(`{"title":"first"}`)},
+		{Op: BulkOpCreate, Data: json.RawMessage(`{"title":"failing","fail":true}`)},
+		{Op: BulkOpCreate, Data: json.RawMessage(`{"title":"panicking","panic":true}`)},
+		{Op: BulkOpCreate, Data: json.RawMessage(`{"title":"last"}`)},
+	}
+	atomic, partial := true, false
+
+	tests := []struct {
+		name          string
+		atomic        *bool
+		wantStatuses  []int
+		wantSucceeded int
+		wantFailed    int
+		wantTitles    []string
+	}{
+		{
+			name:          "atomic by default",
+			wantStatuses:  []int{http.StatusOK, http.StatusBadRequest, http.StatusFailedDependency, http.StatusFailedDependency},
+			wantSucceeded: 1,
+			wantFailed:    1,
+			wantTitles:    []string{"first", "failing"},
+		},
+		{
+			name:          "atomic",
+			atomic:        &atomic,
+			wantStatuses:  []int{http.StatusOK, http.StatusBadRequest, http.StatusFailedDependency, http.StatusFailedDependency},
+			wantSucceeded: 1,
+			wantFailed:    1,
+			wantTitles:    []string{"first", "failing"},
+		},
+		{
+			name:          "partial",
+			atomic:        &partial,
+			wantStatuses:  []int{http.StatusOK, http.StatusBadRequest, http.StatusInternalServerError, http.StatusOK},
+			wantSucceeded: 2,
+			wantFailed:    2,
+			wantTitles:    []string{"first", "last"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			trx := db.DB.Begin()
+			defer trx.Rollback()
+
+			result := NewBulkService(testLogger).Run(trx, BulkRequest{Atomic: test.atomic, Operations: operations}, apply)
+
+			var statuses []int
+			for _, item := range result.Results {
+				statuses = append(statuses, item.Status)
+			}
+			if !reflect.DeepEqual(statuses, test.wantStatuses) {
+				t.Errorf("statuses = %v, want %v", statuses, test.wantStatuses)
+			}
+			if result.Succeeded != test.wantSucceeded || result.Failed != test.wantFailed {
+				t.Errorf("succeeded %d failed %d, want %d and %d", result.Succeeded, result.Failed, test.wantSucceeded, test.wantFailed)
+			}
+			if first := result.FirstError(); first == nil || first.Index != 1 {
+				t.Errorf("first error = %+v, want operation 1", first)
+			}
+
+			// an atomic failure rolls back the whole transaction in the controller, the stored rows show what ran
+			var titles []string
+			if err := trx.Model(&models.Category{}).Order("id").Pluck("title", &titles).Error; err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(titles, test.wantTitles) {
+				t.Errorf("stored categories = %v, want %v", titles, test.wantTitles)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/errors"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// CategoryService -> struct
+type CategoryService struct {
+	repository repository.CategoryRepository
+}
+
+// NewCategoryService -> creates a new CategoryService
+func NewCategoryService(repository repository.CategoryRepository) CategoryService {
+	return CategoryService{
+		repository: repository,
+	}
+}
+
+// WithTrx -> enables repository with transaction
+func (c CategoryService) WithTrx(trxHandle *gorm.DB) CategoryService {
+	c.repository = c.repository.WithTrx(trxHandle)
+	return c
+}
+
+// CreateCategory -> call to create the Category
+func (c CategoryService) CreateCategory(category models.Category) (models.Category, error) {
+	return c.repository.Create(category)
+}
+
+// GetAllCategory -> call to get all the Category
+func (c CategoryService) GetAllCategory(pagination utils.Pagination) ([]models.Category, int64, error) {
+	return c.repository.GetAllCategory(pagination)
+}
+
+// GetOneCategory -> Get One Category By Id
+func (c CategoryService) GetOneCategory(ID int64) (models.Category, error) {
+	category, err := c.repository.GetOneCategory(ID)
+	if err == gorm.ErrRecordNotFound {
+		return category, errors.NotFound.Wrap(err, "category not found")
+	}
+	return category, err
+}
+
+// UpdateOneCategory -> Update One Category By Id
+func (c CategoryService) UpdateOneCategory(category models.Category) error {
+	if _, err := c.GetOneCategory(category.ID); err != nil {
+		return err
+	}
+	return c.repository.UpdateOneCategory(category)
+}
+
+// DeleteOneCategory -> Delete One Category By Id
+func (c CategoryService) DeleteOneCategory(ID int64) error {
+	if _, err := c.GetOneCategory(ID); err != nil {
+		return err
+	}
+	return c.repository.DeleteOneCategory(ID)
+}
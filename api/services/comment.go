@@ -0,0 +1,258 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// CommentEditWindow -> how long authors can edit or delete their comment
+	CommentEditWindow = 15 * time.Minute
+
+	// CommentMaxDepth -> deepest reply level, top level comments are at depth 0
+	CommentMaxDepth = 5
+)
+
+// commentRateLimit -> at most Limit comments per user within Window
+type commentRateLimit struct {
+	Window time.Duration
+	Limit  int64
+}
+
+// commentRateLimits -> limits applied to users outside of the privileged roles
+var commentRateLimits = []commentRateLimit{
+	{Window: time.Minute, Limit: 5},
+	{Window: time.Hour, Limit: 30},
+}
+
+// CommentService -> struct
+type CommentService struct {
+	repository     repository.CommentRepository
+	blogRepository repository.BlogRepository
+}
+
+// NewCommentService -> creates a new CommentService
+func NewCommentService(
+	repository repository.CommentRepository,
+	blogRepository repository.BlogRepository,
+) CommentService {
+	return CommentService{
+		repository:     repository,
+		blogRepository: blogRepository,
+	}
+}
+
+// WithTrx -> enables repository with transaction
+func (c CommentService) WithTrx(trxHandle *gorm.DB) CommentService {
+	c.repository = c.repository.WithTrx(trxHandle)
+	c.blogRepository = c.blogRepository.WithTrx(trxHandle)
+	return c
+}
+
+// getPublishedBlog -> comments are only read and written on published blogs
+func (c CommentService) getPublishedBlog(blogID int64) error {
+	_, err := c.blogRepository.GetOneBlog(blogID, repository.BlogFilter{PublishedOnly: true})
+	if err == gorm.ErrRecordNotFound {
+		return errors.NotFound.Wrap(err, "blog not found")
+	}
+	return err
+}
+
+// GetOneComment -> Get One Comment By Id
+func (c CommentService) GetOneComment(ID int64) (models.Comment, error) {
+	comment, err := c.repository.GetOneComment(ID)
+	if err == gorm.ErrRecordNotFound {
+		return comment, errors.NotFound.Wrap(err, "comment not found")
+	}
+	return comment, err
+}
+
+// checkRateLimit -> rejects the comment when the user wrote too many recently
+// runs in the transaction creating the comment, the user row stays locked until the comment is stored
+func (c CommentService) checkRateLimit(userID int64) error {
+	if err := c.repository.LockAuthor(userID); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, limit := range commentRateLimits {
+		count, err := c.repository.CountSince(userID, now.Add(-limit.Window))
+		if err != nil {
+			return err
+		}
+		if count >= limit.Limit {
+			err := errors.TooManyRequests.Newf("comment rate limit of %d per %s reached", limit.Limit, limit.Window)
+			return errors.SetCustomMessage(err, fmt.Sprintf("You can post at most %d comments per %s.", limit.Limit, limit.Window))
+		}
+	}
+	return nil
+}
+
+// CreateComment -> creates the comment or reply of the user
+// comments of privileged roles are approved right away, the rest waits for moderation
+func (c CommentService) CreateComment(comment *models.Comment, userID int64, role string) error {
+	privileged := utils.StringInList(role, constants.RolePrivileged)
+	if err := c.getPublishedBlog(comment.BlogId); err != nil {
+		return err
+	}
+	if !privileged {
+		if err := c.checkRateLimit(userID); err != nil {
+			return err
+		}
+	}
+
+	comment.RootId = nil
+	comment.Depth = 0
+	if comment.ParentId != nil {
+		parent, err := c.repository.GetOneComment(*comment.ParentId)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err == gorm.ErrRecordNotFound || parent.BlogId != comment.BlogId || parent.Status != models.CommentStatusApproved {
+			err := errors.BadRequest.New("invalid parent comment")
+			err = errors.SetCustomMessage(err, "Invalid input information")
+			return errors.AddErrorContext(err, "parent_id", "Replies can only be made to approved comments of the same blog.")
+		}
+		if parent.Depth+1 > CommentMaxDepth {
+			err := errors.BadRequest.New("comment thread too deep")
+			err = errors.SetCustomMessage(err, "Invalid input information")
+			return errors.AddErrorContext(err, "parent_id", fmt.Sprintf("Replies can be nested at most %d levels deep.", CommentMaxDepth))
+		}
+		rootID := parent.ThreadID()
+		comment.RootId = &rootID
+		comment.Depth = parent.Depth + 1
+	}
+
+	comment.UserId = &userID
+	comment.Status = models.CommentStatusPending
+	comment.ModeratedById = nil
+	comment.ModeratedAt = nil
+	if privileged {
+		now := time.Now()
+		comment.Status = models.CommentStatusApproved
+		comment.ModeratedById = &userID
+		comment.ModeratedAt = &now
+	}
+	return c.repository.Create(comment)
+}
+
+// GetCommentTree -> paginated approved threads of a published blog with their replies nested
+func (c CommentService) GetCommentTree(blogID int64, pagination utils.Pagination) ([]*models.Comment, int64, error) {
+	if err := c.getPublishedBlog(blogID); err != nil {
+		return nil, 0, err
+	}
+	roots, count, err := c.repository.GetThreads(blogID, models.CommentStatusApproved, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	rootIDs := make([]int64, 0, len(roots))
+	for _, root := range roots {
+		rootIDs = append(rootIDs, root.ID)
+	}
+	replies, err := c.repository.GetReplies(rootIDs, models.CommentStatusApproved)
+	if err != nil {
+		return nil, 0, err
+	}
+	return buildCommentTree(roots, replies), count, nil
+}
+
+// buildCommentTree -> nests the replies under their parents
+// replies whose parent is not in the tree (e.g. rejected later on) are left out
+func buildCommentTree(roots []models.Comment, replies []models.Comment) []*models.Comment {
+	nodes := make(map[int64]*models.Comment, len(roots)+len(replies))
+	tree := make([]*models.Comment, 0, len(roots))
+	for i := range roots {
+		nodes[roots[i].ID] = &roots[i]
+		tree = append(tree, &roots[i])
+	}
+	// replies are ordered by creation, so parents are always seen before their children
+	for i := range replies {
+		parent, ok := nodes[*replies[i].ParentId]
+		if !ok {
+			continue
+		}
+		nodes[replies[i].ID] = &replies[i]
+		parent.Replies = append(parent.Replies, &replies[i])
+	}
+	return tree
+}
+
+// getEditable -> comment the user may still change
+// authors can change their comment within the edit window, privileged roles at any time
+func (c CommentService) getEditable(ID int64, userID int64, role string) (models.Comment, error) {
+	comment, err := c.GetOneComment(ID)
+	if err != nil {
+		return comment, err
+	}
+	if utils.StringInList(role, constants.RolePrivileged) {
+		return comment, nil
+	}
+	if comment.UserId == nil || *comment.UserId != userID {
+		err := errors.Forbidden.New("not the author of the comment")
+		return comment, errors.SetCustomMessage(err, "You can only change your own comments")
+	}
+	if time.Since(comment.CreatedAt) > CommentEditWindow {
+		err := errors.Forbidden.New("comment edit window has passed")
+		return comment, errors.SetCustomMessage(err, fmt.Sprintf("Comments can only be changed within %s of posting", CommentEditWindow))
+	}
+	return comment, nil
+}
+
+// UpdateComment -> changes the content, edits by authors go through moderation again
+func (c CommentService) UpdateComment(ID int64, content string, userID int64, role string) error {
+	comment, err := c.getEditable(ID, userID, role)
+	if err != nil {
+		return err
+	}
+	status := models.CommentStatusPending
+	if utils.StringInList(role, constants.RolePrivileged) {
+		status = comment.Status
+	}
+	return c.repository.UpdateContent(ID, content, status)
+}
+
+// DeleteComment -> deletes the comment along with the replies below it
+func (c CommentService) DeleteComment(ID int64, userID int64, role string) error {
+	comment, err := c.getEditable(ID, userID, role)
+	if err != nil {
+		return err
+	}
+	thread, err := c.repository.GetThread(comment.ThreadID())
+	if err != nil {
+		return err
+	}
+	children := map[int64][]int64{}
+	for _, reply := range thread {
+		if reply.ParentId != nil {
+			children[*reply.ParentId] = append(children[*reply.ParentId], reply.ID)
+		}
+	}
+	IDs := []int64{comment.ID}
+	for i := 0; i < len(IDs); i++ {
+		IDs = append(IDs, children[IDs[i]]...)
+	}
+	return c.repository.DeleteComments(IDs)
+}
+
+// GetModerationQueue -> comments waiting for moderation
+func (c CommentService) GetModerationQueue(pagination utils.Pagination) ([]models.Comment, int64, error) {
+	return c.repository.GetModerationQueue(pagination)
+}
+
+// Moderate -> approves or rejects the comment
+func (c CommentService) Moderate(ID int64, approved bool, moderatorID int64) error {
+	if _, err := c.GetOneComment(ID); err != nil {
+		return err
+	}
+	status := models.CommentStatusRejected
+	if approved {
+		status = models.CommentStatusApproved
+	}
+	return c.repository.SetStatus(ID, status, moderatorID)
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"testing"
+	"time"
+)
+
+func newTestCommentService(db infrastructure.Database) CommentService {
+	return NewCommentService(
+		repository.NewCommentRepository(db, testLogger),
+		repository.NewBlogRepository(db, testLogger),
+	)
+}
+
+// createTestBlog -> blog of the author, published or draft
+func createTestBlog(t *testing.T, db infrastructure.Database, author models.User, title string, published bool) models.Blog {
+	t.Helper()
+	blog := models.Blog{Title: title, Content: "content", IsPublished: published}
+	if err := newTestBlogService(db).CreateBlog(&blog, author.ID); err != nil {
+		t.Fatal(err)
+	}
+	return blog
+}
+
+func TestCommentServiceCreateComment(t *testing.T) {
+	db := newTestDatabase(t)
+	author := createTestUser(t, db, "author")
+	reader := createTestUser(t, db, "reader")
+	published := createTestBlog(t, db, author, "published", true)
+	other := createTestBlog(t, db, author, "other", true)
+	draft := createTestBlog(t, db, author, "draft", false)
+	service := newTestCommentService(db)
+
+	create := func(comment models.Comment, user models.User, role string) (models.Comment, error) {
+		err := service.CreateComment(&comment, user.ID, role)
+		return comment, err
+	}
+	approved, err := create(models.Comment{BlogId: published.ID, Content: "approved"}, author, constants.RoleClientAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if approved.Status != models.CommentStatusApproved || approved.ModeratedById == nil {
+		t.Errorf("comment of a privileged role = %s, want approved right away", approved.Status)
+	}
+	pending, err := create(models.Comment{BlogId: published.ID, Content: "pending"}, reader, constants.RoleUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Status != models.CommentStatusPending || pending.UserId == nil || *pending.UserId != reader.ID {
+		t.Errorf("comment of a user = %s by %v, want pending by %d", pending.Status, pending.UserId, reader.ID)
+	}
+
+	// thread as deep as allowed below the approved comment
+	parent := approved
+	for depth := 1; depth <= CommentMaxDepth; depth++ {
+		reply, err := create(models.Comment{BlogId: published.ID, ParentId: &parent.ID, Content: "reply"}, author, constants.RoleAdmin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reply.Depth != depth || reply.RootId == nil || *reply.RootId != approved.ID {
+			t.Fatalf("reply depth %d root %v, want depth %d root %d", reply.Depth, reply.RootId, depth, approved.ID)
+		}
+		parent = reply
+	}
+
+	tests := []struct {
+		name        string
+		comment     models.Comment
+		wantErrType errors.HttpErrorType
+		wantField   string
+	}{
+		{name: "draft blog", comment: models.Comment{BlogId: draft.ID, Content: "text"}, wantErrType: errors.NotFound},
+		{name: "reply to a pending comment", comment: models.Comment{BlogId: published.ID, ParentId: &pending.ID, Content: "text"}, wantErrType: errors.BadRequest, wantField: "parent_id"},
+		{name: "reply to a comment of another blog", comment: models.Comment{BlogId: other.ID, ParentId: &approved.ID, Content: "text"}, wantErrType: errors.BadRequest, wantField: "parent_id"},
+		{name: "reply too deep", comment: models.Comment{BlogId: published.ID, ParentId: &parent.ID, Content: "text"}, wantErrType: errors.BadRequest, wantField: "parent_id"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := create(test.comment, author, constants.RoleAdmin)
+			if errors.GetErrorType(err) != test.wantErrType {
+				t.Fatalf("error = %v, want type %v", err, test.wantErrType)
+			}
+			if test.wantField == "" {
+				return
+			}
+			if contexts := errors.GetErrorContext(err); len(contexts) != 1 || contexts[0].Field != test.wantField {
+				t.Errorf("error context = %+v, want %s", contexts, test.wantField)
+			}
+		})
+	}
+}
+
+func TestCommentServiceRateLimit(t *testing.T) {
+	db := newTestDatabase(t)
+	author := createTestUser(t, db, "author")
+	reader := createTestUser(t, db, "reader")
+	blog := createTestBlog(t, db, author, "published", true)
+	service := newTestCommentService(db)
+
+	limit := commentRateLimits[0].Limit
+	for i := int64(0); i < limit; i++ {
+		if err := service.CreateComment(&models.Comment{BlogId: blog.ID, Content: "text"}, reader.ID, constants.RoleUser); err != nil {
+			t.Fatalf("comment %d: %v", i+1, err)
+		}
+	}
+	err := service.CreateComment(&models.Comment{BlogId: blog.ID, Content: "text"}, reader.ID, constants.RoleUser)
+	if errors.GetErrorType(err) != errors.TooManyRequests {
+		t.Errorf("comment over the limit error = %v, want TooManyRequests", err)
+	}
+	for i := int64(0); i <= limit; i++ {
+		if err := service.CreateComment(&models.Comment{BlogId: blog.ID, Content: "text"}, author.ID, constants.RoleClientAdmin); err != nil {
+			t.Fatalf("privileged comment %d: %v", i+1, err)
+		}
+	}
+}
+
+func TestCommentServicePermissions(t *testing.T) {
+	tests := []struct {
+		name       string
+		byAuthor   bool
+		role       string
+		age        time.Duration
+		wantErr    bool
+		wantStatus string
+	}{
+		{name: "author within the edit window", byAuthor: true, role: constants.RoleUser, wantStatus: models.CommentStatusPending},
+		{name: "author after the edit window", byAuthor: true, role: constants.RoleUser, age: CommentEditWindow + time.Minute, wantErr: true},
+		{name: "other user", role: constants.RoleUser, wantErr: true},
+		{name: "other client", role: constants.RoleClient, wantErr: true},
+		{name: "client admin after the edit window", role: constants.RoleClientAdmin, age: CommentEditWindow + time.Minute, wantStatus: models.CommentStatusApproved},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			author := createTestUser(t, db, "author")
+			other := createTestUser(t, db, "other")
+			blog := createTestBlog(t, db, author, "published", true)
+			service := newTestCommentService(db)
+
+			comment := models.Comment{BlogId: blog.ID, Content: "text"}
+			if err := service.CreateComment(&comment, author.ID, constants.RoleUser); err != nil {
+				t.Fatal(err)
+			}
+			reply := models.Comment{BlogId: blog.ID, ParentId: &comment.ID, Content: "reply"}
+			if err := service.Moderate(comment.ID, true, other.ID); err != nil {
+				t.Fatal(err)
+			}
+			if err := service.CreateComment(&reply, other.ID, constants.RoleAdmin); err != nil {
+				t.Fatal(err)
+			}
+			if err := db.DB.Model(&comment).Update("created_at", time.Now().Add(-test.age)).Error; err != nil {
+				t.Fatal(err)
+			}
+			userID := other.ID
+			if test.byAuthor {
+				userID = author.ID
+			}
+
+			updateErr := service.UpdateComment(comment.ID, "changed", userID, test.role)
+			deleteErr := service.DeleteComment(comment.ID, userID, test.role)
+			if test.wantErr {
+				if errors.GetErrorType(updateErr) != errors.Forbidden || errors.GetErrorType(deleteErr) != errors.Forbidden {
+					t.Errorf("update error = %v, delete error = %v, want Forbidden", updateErr, deleteErr)
+				}
+				return
+			}
+			if updateErr != nil || deleteErr != nil {
+				t.Fatalf("update error = %v, delete error = %v", updateErr, deleteErr)
+			}
+
+			var stored models.Comment
+			if err := db.DB.Unscoped().First(&stored, comment.ID).Error; err != nil {
+				t.Fatal(err)
+			}
+			if stored.Content != "changed" || stored.Status != test.wantStatus {
+				t.Errorf("comment = %q %s, want changed %s", stored.Content, stored.Status, test.wantStatus)
+			}
+			if _, err := service.GetOneComment(reply.ID); errors.GetErrorType(err) != errors.NotFound {
+				t.Errorf("reply of the deleted comment error = %v, want NotFound", err)
+			}
+		})
+	}
+}
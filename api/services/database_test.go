@@ -0,0 +1,62 @@
+package services
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"go.uber.org/zap"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testLogger -> logger that drops everything
+var testLogger = infrastructure.Logger{Zap: zap.NewNop().Sugar()}
+
+// newTestDatabase -> sqlite database in the test directory with the schema of the sqlite migrations
+func newTestDatabase(t *testing.T) infrastructure.Database {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_foreign_keys=on"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrations, err := filepath.Glob(filepath.Join("..", "..", "migration", infrastructure.DriverSQLite, "*.up.sql"))
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("sqlite migrations not found: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		statements, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Exec(string(statements)).Error; err != nil {
+			t.Fatalf("migration %s: %v", filepath.Base(migration), err)
+		}
+	}
+	return infrastructure.Database{DB: db}
+}
+
+// createTestUser -> user without firebase account, credentials are derived from the username
+func createTestUser(t *testing.T, db infrastructure.Database, username string) models.User {
+	t.Helper()
+	user := models.User{
+		Username: username,
+		Email:    username + "@example.com",
+		Phone:    username,
+		FullName: username,
+		Address:  "address",
+		Password: "password",
+		Version:  1,
+	}
+	// the role lives on the organization membership, the user table has no such column
+	if err := db.DB.Omit("Role", "FirebaseUID").Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
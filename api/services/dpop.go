@@ -0,0 +1,244 @@
+package services
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"container/heap"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	// DPoPProofType -> required `typ` header of a DPoP proof
+	DPoPProofType = "dpop+jwt"
+
+	// dpopProofLifetime -> how long a proof is accepted after its `iat`
+	dpopProofLifetime = 5 * time.Minute
+
+	// dpopClockSkew -> allowed skew for proofs issued slightly in the future
+	dpopClockSkew = 30 * time.Second
+)
+
+// dpopSigningMethods -> asymmetric algorithms accepted for DPoP proofs
+var dpopSigningMethods = []string{"ES256", "ES384", "ES512", "RS256", "RS384", "RS512", "PS256", "PS384", "PS512"}
+
+// DPoPClaims -> claims carried by a DPoP proof (RFC 9449 section 4.2)
+type DPoPClaims struct {
+	HTM string `json:"htm"`
+	HTU string `json:"htu"`
+	ATH string `json:"ath,omitempty"`
+	jwt.StandardClaims
+}
+
+// DPoPJWK -> public JWK embedded in the proof header
+type DPoPJWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	D   string `json:"d,omitempty"`
+}
+
+// DPoPProof -> input for verifying a DPoP proof
+type DPoPProof struct {
+	Proof       string
+	Method      string
+	URL         string
+	AccessToken string
+}
+
+// dpopReplayCache -> remembers seen proof ids until they expire
+type dpopReplayCache struct {
+	mu     sync.Mutex
+	seen   map[string]struct{}
+	expiry dpopReplayQueue
+}
+
+// dpopReplayEntry -> proof id with the time it can no longer be replayed
+type dpopReplayEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+// dpopReplayQueue -> min-heap of the seen proof ids by expiry, pruning only touches expired entries
+type dpopReplayQueue []dpopReplayEntry
+
+func (q dpopReplayQueue) Len() int            { return len(q) }
+func (q dpopReplayQueue) Less(i, j int) bool  { return q[i].expiresAt.Before(q[j].expiresAt) }
+func (q dpopReplayQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *dpopReplayQueue) Push(x interface{}) { *q = append(*q, x.(dpopReplayEntry)) }
+func (q *dpopReplayQueue) Pop() interface{} {
+	old := *q
+	entry := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return entry
+}
+
+// DPoPService -> verifies DPoP proof-of-possession proofs
+type DPoPService struct {
+	logger infrastructure.Logger
+	replay *dpopReplayCache
+}
+
+// NewDPoPService -> creates a new DPoPService
+func NewDPoPService(logger infrastructure.Logger) DPoPService {
+	return DPoPService{
+		logger: logger,
+		replay: &dpopReplayCache{seen: map[string]struct{}{}},
+	}
+}
+
+// VerifyProof validates the proof and returns the thumbprint of the key that signed it
+func (s DPoPService) VerifyProof(input DPoPProof) (string, error) {
+	var jwk DPoPJWK
+	claims := DPoPClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(dpopSigningMethods), jwt.WithoutClaimsValidation())
+	_, err := parser.ParseWithClaims(input.Proof, &claims, func(token *jwt.Token) (interface{}, error) {
+		if typ, _ := token.Header["typ"].(string); typ != DPoPProofType {
+			return nil, errors.BadRequest.New("invalid DPoP proof type")
+		}
+		raw, err := json.Marshal(token.Header["jwk"])
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &jwk); err != nil {
+			return nil, err
+		}
+		return jwk.PublicKey()
+	})
+	if err != nil {
+		return "", errors.Unauthorized.Wrap(err, "invalid DPoP proof")
+	}
+
+	if claims.HTM != input.Method {
+		return "", errors.Unauthorized.New("DPoP proof htm mismatch")
+	}
+	if claims.HTU != input.URL {
+		return "", errors.Unauthorized.New("DPoP proof htu mismatch")
+	}
+	if claims.Id == "" {
+		return "", errors.Unauthorized.New("DPoP proof without jti")
+	}
+
+	issuedAt := time.Unix(claims.IssuedAt, 0)
+	now := time.Now()
+	if issuedAt.After(now.Add(dpopClockSkew)) || now.Sub(issuedAt) > dpopProofLifetime {
+		return "", errors.Unauthorized.New("DPoP proof iat outside of acceptable window")
+	}
+
+	if input.AccessToken != "" {
+		hash := sha256.Sum256([]byte(input.AccessToken))
+		if claims.ATH != base64.RawURLEncoding.EncodeToString(hash[:]) {
+			return "", errors.Unauthorized.New("DPoP proof ath mismatch")
+		}
+	}
+
+	thumbprint, err := jwk.Thumbprint()
+	if err != nil {
+		return "", errors.Unauthorized.Wrap(err, "invalid DPoP proof key")
+	}
+
+	if !s.replay.add(thumbprint+":"+claims.Id, issuedAt.Add(dpopProofLifetime+dpopClockSkew)) {
+		return "", errors.Unauthorized.New("DPoP proof replayed")
+	}
+
+	return thumbprint, nil
+}
+
+// add stores the key and reports false if it was already seen, expired keys are dropped first
+func (c *dpopReplayCache) add(key string, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for len(c.expiry) > 0 && now.After(c.expiry[0].expiresAt) {
+		entry := heap.Pop(&c.expiry).(dpopReplayEntry)
+		delete(c.seen, entry.key)
+	}
+	if _, ok := c.seen[key]; ok {
+		return false
+	}
+	c.seen[key] = struct{}{}
+	heap.Push(&c.expiry, dpopReplayEntry{key: key, expiresAt: expiresAt})
+	return true
+}
+
+// PublicKey converts the JWK into a crypto public key
+func (k DPoPJWK) PublicKey() (crypto.PublicKey, error) {
+	if k.D != "" {
+		return nil, errors.BadRequest.New("DPoP jwk must not contain a private key")
+	}
+	switch k.Kty {
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.BadRequest.Newf("unsupported DPoP jwk curve: %s", k.Crv)
+		}
+		x, err := decodeJWKInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.BadRequest.New("DPoP jwk point is not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "RSA":
+		n, err := decodeJWKInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	default:
+		return nil, errors.BadRequest.Newf("unsupported DPoP jwk type: %s", k.Kty)
+	}
+}
+
+// Thumbprint computes the RFC 7638 SHA-256 thumbprint of the JWK
+func (k DPoPJWK) Thumbprint() (string, error) {
+	var canonical string
+	switch k.Kty {
+	case "EC":
+		canonical = `{"crv":"` + k.Crv + `","kty":"EC","x":"` + k.X + `","y":"` + k.Y + `"}`
+	case "RSA":
+		canonical = `{"e":"` + k.E + `","kty":"RSA","n":"` + k.N + `"}`
+	default:
+		return "", errors.BadRequest.Newf("unsupported DPoP jwk type: %s", k.Kty)
+	}
+	hash := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(hash[:]), nil
+}
+
+func decodeJWKInt(value string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.BadRequest.Wrap(err, "invalid DPoP jwk parameter")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"container/heap"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+)
+
+func newTestDPoPService() DPoPService {
+	return NewDPoPService(infrastructure.Logger{Zap: zap.NewNop().Sugar()})
+}
+
+func ecJWK(key *ecdsa.PrivateKey) DPoPJWK {
+	size := (key.Curve.Params().BitSize + 7) / 8
+	return DPoPJWK{
+		Kty: "EC",
+		Crv: key.Curve.Params().Name,
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, size))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, size))),
+	}
+}
+
+func signProof(t *testing.T, key *ecdsa.PrivateKey, header map[string]interface{}, claims DPoPClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["typ"] = DPoPProofType
+	token.Header["jwk"] = ecJWK(key)
+	for name, value := range header {
+		token.Header[name] = value
+	}
+	proof, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return proof
+}
+
+func accessTokenHash(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+func TestDPoPServiceVerifyProof(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	thumbprint, err := ecJWK(key).Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		method      = "GET"
+		url         = "https://api.example.com/user"
+		accessToken = "access-token"
+	)
+	now := time.Now()
+	valid := func() DPoPClaims {
+		return DPoPClaims{
+			HTM:            method,
+			HTU:            url,
+			ATH:            accessTokenHash(accessToken),
+			StandardClaims: jwt.StandardClaims{Id: "jti", IssuedAt: now.Unix()},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		header  map[string]interface{}
+		claims  func(claims *DPoPClaims)
+		token   string
+		wantErr bool
+	}{
+		{name: "valid proof", token: accessToken},
+		{name: "valid proof without access token", claims: func(claims *DPoPClaims) { claims.ATH = "" }},
+		{name: "wrong typ", header: map[string]interface{}{"typ": "JWT"}, token: accessToken, wantErr: true},
+		{name: "private key in jwk", header: map[string]interface{}{"jwk": DPoPJWK{Kty: "EC", Crv: "P-256", D: "secret"}}, token: accessToken, wantErr: true},
+		{name: "unsupported key type", header: map[string]interface{}{"jwk": DPoPJWK{Kty: "oct"}}, token: accessToken, wantErr: true},
+		{name: "htm mismatch", claims: func(claims *DPoPClaims) { claims.HTM = "POST" }, token: accessToken, wantErr: true},
+		{name: "htu mismatch", claims: func(claims *DPoPClaims) { claims.HTU = url + "/other" }, token: accessToken, wantErr: true},
+		{name: "missing jti", claims: func(claims *DPoPClaims) { claims.Id = "" }, token: accessToken, wantErr: true},
+		{name: "expired iat", claims: func(claims *DPoPClaims) { claims.IssuedAt = now.Add(-dpopProofLifetime - time.Minute).Unix() }, token: accessToken, wantErr: true},
+		{name: "future iat", claims: func(claims *DPoPClaims) { claims.IssuedAt = now.Add(dpopClockSkew + time.Minute).Unix() }, token: accessToken, wantErr: true},
+		{name: "iat within clock skew", claims: func(claims *DPoPClaims) { claims.IssuedAt = now.Add(dpopClockSkew / 2).Unix() }, token: accessToken},
+		{name: "ath mismatch", token: "other-token", wantErr: true},
+	}
+
+	for i, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			claims := valid()
+			claims.Id = claims.Id + string(rune('a'+i))
+			if test.claims != nil {
+				test.claims(&claims)
+			}
+			proof := signProof(t, key, test.header, claims)
+
+			got, err := newTestDPoPService().VerifyProof(DPoPProof{Proof: proof, Method: method, URL: url, AccessToken: test.token})
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				if errors.GetErrorType(err) != errors.Unauthorized {
+					t.Errorf("error type = %v, want Unauthorized", errors.GetErrorType(err))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != thumbprint {
+				t.Errorf("thumbprint = %q, want %q", got, thumbprint)
+			}
+		})
+	}
+}
+
+func TestDPoPServiceVerifyProofSymmetricAlgorithm(t *testing.T) {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, DPoPClaims{
+		HTM:            "GET",
+		HTU:            "https://api.example.com/user",
+		StandardClaims: jwt.StandardClaims{Id: "jti", IssuedAt: time.Now().Unix()},
+	})
+	token.Header["typ"] = DPoPProofType
+	proof, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTestDPoPService().VerifyProof(DPoPProof{Proof: proof, Method: "GET", URL: "https://api.example.com/user"}); err == nil {
+		t.Fatal("expected HS256 proofs to be rejected")
+	}
+}
+
+func TestDPoPServiceVerifyProofReplay(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof := signProof(t, key, nil, DPoPClaims{
+		HTM:            "GET",
+		HTU:            "https://api.example.com/user",
+		StandardClaims: jwt.StandardClaims{Id: "jti", IssuedAt: time.Now().Unix()},
+	})
+	input := DPoPProof{Proof: proof, Method: "GET", URL: "https://api.example.com/user"}
+
+	service := newTestDPoPService()
+	if _, err := service.VerifyProof(input); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if _, err := service.VerifyProof(input); err == nil {
+		t.Fatal("expected the replayed proof to be rejected")
+	}
+}
+
+func TestDPoPReplayCacheAdd(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		seen      map[string]time.Time
+		key       string
+		wantAdded bool
+		wantSize  int
+	}{
+		{name: "new key", key: "a", wantAdded: true, wantSize: 1},
+		{name: "seen key", seen: map[string]time.Time{"a": now.Add(time.Minute)}, key: "a", wantAdded: false, wantSize: 1},
+		{name: "expired key is accepted again", seen: map[string]time.Time{"a": now.Add(-time.Minute)}, key: "a", wantAdded: true, wantSize: 1},
+		{name: "expired keys are pruned", seen: map[string]time.Time{"a": now.Add(-time.Minute), "b": now.Add(-time.Second), "c": now.Add(time.Minute)}, key: "d", wantAdded: true, wantSize: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cache := &dpopReplayCache{seen: map[string]struct{}{}}
+			for key, expiresAt := range test.seen {
+				cache.seen[key] = struct{}{}
+				heap.Push(&cache.expiry, dpopReplayEntry{key: key, expiresAt: expiresAt})
+			}
+
+			if added := cache.add(test.key, now.Add(time.Minute)); added != test.wantAdded {
+				t.Errorf("add = %v, want %v", added, test.wantAdded)
+			}
+			if len(cache.seen) != test.wantSize || len(cache.expiry) != test.wantSize {
+				t.Errorf("cache size = %d/%d, want %d", len(cache.seen), len(cache.expiry), test.wantSize)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// IdempotencyKeyDefaultHours -> hours a stored response is replayed when IdempotencyKeyHours is not set
+	IdempotencyKeyDefaultHours = 24
+
+	// IdempotencyKeyMaxLength -> longest accepted Idempotency-Key header
+	IdempotencyKeyMaxLength = 255
+)
+
+// IdempotencyService -> stores responses of requests sent with an Idempotency-Key so retries can be replayed
+type IdempotencyService struct {
+	repository repository.IdempotencyKeyRepository
+	logger     infrastructure.Logger
+	ttl        time.Duration
+}
+
+// NewIdempotencyService -> creates a new IdempotencyService
+func NewIdempotencyService(
+	repository repository.IdempotencyKeyRepository,
+	logger infrastructure.Logger,
+	env infrastructure.Env,
+) IdempotencyService {
+	hours := IdempotencyKeyDefaultHours
+	if env.IdempotencyKeyHours != "" {
+		parsed, err := strconv.Atoi(env.IdempotencyKeyHours)
+		if err != nil || parsed <= 0 {
+			logger.Zap.Fatalf("IdempotencyKeyHours: invalid number of hours %q", env.IdempotencyKeyHours)
+		}
+		hours = parsed
+	}
+	return IdempotencyService{
+		repository: repository,
+		logger:     logger,
+		ttl:        time.Duration(hours) * time.Hour,
+	}
+}
+
+// WithTrx -> enables the service with transaction, the key is stored along with the changes of the request
+func (c IdempotencyService) WithTrx(trxHandle *gorm.DB) IdempotencyService {
+	c.repository = c.repository.WithTrx(trxHandle)
+	return c
+}
+
+// Begin -> stored response of an earlier request with the key, otherwise reserves the key for this request
+// the key fails with Conflict when it was used for a different request or the first request is still running
+func (c IdempotencyService) Begin(userID int64, key string, method string, path string, body []byte) (*models.IdempotencyKey, bool, error) {
+	if len(key) > IdempotencyKeyMaxLength {
+		err := errors.BadRequest.Newf("idempotency key of %d characters", len(key))
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return nil, false, errors.AddErrorContext(err, "Idempotency-Key", "Idempotency-Key can have at most 255 characters.")
+	}
+	fingerprint := requestFingerprint(method, path, body)
+
+	record, err := c.repository.GetKey(userID, key)
+	if err == nil {
+		if record.Fingerprint != fingerprint {
+			err := errors.Conflict.New("idempotency key reused with a different request")
+			return nil, false, errors.SetCustomMessage(err, "Idempotency-Key was already used for a different request")
+		}
+		if !record.IsCompleted() {
+			return nil, false, idempotencyInProgress()
+		}
+		return &record, true, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, false, err
+	}
+
+	record = models.IdempotencyKey{
+		UserID:      userID,
+		Key:         key,
+		Fingerprint: fingerprint,
+		ExpiresAt:   time.Now().Add(c.ttl),
+	}
+	if err := c.repository.Reserve(&record); err != nil {
+		if errors.GetErrorType(err) == errors.Conflict {
+			return nil, false, idempotencyInProgress()
+		}
+		return nil, false, err
+	}
+	return &record, false, nil
+}
+
+// Complete -> stores the response replayed for retries of the request
+func (c IdempotencyService) Complete(record *models.IdempotencyKey, status int, contentType string, body []byte) error {
+	return c.repository.Complete(record.ID, status, contentType, body)
+}
+
+// PurgeExpired -> deletes keys that can no longer be replayed
+func (c IdempotencyService) PurgeExpired() (int64, error) {
+	return c.repository.PurgeExpired(time.Now())
+}
+
+// requestFingerprint -> hash of what makes a retry the same request
+func requestFingerprint(method string, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func idempotencyInProgress() error {
+	err := errors.Conflict.New("idempotency key in use by a request still being processed")
+	return errors.SetCustomMessage(err, "A request with this Idempotency-Key is still being processed, retry later")
+}
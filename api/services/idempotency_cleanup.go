@@ -0,0 +1,60 @@
+package services
+
+import (
+	"boilerplate-api/infrastructure"
+	"time"
+)
+
+// IdempotencyCleanupInterval -> how often expired idempotency keys are deleted
+const IdempotencyCleanupInterval = time.Hour
+
+// IdempotencyCleanupJob -> deletes idempotency keys once their responses can no longer be replayed
+type IdempotencyCleanupJob struct {
+	logger  infrastructure.Logger
+	service IdempotencyService
+	stop    chan struct{}
+}
+
+// NewIdempotencyCleanupJob -> creates a new IdempotencyCleanupJob
+func NewIdempotencyCleanupJob(
+	logger infrastructure.Logger,
+	service IdempotencyService,
+) IdempotencyCleanupJob {
+	return IdempotencyCleanupJob{
+		logger:  logger,
+		service: service,
+		stop:    make(chan struct{}),
+	}
+}
+
+// Start -> deletes expired keys every interval until Stop is called
+func (s IdempotencyCleanupJob) Start() {
+	ticker := time.NewTicker(IdempotencyCleanupInterval)
+	defer ticker.Stop()
+
+	s.purgeExpired()
+	for {
+		select {
+		case <-ticker.C:
+			s.purgeExpired()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Stop -> stops the job
+func (s IdempotencyCleanupJob) Stop() {
+	close(s.stop)
+}
+
+func (s IdempotencyCleanupJob) purgeExpired() {
+	deleted, err := s.service.PurgeExpired()
+	if err != nil {
+		s.logger.Zap.Error("Error [IdempotencyCleanupJob] [PurgeExpired]: ", err.Error())
+		return
+	}
+	if deleted > 0 {
+		s.logger.Zap.Infof("🔑 deleted %d expired idempotency keys", deleted)
+	}
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIdempotencyServiceBegin(t *testing.T) {
+	db := newTestDatabase(t)
+	user := createTestUser(t, db, "jane")
+	service := NewIdempotencyService(repository.NewIdempotencyKeyRepository(db, testLogger), testLogger, infrastructure.Env{})
+	body := []byte(`{"task":"milk"}`)
+
+	record, replay, err := service.Begin(user.ID, "key", http.MethodPost, "/api/todos", body)
+	if err != nil || replay {
+		t.Fatalf("first request = replay %v, %v, want the key reserved", replay, err)
+	}
+	if _, _, err := service.Begin(user.ID, "key", http.MethodPost, "/api/todos", body); errors.GetErrorType(err) != errors.Conflict {
+		t.Errorf("retry while running error = %v, want Conflict", err)
+	}
+	if err := service.Complete(record, http.StatusCreated, "application/json", []byte(`{"id":1}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		userID      int64
+		key         string
+		path        string
+		body        []byte
+		wantReplay  bool
+		wantErr     bool
+		wantErrType errors.HttpErrorType
+	}{
+		{name: "retry", userID: user.ID, key: "key", path: "/api/todos", body: body, wantReplay: true},
+		{name: "different body", userID: user.ID, key: "key", path: "/api/todos", body: []byte(`{"task":"bread"}`), wantErr: true, wantErrType: errors.Conflict},
+		{name: "different path", userID: user.ID, key: "key", path: "/api/todo-lists", body: body, wantErr: true, wantErrType: errors.Conflict},
+		{name: "key of another user", userID: user.ID + 1, key: "key", path: "/api/todos", body: body},
+		{name: "key too long", userID: user.ID, key: strings.Repeat("k", IdempotencyKeyMaxLength+1), path: "/api/todos", body: body, wantErr: true, wantErrType: errors.BadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stored, replay, err := service.Begin(test.userID, test.key, http.MethodPost, test.path, test.body)
+			if test.wantErr {
+				if err == nil || errors.GetErrorType(err) != test.wantErrType {
+					t.Fatalf("error = %v, want type %v", err, test.wantErrType)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if replay != test.wantReplay {
+				t.Fatalf("replay = %v, want %v", replay, test.wantReplay)
+			}
+			if replay && (stored.Status != http.StatusCreated || string(stored.Body) != `{"id":1}`) {
+				t.Errorf("replayed response = %d %s, want the stored one", stored.Status, stored.Body)
+			}
+		})
+	}
+}
+
+func TestIdempotencyServiceExpiredKeys(t *testing.T) {
+	db := newTestDatabase(t)
+	user := createTestUser(t, db, "jane")
+	service := NewIdempotencyService(repository.NewIdempotencyKeyRepository(db, testLogger), testLogger, infrastructure.Env{})
+
+	expired := models.IdempotencyKey{UserID: user.ID, Key: "key", Fingerprint: "other request", Status: http.StatusOK, ExpiresAt: time.Now().Add(-time.Minute)}
+	if err := db.DB.Create(&expired).Error; err != nil {
+		t.Fatal(err)
+	}
+	record, replay, err := service.Begin(user.ID, "key", http.MethodPost, "/api/todos", nil)
+	if err != nil || replay {
+		t.Fatalf("reuse of an expired key = replay %v, %v, want the key reserved again", replay, err)
+	}
+	if record.ID == expired.ID {
+		t.Error("expired key was not replaced")
+	}
+
+	if err := db.DB.Model(record).Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+	purged, err := service.PurgeExpired()
+	if err != nil || purged != 1 {
+		t.Errorf("purged = %d, %v, want 1", purged, err)
+	}
+}
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvitationLifetime -> how long an invitation link can be used
+const InvitationLifetime = 72 * time.Hour
+
+// InvitationService -> struct
+type InvitationService struct {
+	repository   repository.InvitationRepository
+	userService  UserService
+	gmailService GmailService
+	env          infrastructure.Env
+	logger       infrastructure.Logger
+	tenantID     int64
+
+	organizationRepository repository.OrganizationRepository
+}
+
+// NewInvitationService -> creates a new InvitationService
+func NewInvitationService(
+	repository repository.InvitationRepository,
+	userService UserService,
+	gmailService GmailService,
+	env infrastructure.Env,
+	logger infrastructure.Logger,
+	organizationRepository repository.OrganizationRepository,
+) InvitationService {
+	return InvitationService{
+		repository:   repository,
+		userService:  userService,
+		gmailService: gmailService,
+		env:          env,
+		logger:       logger,
+
+		organizationRepository: organizationRepository,
+	}
+}
+
+// WithTrx -> enables repository with transaction
+func (c InvitationService) WithTrx(trxHandle *gorm.DB) InvitationService {
+	c.repository = c.repository.WithTrx(trxHandle)
+	c.userService = c.userService.WithTrx(trxHandle)
+	c.organizationRepository = c.organizationRepository.WithTrx(trxHandle)
+	return c
+}
+
+// WithTenant -> limits the service to the organization
+func (c InvitationService) WithTenant(tenantID int64) InvitationService {
+	c.tenantID = tenantID
+	c.repository = c.repository.WithTenant(tenantID)
+	return c
+}
+
+// Invite -> stores an invitation for the email and sends the link
+// registered users can only be invited to join the organization of the service
+func (c InvitationService) Invite(email string, role string, inviter models.User) (*models.Invitation, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if role == constants.RoleAdmin && inviter.Role != constants.RoleAdmin {
+		return nil, errors.Forbidden.New("only admins can invite admins")
+	}
+	if user, err := c.userService.GetOneUserWithEmail(email); err == nil {
+		if c.tenantID == 0 {
+			err := errors.Conflict.New("user already exists")
+			return nil, errors.SetCustomMessage(err, "Email address already taken")
+		}
+		if _, err := c.organizationRepository.GetMembership(c.tenantID, user.ID); err == nil {
+			err := errors.Conflict.New("user already member of the organization")
+			return nil, errors.SetCustomMessage(err, "User is already a member of the organization")
+		}
+	}
+	if _, err := c.repository.GetPendingByEmail(email); err == nil {
+		err := errors.Conflict.New("invitation already pending")
+		return nil, errors.SetCustomMessage(err, "Invitation already sent to this email")
+	}
+
+	invitation := models.Invitation{
+		Email:       email,
+		Role:        role,
+		InvitedByID: inviter.ID,
+	}
+	c.refreshToken(&invitation)
+	if err := c.repository.Create(&invitation); err != nil {
+		return nil, err
+	}
+	if err := c.send(&invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// GetAllPending -> Get all pending invitations
+func (c InvitationService) GetAllPending(pagination utils.Pagination) ([]models.Invitation, int64, error) {
+	return c.repository.GetAllPending(pagination)
+}
+
+// Resend -> regenerates the invitation link and sends it again
+func (c InvitationService) Resend(ID int64) (*models.Invitation, error) {
+	invitation, err := c.repository.GetOnePending(ID)
+	if err != nil {
+		return nil, errors.NotFound.Wrap(err, "invitation not found")
+	}
+	c.refreshToken(&invitation)
+	if err := c.repository.UpdateToken(invitation); err != nil {
+		return nil, err
+	}
+	if err := c.send(&invitation); err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// Revoke -> deletes a pending invitation
+func (c InvitationService) Revoke(ID int64) error {
+	if err := c.repository.Delete(ID); err != nil {
+		return errors.NotFound.Wrap(err, "invitation not found")
+	}
+	return nil
+}
+
+// Accept -> adds a registered user to the organization of the invitation,
+// otherwise creates the invited user with the given profile and hashed password
+func (c InvitationService) Accept(token string, user *models.User) (*models.User, error) {
+	invitation, err := c.repository.GetPendingByToken(token)
+	if err != nil {
+		return nil, errors.NotFound.Wrap(err, "invitation not found")
+	}
+	if invitation.IsExpired() {
+		err := errors.BadRequest.New("invitation has expired")
+		return nil, errors.SetCustomMessage(err, "Invitation has expired")
+	}
+	if registered, err := c.userService.GetOneUserWithEmail(invitation.Email); err == nil {
+		return c.join(invitation, *registered)
+	}
+	if user == nil {
+		err := errors.BadRequest.New("profile required to sign up")
+		return nil, errors.SetCustomMessage(err, "Username, full name, phone, address and password are required")
+	}
+
+	user.Email = invitation.Email
+	user.Role = invitation.Role
+	user.EmailVerified = true
+
+	userService := c.userService
+	if invitation.OrganizationID != nil {
+		userService = userService.WithTenant(*invitation.OrganizationID)
+	}
+	created, err := userService.CreateUser(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.repository.MarkAccepted(invitation.ID); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+// join -> adds the registered user to the organization of the invitation
+func (c InvitationService) join(invitation models.Invitation, user models.User) (*models.User, error) {
+	if invitation.OrganizationID == nil {
+		err := errors.Conflict.New("user already exists")
+		return nil, errors.SetCustomMessage(err, "Email address already taken")
+	}
+	membership := models.OrganizationMember{
+		OrganizationID: *invitation.OrganizationID,
+		UserID:         user.ID,
+		Role:           invitation.Role,
+	}
+	if err := c.organizationRepository.CreateMembership(&membership); err != nil {
+		return nil, err
+	}
+	if user.OrganizationID == nil {
+		if err := c.organizationRepository.SetCurrentOrganization(user.ID, membership.OrganizationID); err != nil {
+			return nil, err
+		}
+		user.OrganizationID = invitation.OrganizationID
+	}
+	if err := c.repository.MarkAccepted(invitation.ID); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// refreshToken -> sets a new token and expiry on the invitation
+func (c InvitationService) refreshToken(invitation *models.Invitation) {
+	invitation.Token = utils.GenerateSecureToken(32)
+	invitation.ExpiresAt = time.Now().Add(InvitationLifetime)
+}
+
+// send -> emails the invitation link
+func (c InvitationService) send(invitation *models.Invitation) error {
+	_, registeredErr := c.userService.GetOneUserWithEmail(invitation.Email)
+	_, err := c.gmailService.SendEmail(models.EmailParams{
+		To:          invitation.Email,
+		SubjectData: "You have been invited",
+		BodyData: map[string]interface{}{
+			"Registered": registeredErr == nil,
+			"Role":       invitation.Role,
+			"URL":        c.env.ClientURL + "/invitations/accept?token=" + invitation.Token,
+			"ExpiresAt":  invitation.ExpiresAt.Format(time.RFC1123),
+		},
+		BodyTemplate: "invitation_body.txt",
+		Lang:         "en",
+	})
+	if err != nil {
+		c.logger.Zap.Error("Error sending invitation email: ", err.Error())
+		return errors.InternalError.Wrap(err, "failed to send invitation email")
+	}
+	sentAt := time.Now()
+	invitation.SentAt = &sentAt
+	return c.repository.UpdateToken(*invitation)
+}
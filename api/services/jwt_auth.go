@@ -1,26 +1,162 @@
 package services
 
 import (
+	"boilerplate-api/errors"
 	"boilerplate-api/infrastructure"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
+	"io/ioutil"
+	"strings"
 	"time"
 
+	"github.com/go-jose/go-jose/v3"
 	"github.com/golang-jwt/jwt/v4"
 )
 
 type JWTAuthService struct {
-	logger infrastructure.Logger
-	env    infrastructure.Env
+	logger        infrastructure.Logger
+	env           infrastructure.Env
+	encryptionKey crypto.PrivateKey
+	keyAlgorithm  jose.KeyAlgorithm
+	claimMappings []claimMapping
 }
 
 func NewJWTAuthService(
 	logger infrastructure.Logger,
 	env infrastructure.Env,
 ) JWTAuthService {
-	return JWTAuthService{
+	service := JWTAuthService{
 		logger: logger,
 		env:    env,
 	}
+	claimMappings, err := parseClaimMappings(env.JWT_CLAIMS, env.JWT_ENCRYPTION_ENABLED == "true")
+	if err != nil {
+		logger.Zap.Fatalf("JWT claims: %v", err)
+	}
+	service.claimMappings = claimMappings
+
+	if env.JWT_ENCRYPTION_ENABLED != "true" {
+		return service
+	}
+
+	key, err := loadEncryptionKey(env.JWT_ENCRYPTION_KEY_FILE)
+	if err != nil {
+		logger.Zap.Fatalf("JWT encryption key: %v", err)
+	}
+	service.encryptionKey = key
+
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		service.keyAlgorithm = jose.RSA_OAEP_256
+		if env.JWT_ENCRYPTION_ALGORITHM == string(jose.RSA_OAEP) {
+			service.keyAlgorithm = jose.RSA_OAEP
+		}
+	case *ecdsa.PrivateKey:
+		service.keyAlgorithm = jose.ECDH_ES_A256KW
+		if env.JWT_ENCRYPTION_ALGORITHM == string(jose.ECDH_ES) {
+			service.keyAlgorithm = jose.ECDH_ES
+		}
+	}
+	logger.Zap.Info("✅ JWT encryption enabled with ", service.keyAlgorithm)
+	return service
+}
+
+// loadEncryptionKey reads an RSA or EC private key from a PEM file
+func loadEncryptionKey(path string) (crypto.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		switch key.(type) {
+		case *rsa.PrivateKey, *ecdsa.PrivateKey:
+			return key, nil
+		}
+		return nil, fmt.Errorf("unsupported key type %T", key)
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// IsEncryptionEnabled -> whether issued tokens are wrapped in JWE
+func (m JWTAuthService) IsEncryptionEnabled() bool {
+	return m.encryptionKey != nil
+}
+
+// SignToken -> signs the claims and encrypts the result when encryption is enabled
+func (m JWTAuthService) SignToken(claims jwt.Claims) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(m.env.JWT_SECRET))
+	if err != nil {
+		return "", err
+	}
+	if !m.IsEncryptionEnabled() {
+		return token, nil
+	}
+	return m.EncryptToken(token)
+}
+
+// EncryptToken -> wraps a signed JWT in a JWE (nested JWS-in-JWE)
+func (m JWTAuthService) EncryptToken(signedToken string) (string, error) {
+	var publicKey interface{}
+	switch key := m.encryptionKey.(type) {
+	case *rsa.PrivateKey:
+		publicKey = &key.PublicKey
+	case *ecdsa.PrivateKey:
+		publicKey = &key.PublicKey
+	default:
+		return "", errors.InternalError.New("JWT encryption is not configured")
+	}
+
+	encrypter, err := jose.NewEncrypter(
+		jose.A256GCM,
+		jose.Recipient{Algorithm: m.keyAlgorithm, Key: publicKey},
+		(&jose.EncrypterOptions{}).WithContentType("JWT").WithType("JWT"),
+	)
+	if err != nil {
+		return "", err
+	}
+	object, err := encrypter.Encrypt([]byte(signedToken))
+	if err != nil {
+		return "", err
+	}
+	return object.CompactSerialize()
+}
+
+// DecryptToken -> returns the inner signed JWT of a JWE
+// with encryption enabled only JWEs are accepted, without it only plain JWTs
+func (m JWTAuthService) DecryptToken(tokenString string) (string, error) {
+	encrypted := strings.Count(tokenString, ".") == 4
+	if !m.IsEncryptionEnabled() {
+		if encrypted {
+			return "", errors.BadRequest.New("encrypted token received but JWT encryption is not configured")
+		}
+		return tokenString, nil
+	}
+	if !encrypted {
+		return "", errors.BadRequest.New("plain token received but JWT encryption is enabled")
+	}
+	object, err := jose.ParseEncrypted(tokenString)
+	if err != nil {
+		return "", errors.BadRequest.Wrap(err, "invalid encrypted token")
+	}
+	if object.Header.Algorithm != string(m.keyAlgorithm) {
+		return "", errors.BadRequest.Newf("unexpected key management algorithm: %s", object.Header.Algorithm)
+	}
+	payload, err := object.Decrypt(m.encryptionKey)
+	if err != nil {
+		return "", errors.BadRequest.Wrap(err, "failed to decrypt token")
+	}
+	return string(payload), nil
 }
 
 func (m JWTAuthService) VerifyToken(tokenString string) (*jwt.MapClaims, bool) {
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"boilerplate-api/infrastructure"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+)
+
+const testJWTSecret = "secret"
+
+// writeKeyFile -> writes the key as PEM into a temporary file
+func writeKeyFile(t *testing.T, blockType string, der []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "key.pem")
+	if err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func rsaKeyFile(t *testing.T) string {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeKeyFile(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+}
+
+func ecKeyFile(t *testing.T) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writeKeyFile(t, "EC PRIVATE KEY", der)
+}
+
+func newTestJWTAuthService(keyFile string, algorithm string) JWTAuthService {
+	env := infrastructure.Env{JWT_SECRET: testJWTSecret, JWT_ENCRYPTION_ALGORITHM: algorithm}
+	if keyFile != "" {
+		env.JWT_ENCRYPTION_ENABLED = "true"
+		env.JWT_ENCRYPTION_KEY_FILE = keyFile
+	}
+	return NewJWTAuthService(infrastructure.Logger{Zap: zap.NewNop().Sugar()}, env)
+}
+
+func TestLoadEncryptionKey(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    func(t *testing.T) string
+		wantErr bool
+	}{
+		{name: "PKCS1 RSA key", path: rsaKeyFile},
+		{name: "SEC1 EC key", path: ecKeyFile},
+		{name: "PKCS8 key", path: func(t *testing.T) string { return writeKeyFile(t, "PRIVATE KEY", pkcs8) }},
+		{name: "missing file", path: func(t *testing.T) string { return filepath.Join(t.TempDir(), "missing.pem") }, wantErr: true},
+		{name: "no PEM data", path: func(t *testing.T) string {
+			path := filepath.Join(t.TempDir(), "key.pem")
+			if err := ioutil.WriteFile(path, []byte("not a key"), 0600); err != nil {
+				t.Fatal(err)
+			}
+			return path
+		}, wantErr: true},
+		{name: "garbage key", path: func(t *testing.T) string { return writeKeyFile(t, "PRIVATE KEY", []byte("garbage")) }, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key, err := loadEncryptionKey(test.path(t))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got key %T", key)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestJWTAuthServiceEncryptedRoundTrip(t *testing.T) {
+	rsaKey, ecKey := rsaKeyFile(t), ecKeyFile(t)
+	tests := []struct {
+		name          string
+		keyFile       string
+		algorithm     string
+		wantAlgorithm jose.KeyAlgorithm
+	}{
+		{name: "RSA default", keyFile: rsaKey, wantAlgorithm: jose.RSA_OAEP_256},
+		{name: "RSA-OAEP", keyFile: rsaKey, algorithm: string(jose.RSA_OAEP), wantAlgorithm: jose.RSA_OAEP},
+		{name: "EC default", keyFile: ecKey, wantAlgorithm: jose.ECDH_ES_A256KW},
+		{name: "ECDH-ES", keyFile: ecKey, algorithm: string(jose.ECDH_ES), wantAlgorithm: jose.ECDH_ES},
+		{name: "algorithm of the other key type is ignored", keyFile: ecKey, algorithm: string(jose.RSA_OAEP), wantAlgorithm: jose.ECDH_ES_A256KW},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := newTestJWTAuthService(test.keyFile, test.algorithm)
+			if !service.IsEncryptionEnabled() {
+				t.Fatal("expected encryption to be enabled")
+			}
+			if service.keyAlgorithm != test.wantAlgorithm {
+				t.Errorf("key algorithm = %s, want %s", service.keyAlgorithm, test.wantAlgorithm)
+			}
+
+			token, err := service.SignToken(jwt.StandardClaims{Subject: "1", ExpiresAt: time.Now().Add(time.Hour).Unix()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			object, err := jose.ParseEncrypted(token)
+			if err != nil {
+				t.Fatalf("issued token is not a JWE: %v", err)
+			}
+			if object.Header.Algorithm != string(test.wantAlgorithm) {
+				t.Errorf("JWE alg = %s, want %s", object.Header.Algorithm, test.wantAlgorithm)
+			}
+
+			signed, err := service.DecryptToken(token)
+			if err != nil {
+				t.Fatalf("DecryptToken: %v", err)
+			}
+			claims, ok := service.VerifyToken(signed)
+			if !ok || (*claims)["sub"] != "1" {
+				t.Errorf("VerifyToken = %v, %v", claims, ok)
+			}
+		})
+	}
+}
+
+func TestJWTAuthServiceDecryptToken(t *testing.T) {
+	rsaKey := rsaKeyFile(t)
+	plain := newTestJWTAuthService("", "")
+	encrypted := newTestJWTAuthService(rsaKey, "")
+
+	signed, err := plain.SignToken(jwt.StandardClaims{Subject: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwe, err := encrypted.EncryptToken(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherAlgorithm, err := newTestJWTAuthService(rsaKey, string(jose.RSA_OAEP)).EncryptToken(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := newTestJWTAuthService(rsaKeyFile(t), "").EncryptToken(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		service JWTAuthService
+		token   string
+		want    string
+		wantErr bool
+	}{
+		{name: "plain token without encryption", service: plain, token: signed, want: signed},
+		{name: "encrypted token without encryption", service: plain, token: jwe, wantErr: true},
+		{name: "encrypted token", service: encrypted, token: jwe, want: signed},
+		{name: "plain token with encryption", service: encrypted, token: signed, wantErr: true},
+		{name: "other key management algorithm", service: encrypted, token: otherAlgorithm, wantErr: true},
+		{name: "encrypted for another key", service: encrypted, token: otherKey, wantErr: true},
+		{name: "malformed JWE", service: encrypted, token: "a.b.c.d.e", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.service.DecryptToken(test.token)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("DecryptToken() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestJWTAuthServiceEncryptTokenWithoutKey(t *testing.T) {
+	if _, err := newTestJWTAuthService("", "").EncryptToken("token"); err == nil {
+		t.Fatal("expected an error without an encryption key")
+	}
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"boilerplate-api/constants"
+	"boilerplate-api/models"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// AccessTokenLifetime -> lifetime of issued access tokens
+const AccessTokenLifetime = time.Hour
+
+// defaultTokenClaims -> claims mapped when JWT_CLAIMS is not set
+const defaultTokenClaims = "role,permissions,tenant"
+
+// JWTConfirmation -> `cnf` claim binding a token to a DPoP key thumbprint
+type JWTConfirmation struct {
+	JKT string `json:"jkt"`
+}
+
+// JWTClaims -> claims carried by access tokens
+type JWTClaims struct {
+	Username      string           `json:"username,omitempty"`
+	Email         string           `json:"email,omitempty"`
+	FullName      string           `json:"full_name,omitempty"`
+	Phone         string           `json:"phone,omitempty"`
+	Role          string           `json:"role,omitempty"`
+	Permissions   []string         `json:"permissions,omitempty"`
+	EmailVerified *bool            `json:"email_verified,omitempty"`
+	PhoneVerified *bool            `json:"phone_verified,omitempty"`
+	Tenant        int64            `json:"tenant,omitempty"`
+	Confirmation  *JWTConfirmation `json:"cnf,omitempty"`
+	jwt.StandardClaims
+}
+
+// HasPermission -> checks if the token grants the permission
+func (c JWTClaims) HasPermission(permission string) bool {
+	for _, p := range c.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// claimMapping -> copies a value from the user model into the claims
+type claimMapping struct {
+	personal bool
+	apply    func(claims *JWTClaims, user models.User)
+}
+
+// userClaimMappings -> claims that can be selected through JWT_CLAIMS
+var userClaimMappings = map[string]claimMapping{
+	"username": {apply: func(c *JWTClaims, u models.User) { c.Username = u.Username }},
+	"role":     {apply: func(c *JWTClaims, u models.User) { c.Role = u.Role }},
+	"permissions": {apply: func(c *JWTClaims, u models.User) {
+		role := u.Role
+		if u.OrganizationID != nil && u.Role != constants.RoleAdmin {
+			role = u.MembershipRole(*u.OrganizationID)
+		}
+		c.Permissions = constants.RolePermissions[role]
+	}},
+	"email_verified": {apply: func(c *JWTClaims, u models.User) { c.EmailVerified = &u.EmailVerified }},
+	"phone_verified": {apply: func(c *JWTClaims, u models.User) { c.PhoneVerified = &u.PhoneVerified }},
+	"tenant": {apply: func(c *JWTClaims, u models.User) {
+		if u.OrganizationID != nil {
+			c.Tenant = *u.OrganizationID
+		}
+	}},
+	"email":     {personal: true, apply: func(c *JWTClaims, u models.User) { c.Email = u.Email }},
+	"full_name": {personal: true, apply: func(c *JWTClaims, u models.User) { c.FullName = u.FullName }},
+	"phone":     {personal: true, apply: func(c *JWTClaims, u models.User) { c.Phone = u.Phone }},
+}
+
+// parseClaimMappings -> resolves the comma separated claim names
+// personal claims are refused unless the tokens are encrypted
+func parseClaimMappings(names string, encrypted bool) ([]claimMapping, error) {
+	if strings.TrimSpace(names) == "" {
+		names = defaultTokenClaims
+	}
+	var mappings []claimMapping
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		mapping, ok := userClaimMappings[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown claim %q", name)
+		}
+		if mapping.personal && !encrypted {
+			return nil, fmt.Errorf("claim %q carries personal data and requires JWT_ENCRYPTION_ENABLED=true", name)
+		}
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+// BuildClaims -> creates access token claims for the user
+func (m JWTAuthService) BuildClaims(user models.User) JWTClaims {
+	claims := JWTClaims{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: time.Now().Add(AccessTokenLifetime).Unix(),
+			IssuedAt:  time.Now().Unix(),
+			Subject:   fmt.Sprintf("%v", user.ID),
+		},
+	}
+	for _, mapping := range m.claimMappings {
+		mapping.apply(&claims, user)
+	}
+	return claims
+}
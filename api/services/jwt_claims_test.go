@@ -0,0 +1,164 @@
+package services
+
+import (
+	"boilerplate-api/constants"
+	"boilerplate-api/models"
+	"reflect"
+	"testing"
+)
+
+func TestParseClaimMappings(t *testing.T) {
+	tests := []struct {
+		name      string
+		names     string
+		encrypted bool
+		wantCount int
+		wantErr   bool
+	}{
+		{name: "default claims", names: "", wantCount: 3},
+		{name: "blank claims use the defaults", names: "  ", wantCount: 3},
+		{name: "selected claims with spaces", names: "role, username ,tenant", wantCount: 3},
+		{name: "unknown claim", names: "role,unknown", wantErr: true},
+		{name: "personal claim without encryption", names: "role,email", wantErr: true},
+		{name: "personal claims with encryption", names: "email,full_name,phone", encrypted: true, wantCount: 3},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mappings, err := parseClaimMappings(test.names, test.encrypted)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(mappings) != test.wantCount {
+				t.Errorf("got %d mappings, want %d", len(mappings), test.wantCount)
+			}
+		})
+	}
+}
+
+func TestJWTAuthServiceBuildClaims(t *testing.T) {
+	organizationID := int64(7)
+	user := models.User{
+		Username:       "jane",
+		Email:          "jane@example.com",
+		FullName:       "Jane Doe",
+		Phone:          "+15550100",
+		Role:           constants.RoleUser,
+		EmailVerified:  true,
+		OrganizationID: &organizationID,
+		Memberships: []models.OrganizationMember{
+			{OrganizationID: 3, Role: constants.RoleClientAdmin},
+			{OrganizationID: organizationID, Role: constants.RoleClient},
+		},
+	}
+	user.ID = 42
+
+	tests := []struct {
+		name   string
+		claims string
+		user   func(user models.User) models.User
+		want   func(claims JWTClaims) JWTClaims
+	}{
+		{
+			name:   "defaults use the membership role of the current organization",
+			claims: defaultTokenClaims,
+			want: func(claims JWTClaims) JWTClaims {
+				claims.Role = constants.RoleUser
+				claims.Permissions = constants.RolePermissions[constants.RoleClient]
+				claims.Tenant = organizationID
+				return claims
+			},
+		},
+		{
+			name:   "admins keep their own permissions",
+			claims: "permissions",
+			user: func(user models.User) models.User {
+				user.Role = constants.RoleAdmin
+				return user
+			},
+			want: func(claims JWTClaims) JWTClaims {
+				claims.Permissions = constants.RolePermissions[constants.RoleAdmin]
+				return claims
+			},
+		},
+		{
+			name:   "users without an organization use their role",
+			claims: "permissions,tenant",
+			user: func(user models.User) models.User {
+				user.OrganizationID = nil
+				return user
+			},
+			want: func(claims JWTClaims) JWTClaims {
+				claims.Permissions = constants.RolePermissions[constants.RoleUser]
+				return claims
+			},
+		},
+		{
+			name:   "personal and verification claims",
+			claims: "username,email,full_name,phone,email_verified,phone_verified",
+			want: func(claims JWTClaims) JWTClaims {
+				verified, unverified := true, false
+				claims.Username = "jane"
+				claims.Email = "jane@example.com"
+				claims.FullName = "Jane Doe"
+				claims.Phone = "+15550100"
+				claims.EmailVerified = &verified
+				claims.PhoneVerified = &unverified
+				return claims
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mappings, err := parseClaimMappings(test.claims, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			service := JWTAuthService{claimMappings: mappings}
+			subject := user
+			if test.user != nil {
+				subject = test.user(user)
+			}
+
+			got := service.BuildClaims(subject)
+			if got.Subject != "42" {
+				t.Errorf("sub = %q, want 42", got.Subject)
+			}
+			if got.ExpiresAt-got.IssuedAt != int64(AccessTokenLifetime.Seconds()) {
+				t.Errorf("lifetime = %ds, want %v", got.ExpiresAt-got.IssuedAt, AccessTokenLifetime)
+			}
+			want := test.want(JWTClaims{StandardClaims: got.StandardClaims})
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("BuildClaims() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestJWTClaimsHasPermission(t *testing.T) {
+	claims := JWTClaims{Permissions: []string{constants.PermissionTodoRead, constants.PermissionTodoWrite}}
+	tests := []struct {
+		permission string
+		want       bool
+	}{
+		{permission: constants.PermissionTodoRead, want: true},
+		{permission: constants.PermissionTodoWrite, want: true},
+		{permission: constants.PermissionUserRead, want: false},
+		{permission: "", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.permission, func(t *testing.T) {
+			if got := claims.HasPermission(test.permission); got != test.want {
+				t.Errorf("HasPermission(%q) = %v, want %v", test.permission, got, test.want)
+			}
+		})
+	}
+}
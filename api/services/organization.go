@@ -0,0 +1,70 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/models"
+	stdErrors "errors"
+
+	"gorm.io/gorm"
+)
+
+// OrganizationService -> struct
+type OrganizationService struct {
+	repository repository.OrganizationRepository
+}
+
+// NewOrganizationService -> creates a new OrganizationService
+func NewOrganizationService(repository repository.OrganizationRepository) OrganizationService {
+	return OrganizationService{
+		repository: repository,
+	}
+}
+
+// WithTrx -> enables repository with transaction
+func (c OrganizationService) WithTrx(trxHandle *gorm.DB) OrganizationService {
+	c.repository = c.repository.WithTrx(trxHandle)
+	return c
+}
+
+// CreateOrganization -> creates the organization with the user as its admin
+func (c OrganizationService) CreateOrganization(organization *models.Organization, user models.User) error {
+	organization.CreatedByID = user.ID
+	if err := c.repository.Create(organization); err != nil {
+		return err
+	}
+	membership := models.OrganizationMember{
+		OrganizationID: organization.ID,
+		UserID:         user.ID,
+		Role:           constants.RoleClientAdmin,
+	}
+	if err := c.repository.CreateMembership(&membership); err != nil {
+		return err
+	}
+	if user.OrganizationID == nil {
+		return c.repository.SetCurrentOrganization(user.ID, organization.ID)
+	}
+	return nil
+}
+
+// GetUserOrganizations -> organizations the user is a member of
+func (c OrganizationService) GetUserOrganizations(userID int64) ([]models.OrganizationMember, error) {
+	return c.repository.GetUserOrganizations(userID)
+}
+
+// ActingRole -> role of the user acting in the organization, the membership role unless the user is a platform admin
+// fails when the user is not a member, so removed members lose access with their next request
+func (c OrganizationService) ActingRole(organizationID int64, user models.User) (string, error) {
+	if organizationID == 0 || user.Role == constants.RoleAdmin {
+		return user.Role, nil
+	}
+	membership, err := c.repository.GetMembership(organizationID, user.ID)
+	if stdErrors.Is(err, gorm.ErrRecordNotFound) {
+		return "", errors.Forbidden.New("user is not a member of the organization")
+	}
+	if err != nil {
+		return "", errors.InternalError.Wrap(err, "failed to get organization membership")
+	}
+	return membership.Role, nil
+}
@@ -11,5 +11,20 @@ var Module = fx.Options(
 	fx.Provide(NewGmailService),
 	fx.Provide(NewS3BucketService),
 	fx.Provide(NewJWTAuthService),
+	fx.Provide(NewDPoPService),
 	fx.Provide(NewTodoService),
+	fx.Provide(NewTodoListService),
+	fx.Provide(NewTodoNotificationService),
+	fx.Provide(NewBulkService),
+	fx.Provide(NewUserImportService),
+	fx.Provide(NewTrashService),
+	fx.Provide(NewTrashRetentionJob),
+	fx.Provide(NewIdempotencyService),
+	fx.Provide(NewIdempotencyCleanupJob),
+	fx.Provide(NewOrganizationService),
+	fx.Provide(NewInvitationService),
+	fx.Provide(NewBlogService),
+	fx.Provide(NewCategoryService),
+	fx.Provide(NewCommentService),
+	fx.Provide(NewBlogScheduler),
 )
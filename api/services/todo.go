@@ -2,30 +2,324 @@ package services
 
 import (
 	"boilerplate-api/api/repository"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
 	"boilerplate-api/models"
 	"boilerplate-api/utils"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// TodoMaxTags -> most tags a todo can have
+	TodoMaxTags = 20
+
+	// TodoTagMaxLength -> longest tag name
+	TodoTagMaxLength = 64
+
+	// TodoRRuleMaxLength -> longest recurrence rule
+	TodoRRuleMaxLength = 500
+
+	// TodoOccurrencesDefault, TodoOccurrencesMax -> upcoming occurrences listed by default and at most
+	TodoOccurrencesDefault = 10
+	TodoOccurrencesMax     = 100
 )
 
 // TodoService -> struct
 type TodoService struct {
-	repository repository.TodoRepository
+	repository      repository.TodoRepository
+	listRepository  repository.TodoListRepository
+	shareRepository repository.TodoShareRepository
+	userService     UserService
+	notifier        TodoNotificationService
+	viewerID        int64
+	viewerRole      string
 }
 
 // NewTodoService  -> creates a new Todoservice
-func NewTodoService(repository repository.TodoRepository) TodoService {
+func NewTodoService(
+	repository repository.TodoRepository,
+	listRepository repository.TodoListRepository,
+	shareRepository repository.TodoShareRepository,
+	userService UserService,
+	notifier TodoNotificationService,
+) TodoService {
 	return TodoService{
-		repository: repository,
+		repository:      repository,
+		listRepository:  listRepository,
+		shareRepository: shareRepository,
+		userService:     userService,
+		notifier:        notifier,
+	}
+}
+
+// WithTrx -> enables repository with transaction
+func (c TodoService) WithTrx(trxHandle *gorm.DB) TodoService {
+	c.repository = c.repository.WithTrx(trxHandle)
+	c.listRepository = c.listRepository.WithTrx(trxHandle)
+	c.shareRepository = c.shareRepository.WithTrx(trxHandle)
+	c.userService = c.userService.WithTrx(trxHandle)
+	return c
+}
+
+// WithTenant -> limits the service to the organization
+func (c TodoService) WithTenant(tenantID int64) TodoService {
+	c.repository = c.repository.WithTenant(tenantID)
+	c.listRepository = c.listRepository.WithTenant(tenantID)
+	c.userService = c.userService.WithTenant(tenantID)
+	return c
+}
+
+// WithViewer -> acts on behalf of the user, privileged roles see every todo of the organization
+func (c TodoService) WithViewer(userID int64, role string) TodoService {
+	c.viewerID = userID
+	c.viewerRole = role
+	if utils.StringInList(role, constants.RolePrivileged) {
+		c.repository = c.repository.WithViewer(0)
+		return c
+	}
+	c.repository = c.repository.WithViewer(userID)
+	return c
+}
+
+// WithFieldset -> limits the fields of todos returned by the service
+func (c TodoService) WithFieldset(fieldset utils.Fieldset) TodoService {
+	c.repository = c.repository.WithFieldset(fieldset)
+	return c
+}
+
+// NormalizeTags -> trimmed, lower cased and unique tag names
+func NormalizeTags(names []string) []string {
+	tags := []string{}
+	seen := map[string]bool{}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		tags = append(tags, name)
+	}
+	return tags
+}
+
+// prepareTodo -> validates the list, priority, due date and tags of the todo
+// the due date is resolved in the due timezone, defaultTimezone is used when the todo has none
+func (c TodoService) prepareTodo(todo *models.Todo, defaultTimezone string) error {
+	var invalid []errors.ErrorContext
+
+	if todo.Priority != nil && (*todo.Priority < models.TodoPriorityNone || *todo.Priority > models.TodoPriorityUrgent) {
+		invalid = append(invalid, errors.ErrorContext{
+			Field:   "priority",
+			Message: fmt.Sprintf("Priority has to be between %d and %d.", models.TodoPriorityNone, models.TodoPriorityUrgent),
+		})
+	}
+
+	timezone := todo.DueTimezone
+	if timezone == "" {
+		timezone = defaultTimezone
+	}
+	loc, err := utils.LoadTimezone(timezone)
+	if err != nil {
+		invalid = append(invalid, errors.ErrorContext{Field: "due_timezone", Message: fmt.Sprintf("Unknown timezone '%s'.", timezone)})
+	} else if todo.DueDate != "" {
+		dueAt, err := utils.ParseDueDate(todo.DueDate, loc)
+		if err != nil {
+			invalid = append(invalid, errors.ErrorContext{Field: "due_date", Message: "Due date has to be a date (2006-01-02) or a date time (2006-01-02T15:04)."})
+		} else {
+			todo.DueAt = &dueAt
+		}
+	}
+	if todo.DueAt != nil {
+		dueAt := todo.DueAt.UTC()
+		todo.DueAt = &dueAt
 	}
+
+	if todo.ListID != nil {
+		_, err := c.listRepository.GetOneTodoList(*todo.ListID)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err == gorm.ErrRecordNotFound {
+			invalid = append(invalid, errors.ErrorContext{Field: "list_id", Message: "List does not exist."})
+		}
+	}
+
+	if todo.Tags != nil {
+		todo.Tags = NormalizeTags(todo.Tags)
+		if len(todo.Tags) > TodoMaxTags {
+			invalid = append(invalid, errors.ErrorContext{Field: "tags", Message: fmt.Sprintf("A todo can have at most %d tags.", TodoMaxTags)})
+		}
+		for _, tag := range todo.Tags {
+			if len([]rune(tag)) > TodoTagMaxLength {
+				invalid = append(invalid, errors.ErrorContext{Field: "tags", Message: fmt.Sprintf("Tag '%s' is longer than %d characters.", tag, TodoTagMaxLength)})
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		err := errors.BadRequest.New("invalid todo")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return errors.AddErrorContextBlock(err, invalid)
+	}
+	return nil
+}
+
+// prepareRecurrence -> validates the rule against the due date it starts from
+// setting the rule, the due date or the timezone restarts the series from the due date
+func (c TodoService) prepareRecurrence(todo *models.Todo, existing models.Todo) error {
+	if todo.RRule == nil && todo.DueAt == nil && todo.DueTimezone == "" {
+		return nil
+	}
+	if todo.RRule != nil {
+		rule := utils.NormalizeRecurrence(*todo.RRule)
+		todo.RRule = &rule
+	}
+	current := mergeRecurrence(existing, *todo)
+	if !current.IsRecurring() {
+		return nil
+	}
+
+	invalidRule := func(message string) error {
+		err := errors.BadRequest.New("invalid recurrence")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return errors.AddErrorContext(err, "rrule", message)
+	}
+	if len(*current.RRule) > TodoRRuleMaxLength {
+		return invalidRule(fmt.Sprintf("Recurrence rule is longer than %d characters.", TodoRRuleMaxLength))
+	}
+	if current.DueAt == nil {
+		return invalidRule("Recurring todos need a due date.")
+	}
+	loc, err := utils.LoadTimezone(current.DueTimezone)
+	if err != nil {
+		return invalidRule(fmt.Sprintf("Unknown timezone '%s'.", current.DueTimezone))
+	}
+	if _, err := utils.ParseRecurrence(*current.RRule, *current.DueAt, loc); err != nil {
+		return invalidRule(fmt.Sprintf("Invalid recurrence rule: %s.", err.Error()))
+	}
+	todo.RecurrenceStart = current.DueAt
+	return nil
+}
+
+// mergeRecurrence -> recurrence of the todo once the update is applied
+func mergeRecurrence(existing models.Todo, update models.Todo) models.Todo {
+	current := existing
+	if update.RRule != nil {
+		current.RRule = update.RRule
+	}
+	if update.DueAt != nil {
+		current.DueAt = update.DueAt
+	}
+	if update.DueTimezone != "" {
+		current.DueTimezone = update.DueTimezone
+	}
+	if update.RecurrenceStart != nil {
+		current.RecurrenceStart = update.RecurrenceStart
+	}
+	if current.RecurrenceStart == nil {
+		current.RecurrenceStart = current.DueAt
+	}
+	return current
+}
+
+// createNextOccurrence -> creates the occurrence following the completed one, nothing once the rule has ended
+func (c TodoService) createNextOccurrence(completed models.Todo) (*models.Todo, error) {
+	if completed.DueAt == nil || completed.RecurrenceStart == nil {
+		return nil, nil
+	}
+	loc, err := utils.LoadTimezone(completed.DueTimezone)
+	if err != nil {
+		return nil, err
+	}
+	dueAt, ok, err := utils.NextOccurrence(*completed.RRule, *completed.RecurrenceStart, *completed.DueAt, loc)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	isCompleted := false
+	recurrenceID := completed.ID
+	if completed.RecurrenceID != nil {
+		recurrenceID = *completed.RecurrenceID
+	}
+	next, err := c.repository.Create(models.Todo{
+		Task:            completed.Task,
+		IsCompleted:     &isCompleted,
+		ListID:          completed.ListID,
+		Priority:        completed.Priority,
+		DueAt:           &dueAt,
+		DueTimezone:     completed.DueTimezone,
+		RRule:           completed.RRule,
+		RecurrenceStart: completed.RecurrenceStart,
+		RecurrenceID:    &recurrenceID,
+		OrganizationID:  completed.OrganizationID,
+		OwnerID:         completed.OwnerID,
+		AssigneeID:      completed.AssigneeID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.shareRepository.CopyCollaborators(completed.ID, next.ID); err != nil {
+		return nil, err
+	}
+	next.Tags = completed.Tags
+	if err := c.repository.SetTags(next.ID, next.Tags); err != nil {
+		return nil, err
+	}
+	return &next, nil
 }
 
 // CreateTodo -> call to create the Todo
+// the viewer owns the todo, the assignee is notified
 func (c TodoService) CreateTodo(todo models.Todo) (models.Todo, error) {
-	return c.repository.Create(todo)
+	todo.RecurrenceStart = nil
+	todo.RecurrenceID = nil
+	todo.OwnerID = nil
+	if c.viewerID != 0 {
+		todo.OwnerID = &c.viewerID
+	}
+	if err := c.prepareTodo(&todo, ""); err != nil {
+		return todo, err
+	}
+	var assignee *models.User
+	if todo.AssigneeID != nil {
+		user, err := c.getAssignee(*todo.AssigneeID)
+		if err != nil {
+			return todo, err
+		}
+		assignee = user
+	}
+	if err := c.prepareRecurrence(&todo, models.Todo{}); err != nil {
+		return todo, err
+	}
+	if todo.RRule != nil && *todo.RRule == "" {
+		todo.RRule = nil
+	}
+	if todo.Priority == nil {
+		priority := models.TodoPriorityNone
+		todo.Priority = &priority
+	}
+	todo, err := c.repository.Create(todo)
+	if err != nil {
+		return todo, err
+	}
+	if err := c.repository.SetTags(todo.ID, todo.Tags); err != nil {
+		return todo, err
+	}
+	if todo.Tags == nil {
+		todo.Tags = []string{}
+	}
+	todo.SetDueAtLocal()
+	c.notifyAssigned(todo, assignee)
+	return todo, nil
 }
 
 // GetAllTodo -> call to create the Todo
-func (c TodoService) GetAllTodo(pagination utils.Pagination) ([]models.Todo, int64, error) {
-	return c.repository.GetAllTodo(pagination)
+func (c TodoService) GetAllTodo(pagination utils.Pagination, filter repository.TodoFilter) ([]models.Todo, utils.PageInfo, error) {
+	return c.repository.GetAllTodo(pagination, filter)
 }
 
 // GetOneTodo -> Get One Todo By Id
@@ -33,13 +327,173 @@ func (c TodoService) GetOneTodo(ID int64) (models.Todo, error) {
 	return c.repository.GetOneTodo(ID)
 }
 
-// UpdateOneTodo -> Update One Todo By Id
-func (c TodoService) UpdateOneTodo(todo models.Todo) error {
-	return c.repository.UpdateOneTodo(todo)
+// GetAllTags -> tags used in the organization
+func (c TodoService) GetAllTags() ([]models.Tag, error) {
+	return c.repository.GetAllTags()
 }
 
-// DeleteOneTodo -> Delete One Todo By Id
-func (c TodoService) DeleteOneTodo(ID int64) error {
-	return c.repository.DeleteOneTodo(ID)
+// UpdateOneTodo -> Update One Todo By Id, tags are only replaced when given
+// completing an occurrence of a recurring todo creates the next occurrence, which is returned
+// owner and assignee are changed through AssignTodo only
+// todo.Version is the version the change was made on (0 for any), a todo changed meanwhile is not updated
+func (c TodoService) UpdateOneTodo(todo models.Todo) (*models.Todo, error) {
+	existing, err := c.getWithPermission(todo.ID, models.TodoPermissionEdit)
+	if err != nil {
+		return nil, err
+	}
+	version := todo.Version
+	todo.Version = 0
+	todo.RecurrenceStart = nil
+	todo.RecurrenceID = nil
+	todo.OwnerID = nil
+	todo.AssigneeID = nil
+	if err := c.prepareTodo(&todo, existing.DueTimezone); err != nil {
+		return nil, err
+	}
+	if err := c.prepareRecurrence(&todo, existing); err != nil {
+		return nil, err
+	}
+	if err := c.repository.BumpVersion(todo.ID, version); err != nil {
+		return nil, err
+	}
+
+	var next *models.Todo
+	current := mergeRecurrence(existing, todo)
+	if todo.IsDone() && !existing.IsDone() && current.IsRecurring() {
+		completed, err := c.repository.CompleteRecurring(todo.ID)
+		if err != nil {
+			return nil, err
+		}
+		if completed {
+			current.Task = existing.Task
+			if todo.Task != "" {
+				current.Task = todo.Task
+			}
+			current.ListID = existing.ListID
+			if todo.ListID != nil {
+				current.ListID = todo.ListID
+			}
+			current.Priority = existing.Priority
+			if todo.Priority != nil {
+				current.Priority = todo.Priority
+			}
+			current.Tags = existing.Tags
+			if todo.Tags != nil {
+				current.Tags = todo.Tags
+			}
+			if next, err = c.createNextOccurrence(current); err != nil {
+				return nil, err
+			}
+		}
+		// the rule moved on to the next occurrence
+		todo.RRule = nil
+	}
+
+	if err := c.repository.UpdateOneTodo(todo); err != nil {
+		return nil, err
+	}
+	if todo.Tags != nil {
+		if err := c.repository.SetTags(todo.ID, todo.Tags); err != nil {
+			return nil, err
+		}
+	}
+	return next, nil
+}
+
+// TodoClearableColumns -> nullable fields of a todo PATCH can clear and their columns
+// priority is not nullable, a PATCH resets it to TodoPriorityNone instead
+var TodoClearableColumns = map[string]string{
+	"list_id": "ListID",
+	"due_at":  "DueAt",
+}
+
+// PatchOneTodo -> UpdateOneTodo that also clears the given fields (list_id, due_at)
+// a recurring todo keeps needing a due date
+func (c TodoService) PatchOneTodo(todo models.Todo, clear []string) (*models.Todo, error) {
+	columns := make([]string, 0, len(clear))
+	for _, field := range clear {
+		column, ok := TodoClearableColumns[field]
+		if !ok {
+			err := errors.BadRequest.Newf("field %s can not be cleared", field)
+			err = errors.SetCustomMessage(err, "Invalid input information")
+			return nil, errors.AddErrorContext(err, field, fmt.Sprintf("Field '%s' can not be empty.", field))
+		}
+		columns = append(columns, column)
+	}
+	next, err := c.UpdateOneTodo(todo)
+	if err != nil || len(columns) == 0 {
+		return next, err
+	}
+	if err := c.repository.ClearColumns(todo.ID, columns); err != nil {
+		return nil, err
+	}
+	current, err := c.repository.GetOneTodo(todo.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.IsRecurring() && current.DueAt == nil {
+		err := errors.BadRequest.New("invalid recurrence")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return nil, errors.AddErrorContext(err, "due_at", "Recurring todos need a due date.")
+	}
+	return next, nil
+}
+
+// GetOccurrences -> upcoming occurrences of a recurring todo, the current one included
+func (c TodoService) GetOccurrences(ID int64, limit int) ([]models.TodoOccurrence, error) {
+	todo, err := c.repository.GetOneTodo(ID)
+	if err == gorm.ErrRecordNotFound {
+		return nil, errors.NotFound.Wrap(err, "todo not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !todo.IsRecurring() || todo.DueAt == nil {
+		return []models.TodoOccurrence{}, nil
+	}
+	start := todo.RecurrenceStart
+	if start == nil {
+		start = todo.DueAt
+	}
+	return c.PreviewOccurrences(*todo.RRule, *start, *todo.DueAt, todo.DueTimezone, limit)
+}
+
+// PreviewOccurrences -> occurrences of the rule starting at dtstart, from the given time on
+func (c TodoService) PreviewOccurrences(rule string, dtstart time.Time, from time.Time, timezone string, limit int) ([]models.TodoOccurrence, error) {
+	if limit <= 0 {
+		limit = TodoOccurrencesDefault
+	}
+	if limit > TodoOccurrencesMax {
+		limit = TodoOccurrencesMax
+	}
+	loc, err := utils.LoadTimezone(timezone)
+	if err != nil {
+		err := errors.BadRequest.Wrap(err, "unknown timezone")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return nil, errors.AddErrorContext(err, "timezone", fmt.Sprintf("Unknown timezone '%s'.", timezone))
+	}
+	dates, ruleErr := utils.Occurrences(rule, dtstart, from, loc, limit)
+	if ruleErr != nil {
+		err := errors.BadRequest.Wrap(ruleErr, "invalid recurrence")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return nil, errors.AddErrorContext(err, "rrule", fmt.Sprintf("Invalid recurrence rule: %s.", ruleErr.Error()))
+	}
+	occurrences := make([]models.TodoOccurrence, 0, len(dates))
+	for _, date := range dates {
+		occurrences = append(occurrences, models.TodoOccurrence{
+			DueAt:      date,
+			DueAtLocal: date.In(loc).Format(time.RFC3339),
+		})
+	}
+	return occurrences, nil
+}
+
+// DeleteOneTodo -> Delete One Todo By Id, only its owner can delete it
+// version is the version the client has (0 for any), a todo changed meanwhile is not deleted
+func (c TodoService) DeleteOneTodo(ID int64, version int64) error {
+	if _, err := c.getWithPermission(ID, models.TodoPermissionOwner); err != nil {
+		return err
+	}
+	return c.repository.DeleteOneTodo(ID, version)
 
 }
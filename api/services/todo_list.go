@@ -0,0 +1,79 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/errors"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+
+	"gorm.io/gorm"
+)
+
+// TodoListService -> struct
+type TodoListService struct {
+	repository     repository.TodoListRepository
+	todoRepository repository.TodoRepository
+}
+
+// NewTodoListService -> creates a new TodoListService
+func NewTodoListService(
+	repository repository.TodoListRepository,
+	todoRepository repository.TodoRepository,
+) TodoListService {
+	return TodoListService{
+		repository:     repository,
+		todoRepository: todoRepository,
+	}
+}
+
+// WithTrx -> enables repository with transaction
+func (c TodoListService) WithTrx(trxHandle *gorm.DB) TodoListService {
+	c.repository = c.repository.WithTrx(trxHandle)
+	c.todoRepository = c.todoRepository.WithTrx(trxHandle)
+	return c
+}
+
+// WithTenant -> limits the service to the organization
+func (c TodoListService) WithTenant(tenantID int64) TodoListService {
+	c.repository = c.repository.WithTenant(tenantID)
+	c.todoRepository = c.todoRepository.WithTenant(tenantID)
+	return c
+}
+
+// CreateTodoList -> call to create the TodoList
+func (c TodoListService) CreateTodoList(list models.TodoList) (models.TodoList, error) {
+	return c.repository.Create(list)
+}
+
+// GetAllTodoList -> call to get all the TodoList
+func (c TodoListService) GetAllTodoList(pagination utils.Pagination) ([]models.TodoList, int64, error) {
+	return c.repository.GetAllTodoList(pagination)
+}
+
+// GetOneTodoList -> Get One TodoList By Id
+func (c TodoListService) GetOneTodoList(ID int64) (models.TodoList, error) {
+	list, err := c.repository.GetOneTodoList(ID)
+	if err == gorm.ErrRecordNotFound {
+		return list, errors.NotFound.Wrap(err, "todo list not found")
+	}
+	return list, err
+}
+
+// UpdateOneTodoList -> Update One TodoList By Id
+func (c TodoListService) UpdateOneTodoList(list models.TodoList) error {
+	if _, err := c.GetOneTodoList(list.ID); err != nil {
+		return err
+	}
+	return c.repository.UpdateOneTodoList(list)
+}
+
+// DeleteOneTodoList -> Delete One TodoList By Id, its todos are kept outside of any list
+func (c TodoListService) DeleteOneTodoList(ID int64) error {
+	if _, err := c.GetOneTodoList(ID); err != nil {
+		return err
+	}
+	if err := c.todoRepository.ClearList(ID); err != nil {
+		return err
+	}
+	return c.repository.DeleteOneTodoList(ID)
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"fmt"
+)
+
+// TodoNotificationService -> tells assignees about their todos by email and sms
+type TodoNotificationService struct {
+	gmailService  GmailService
+	twilioService TwilioService
+	env           infrastructure.Env
+	logger        infrastructure.Logger
+}
+
+// NewTodoNotificationService -> creates a new TodoNotificationService
+func NewTodoNotificationService(
+	gmailService GmailService,
+	twilioService TwilioService,
+	env infrastructure.Env,
+	logger infrastructure.Logger,
+) TodoNotificationService {
+	return TodoNotificationService{
+		gmailService:  gmailService,
+		twilioService: twilioService,
+		env:           env,
+		logger:        logger,
+	}
+}
+
+// NotifyAssigned -> emails the assignee and texts verified phone numbers
+// failures are logged only, the assignment itself has already been made
+func (s TodoNotificationService) NotifyAssigned(todo models.Todo, assignee models.User, assignedBy models.User) {
+	defer utils.RecoverPanic(s.logger)()
+
+	url := fmt.Sprintf("%s/todo/%d", s.env.ClientURL, todo.ID)
+	dueAt := ""
+	if todo.DueAt != nil {
+		dueAt = utils.FormatInTimezone(*todo.DueAt, todo.DueTimezone)
+	}
+	assigner := assignedBy.FullName
+	if assigner == "" {
+		assigner = assignedBy.Username
+	}
+
+	if assignee.Email != "" {
+		_, err := s.gmailService.SendEmail(models.EmailParams{
+			To:          assignee.Email,
+			SubjectData: "A todo has been assigned to you",
+			BodyData: map[string]interface{}{
+				"AssignedBy": assigner,
+				"Task":       todo.Task,
+				"DueAt":      dueAt,
+				"URL":        url,
+			},
+			BodyTemplate: "todo_assigned_body.txt",
+			Lang:         "en",
+		})
+		if err != nil {
+			s.logger.Zap.Error("Error sending todo assignment email: ", err.Error())
+		}
+	}
+
+	if assignee.Phone == "" || !assignee.PhoneVerified || s.env.TwilioSID == "" {
+		return
+	}
+	body := fmt.Sprintf("%s assigned you a todo: %s", assigner, todo.Task)
+	if dueAt != "" {
+		body += " (due " + dueAt + ")"
+	}
+	_, twilioErr, err := s.twilioService.SendSMS(SMSInput{
+		From: s.env.TwilioSMSFrom,
+		To:   assignee.Phone,
+		Body: body,
+	})
+	if err != nil {
+		s.logger.Zap.Error("Error sending todo assignment sms: ", err.Error())
+	} else if twilioErr != nil {
+		s.logger.Zap.Error("Error sending todo assignment sms: ", twilioErr.Message)
+	}
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// todoPermissionRank -> permissions ordered from least to most
+var todoPermissionRank = map[string]int{
+	models.TodoPermissionView:  1,
+	models.TodoPermissionEdit:  2,
+	models.TodoPermissionOwner: 3,
+}
+
+// Permission -> what the viewer may do with the todo
+// owners, privileged roles and everyone on todos without owner have full access, assignees can edit
+func (c TodoService) Permission(todo models.Todo) (string, error) {
+	if c.viewerID == 0 || utils.StringInList(c.viewerRole, constants.RolePrivileged) {
+		return models.TodoPermissionOwner, nil
+	}
+	if todo.OwnerID == nil || *todo.OwnerID == c.viewerID {
+		return models.TodoPermissionOwner, nil
+	}
+	if todo.AssigneeID != nil && *todo.AssigneeID == c.viewerID {
+		return models.TodoPermissionEdit, nil
+	}
+	collaborator, err := c.shareRepository.GetCollaborator(todo.ID, c.viewerID)
+	if err == gorm.ErrRecordNotFound {
+		return "", nil
+	}
+	return collaborator.Permission, err
+}
+
+// getWithPermission -> todo the viewer can see, forbidden without the permission
+func (c TodoService) getWithPermission(ID int64, permission string) (models.Todo, error) {
+	todo, err := c.repository.GetOneTodo(ID)
+	if err == gorm.ErrRecordNotFound {
+		return todo, errors.NotFound.Wrap(err, "todo not found")
+	}
+	if err != nil {
+		return todo, err
+	}
+	granted, err := c.Permission(todo)
+	if err != nil {
+		return todo, err
+	}
+	if todoPermissionRank[granted] < todoPermissionRank[permission] {
+		err := errors.Forbidden.Newf("%s permission required on todo", permission)
+		return todo, errors.SetCustomMessage(err, "You are not allowed to change this todo")
+	}
+	return todo, nil
+}
+
+// getAssignee -> user of the organization the todo can be assigned to
+func (c TodoService) getAssignee(userID int64) (*models.User, error) {
+	user, err := c.userService.GetOneUser(userID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if err == gorm.ErrRecordNotFound {
+		err := errors.BadRequest.New("unknown assignee")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return nil, errors.AddErrorContext(err, "assignee_id", "User does not exist.")
+	}
+	return user, nil
+}
+
+// notifyAssigned -> notifies the assignee in the background unless they assigned the todo themselves
+func (c TodoService) notifyAssigned(todo models.Todo, assignee *models.User) {
+	if assignee == nil || assignee.ID == c.viewerID {
+		return
+	}
+	assignedBy := models.User{}
+	if c.viewerID != 0 {
+		if user, err := c.userService.GetOneUser(c.viewerID); err == nil {
+			assignedBy = *user
+		}
+	}
+	go c.notifier.NotifyAssigned(todo, *assignee, assignedBy)
+}
+
+// AssignTodo -> assigns the todo to the user, nil unassigns it
+func (c TodoService) AssignTodo(ID int64, assigneeID *int64) error {
+	todo, err := c.getWithPermission(ID, models.TodoPermissionEdit)
+	if err != nil {
+		return err
+	}
+	var assignee *models.User
+	if assigneeID != nil {
+		if assignee, err = c.getAssignee(*assigneeID); err != nil {
+			return err
+		}
+	}
+	if err := c.repository.SetAssignee(ID, assigneeID); err != nil {
+		return err
+	}
+	if assigneeID != nil && (todo.AssigneeID == nil || *todo.AssigneeID != *assigneeID) {
+		c.notifyAssigned(todo, assignee)
+	}
+	return nil
+}
+
+// GetCollaborators -> users the todo is shared with
+func (c TodoService) GetCollaborators(todoID int64) ([]models.TodoCollaborator, error) {
+	if _, err := c.getWithPermission(todoID, models.TodoPermissionView); err != nil {
+		return nil, err
+	}
+	return c.shareRepository.GetCollaborators(todoID)
+}
+
+// validatePermission -> permission that can be granted to collaborators and share links
+func validatePermission(permission string) error {
+	if utils.StringInList(permission, models.TodoPermissions) {
+		return nil
+	}
+	err := errors.BadRequest.Newf("unknown permission %s", permission)
+	err = errors.SetCustomMessage(err, "Invalid input information")
+	return errors.AddErrorContext(err, "permission", "Permission has to be view or edit.")
+}
+
+// SetCollaborator -> shares the todo with the user, only the owner can share
+func (c TodoService) SetCollaborator(todoID int64, userID int64, permission string) error {
+	todo, err := c.getWithPermission(todoID, models.TodoPermissionOwner)
+	if err != nil {
+		return err
+	}
+	if err := validatePermission(permission); err != nil {
+		return err
+	}
+	if _, err := c.getAssignee(userID); err != nil {
+		return errors.AddErrorContext(err, "user_id", "User does not exist.")
+	}
+	if todo.OwnerID != nil && *todo.OwnerID == userID {
+		err := errors.BadRequest.New("owner cannot be a collaborator")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return errors.AddErrorContext(err, "user_id", "The owner already has full access.")
+	}
+	return c.shareRepository.SetCollaborator(models.TodoCollaborator{
+		TodoID:     todoID,
+		UserID:     userID,
+		Permission: permission,
+	})
+}
+
+// RemoveCollaborator -> stops sharing the todo with the user, collaborators can leave by themselves
+func (c TodoService) RemoveCollaborator(todoID int64, userID int64) error {
+	permission := models.TodoPermissionOwner
+	if userID == c.viewerID {
+		permission = models.TodoPermissionView
+	}
+	if _, err := c.getWithPermission(todoID, permission); err != nil {
+		return err
+	}
+	return c.shareRepository.DeleteCollaborator(todoID, userID)
+}
+
+// CreateShare -> share link granting the permission, expiresIn of 0 never expires
+func (c TodoService) CreateShare(todoID int64, permission string, expiresIn time.Duration) (models.TodoShare, error) {
+	share := models.TodoShare{}
+	if _, err := c.getWithPermission(todoID, models.TodoPermissionOwner); err != nil {
+		return share, err
+	}
+	if err := validatePermission(permission); err != nil {
+		return share, err
+	}
+	share.TodoID = todoID
+	share.Token = utils.GenerateSecureToken(32)
+	share.Permission = permission
+	share.CreatedByID = c.viewerID
+	if expiresIn > 0 {
+		expiresAt := time.Now().Add(expiresIn)
+		share.ExpiresAt = &expiresAt
+	}
+	return share, c.shareRepository.CreateShare(&share)
+}
+
+// GetShares -> share links of the todo
+func (c TodoService) GetShares(todoID int64) ([]models.TodoShare, error) {
+	if _, err := c.getWithPermission(todoID, models.TodoPermissionOwner); err != nil {
+		return nil, err
+	}
+	return c.shareRepository.GetShares(todoID)
+}
+
+// RevokeShare -> deletes the share link, collaborators who joined through it stay
+func (c TodoService) RevokeShare(todoID int64, ID int64) error {
+	if _, err := c.getWithPermission(todoID, models.TodoPermissionOwner); err != nil {
+		return err
+	}
+	deleted, err := c.shareRepository.DeleteShare(todoID, ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.NotFound.New("share link not found")
+	}
+	return nil
+}
+
+// JoinShare -> makes the viewer a collaborator through the share link, existing rights are never lowered
+func (c TodoService) JoinShare(token string) (models.Todo, error) {
+	share, err := c.shareRepository.GetShareByToken(token)
+	if err == gorm.ErrRecordNotFound || (err == nil && share.IsExpired()) {
+		err := errors.NotFound.New("share link not found or expired")
+		return models.Todo{}, errors.SetCustomMessage(err, "This share link is invalid or has expired")
+	}
+	if err != nil {
+		return models.Todo{}, err
+	}
+	todo, err := c.repository.WithViewer(0).GetOneTodo(share.TodoID)
+	if err == gorm.ErrRecordNotFound {
+		return todo, errors.NotFound.Wrap(err, "todo not found")
+	}
+	if err != nil {
+		return todo, err
+	}
+	granted, err := c.Permission(todo)
+	if err != nil {
+		return todo, err
+	}
+	if todoPermissionRank[granted] >= todoPermissionRank[share.Permission] {
+		return todo, nil
+	}
+	return todo, c.shareRepository.SetCollaborator(models.TodoCollaborator{
+		TodoID:     todo.ID,
+		UserID:     c.viewerID,
+		Permission: share.Permission,
+	})
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"testing"
+	"time"
+)
+
+// todoShareFixture -> todo of the owner with a collaborator for each permission and an assignee
+type todoShareFixture struct {
+	db      infrastructure.Database
+	todo    models.Todo
+	users   map[string]models.User
+	service TodoService
+}
+
+func newTodoShareFixture(t *testing.T) todoShareFixture {
+	t.Helper()
+	db := newTestDatabase(t)
+	users := map[string]models.User{}
+	for _, name := range []string{"owner", "viewer", "editor", "assignee", "stranger", "admin", "invitee"} {
+		users[name] = createTestUser(t, db, name)
+	}
+	service := newTestTodoService(db)
+	todo, err := service.WithViewer(users["owner"].ID, constants.RoleUser).CreateTodo(models.Todo{Task: "milk"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// set directly, assigning through the service notifies the assignee
+	if err := db.DB.Model(&todo).Update("AssigneeID", users["assignee"].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	owner := service.WithViewer(users["owner"].ID, constants.RoleUser)
+	for name, permission := range map[string]string{"viewer": models.TodoPermissionView, "editor": models.TodoPermissionEdit} {
+		if err := owner.SetCollaborator(todo.ID, users[name].ID, permission); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return todoShareFixture{db: db, todo: todo, users: users, service: service}
+}
+
+// as -> service acting on behalf of the user
+func (f todoShareFixture) as(name string) TodoService {
+	role := constants.RoleUser
+	if name == "admin" {
+		role = constants.RoleClientAdmin
+	}
+	return f.service.WithViewer(f.users[name].ID, role)
+}
+
+func TestTodoServicePermissions(t *testing.T) {
+	tests := []struct {
+		viewer         string
+		wantPermission string
+		wantView       errors.HttpErrorType
+		wantEdit       errors.HttpErrorType
+		wantOwner      errors.HttpErrorType
+	}{
+		{viewer: "owner", wantPermission: models.TodoPermissionOwner},
+		{viewer: "admin", wantPermission: models.TodoPermissionOwner},
+		{viewer: "editor", wantPermission: models.TodoPermissionEdit, wantOwner: errors.Forbidden},
+		{viewer: "assignee", wantPermission: models.TodoPermissionEdit, wantOwner: errors.Forbidden},
+		{viewer: "viewer", wantPermission: models.TodoPermissionView, wantEdit: errors.Forbidden, wantOwner: errors.Forbidden},
+		{viewer: "stranger", wantView: errors.NotFound, wantEdit: errors.NotFound, wantOwner: errors.NotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.viewer, func(t *testing.T) {
+			fixture := newTodoShareFixture(t)
+			service := fixture.as(test.viewer)
+
+			todo, err := service.GetOneTodo(fixture.todo.ID)
+			if test.wantView == 0 {
+				if err != nil {
+					t.Fatalf("get error = %v", err)
+				}
+				if permission, _ := service.Permission(todo); permission != test.wantPermission {
+					t.Errorf("permission = %q, want %q", permission, test.wantPermission)
+				}
+			}
+
+			_, viewErr := service.GetCollaborators(fixture.todo.ID)
+			_, editErr := service.UpdateOneTodo(models.Todo{BaseModel: models.BaseModel{ID: fixture.todo.ID}, Task: "bread"})
+			shareErr := service.SetCollaborator(fixture.todo.ID, fixture.users["invitee"].ID, models.TodoPermissionView)
+			_, linkErr := service.CreateShare(fixture.todo.ID, models.TodoPermissionView, 0)
+			deleteErr := service.DeleteOneTodo(fixture.todo.ID, 0)
+
+			for action, got := range map[string]struct {
+				err  error
+				want errors.HttpErrorType
+			}{
+				"view collaborators": {viewErr, test.wantView},
+				"update":             {editErr, test.wantEdit},
+				"share":              {shareErr, test.wantOwner},
+				"create share link":  {linkErr, test.wantOwner},
+				"delete":             {deleteErr, test.wantOwner},
+			} {
+				if got.want == 0 && got.err != nil {
+					t.Errorf("%s error = %v", action, got.err)
+				}
+				if got.want != 0 && (got.err == nil || errors.GetErrorType(got.err) != got.want) {
+					t.Errorf("%s error = %v, want type %v", action, got.err, got.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoServiceRemoveCollaborator(t *testing.T) {
+	fixture := newTodoShareFixture(t)
+
+	if err := fixture.as("editor").RemoveCollaborator(fixture.todo.ID, fixture.users["viewer"].ID); errors.GetErrorType(err) != errors.Forbidden {
+		t.Errorf("removing another collaborator error = %v, want Forbidden", err)
+	}
+	if err := fixture.as("viewer").RemoveCollaborator(fixture.todo.ID, fixture.users["viewer"].ID); err != nil {
+		t.Errorf("leaving the todo error = %v", err)
+	}
+	if _, err := fixture.as("viewer").GetOneTodo(fixture.todo.ID); err == nil {
+		t.Error("former collaborator still sees the todo")
+	}
+	if err := fixture.as("owner").RemoveCollaborator(fixture.todo.ID, fixture.users["editor"].ID); err != nil {
+		t.Errorf("owner removing a collaborator error = %v", err)
+	}
+	if err := fixture.as("owner").SetCollaborator(fixture.todo.ID, fixture.users["owner"].ID, models.TodoPermissionEdit); err == nil || errors.GetErrorType(err) != errors.BadRequest {
+		t.Errorf("owner as collaborator error = %v, want BadRequest", err)
+	}
+	if err := fixture.as("owner").SetCollaborator(fixture.todo.ID, fixture.users["invitee"].ID, models.TodoPermissionOwner); err == nil || errors.GetErrorType(err) != errors.BadRequest {
+		t.Errorf("owner permission for a collaborator error = %v, want BadRequest", err)
+	}
+}
+
+func TestTodoServiceJoinShare(t *testing.T) {
+	fixture := newTodoShareFixture(t)
+	owner := fixture.as("owner")
+	viewLink, err := owner.CreateShare(fixture.todo.ID, models.TodoPermissionView, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	editLink, err := owner.CreateShare(fixture.todo.ID, models.TodoPermissionEdit, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiredLink, err := owner.CreateShare(fixture.todo.ID, models.TodoPermissionEdit, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fixture.db.DB.Model(&expiredLink).Update("ExpiresAt", time.Now().Add(-time.Minute)).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name           string
+		viewer         string
+		token          string
+		wantErr        bool
+		wantPermission string
+	}{
+		{name: "stranger joins with edit", viewer: "stranger", token: editLink.Token, wantPermission: models.TodoPermissionEdit},
+		{name: "editor keeps edit with a view link", viewer: "editor", token: viewLink.Token, wantPermission: models.TodoPermissionEdit},
+		{name: "viewer is raised to edit", viewer: "viewer", token: editLink.Token, wantPermission: models.TodoPermissionEdit},
+		{name: "expired link", viewer: "invitee", token: expiredLink.Token, wantErr: true},
+		{name: "unknown link", viewer: "invitee", token: "unknown", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			service := fixture.as(test.viewer)
+			todo, err := service.JoinShare(test.token)
+			if test.wantErr {
+				if errors.GetErrorType(err) != errors.NotFound {
+					t.Fatalf("error = %v, want NotFound", err)
+				}
+				if _, err := service.GetOneTodo(fixture.todo.ID); err == nil {
+					t.Error("todo visible without joining")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if permission, _ := service.Permission(todo); permission != test.wantPermission {
+				t.Errorf("permission = %q, want %q", permission, test.wantPermission)
+			}
+		})
+	}
+
+	if err := owner.RevokeShare(fixture.todo.ID, editLink.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fixture.as("invitee").JoinShare(editLink.Token); errors.GetErrorType(err) != errors.NotFound {
+		t.Errorf("revoked link error = %v, want NotFound", err)
+	}
+	if _, err := fixture.as("stranger").GetOneTodo(fixture.todo.ID); err != nil {
+		t.Errorf("collaborator who joined through the revoked link: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestTodoService(db infrastructure.Database) TodoService {
+	return NewTodoService(
+		repository.NewTodoRepository(db, testLogger),
+		repository.NewTodoListRepository(db, testLogger),
+		repository.NewTodoShareRepository(db, testLogger),
+		NewUserService(repository.NewUserRepository(db, testLogger)),
+		TodoNotificationService{},
+	)
+}
+
+func TestTodoServiceCreateTodo(t *testing.T) {
+	db := newTestDatabase(t)
+	owner := createTestUser(t, db, "owner")
+	list, err := repository.NewTodoListRepository(db, testLogger).Create(models.TodoList{Name: "groceries"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := newTestTodoService(db).WithViewer(owner.ID, "user")
+	priority := func(value int) *int { return &value }
+	unknownList := list.ID + 1
+	tooManyTags := make([]string, TodoMaxTags+1)
+	for i := range tooManyTags {
+		tooManyTags[i] = strings.Repeat("t", i+1)
+	}
+
+	tests := []struct {
+		name          string
+		todo          models.Todo
+		wantPriority  int
+		wantDueAt     *time.Time
+		wantTags      []string
+		wantErrFields []string
+	}{
+		{name: "defaults", todo: models.Todo{Task: "milk"}, wantTags: []string{}},
+		{name: "list, priority and tags", todo: models.Todo{Task: "milk", ListID: &list.ID, Priority: priority(models.TodoPriorityHigh), Tags: []string{" Home ", "home", "Shop", ""}}, wantPriority: models.TodoPriorityHigh, wantTags: []string{"home", "shop"}},
+		{name: "due date in the due timezone", todo: models.Todo{Task: "milk", DueDate: "2024-03-10T09:00", DueTimezone: "Europe/Berlin"}, wantDueAt: timePointer(time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)), wantTags: []string{}},
+		{name: "due date without time is due at the end of the day", todo: models.Todo{Task: "milk", DueDate: "2024-03-10"}, wantDueAt: timePointer(time.Date(2024, 3, 10, 23, 59, 59, 0, time.UTC)), wantTags: []string{}},
+		{name: "invalid fields", todo: models.Todo{Task: "milk", ListID: &unknownList, Priority: priority(models.TodoPriorityUrgent + 1), DueDate: "tomorrow"}, wantErrFields: []string{"priority", "due_date", "list_id"}},
+		{name: "unknown timezone", todo: models.Todo{Task: "milk", DueTimezone: "Mars/Olympus"}, wantErrFields: []string{"due_timezone"}},
+		{name: "too many tags", todo: models.Todo{Task: "milk", Tags: tooManyTags}, wantErrFields: []string{"tags"}},
+		{name: "tag too long", todo: models.Todo{Task: "milk", Tags: []string{strings.Repeat("t", TodoTagMaxLength+1)}}, wantErrFields: []string{"tags"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			created, err := service.CreateTodo(test.todo)
+			if test.wantErrFields != nil {
+				if errors.GetErrorType(err) != errors.BadRequest {
+					t.Fatalf("error = %v, want BadRequest", err)
+				}
+				var fields []string
+				for _, context := range errors.GetErrorContext(err) {
+					fields = append(fields, context.Field)
+				}
+				if !reflect.DeepEqual(fields, test.wantErrFields) {
+					t.Errorf("invalid fields = %v, want %v", fields, test.wantErrFields)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			stored, err := service.GetOneTodo(created.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.OwnerID == nil || *stored.OwnerID != owner.ID {
+				t.Errorf("owner = %v, want %d", stored.OwnerID, owner.ID)
+			}
+			if stored.Priority == nil || *stored.Priority != test.wantPriority {
+				t.Errorf("priority = %v, want %d", stored.Priority, test.wantPriority)
+			}
+			if (stored.DueAt == nil) != (test.wantDueAt == nil) || (stored.DueAt != nil && !stored.DueAt.Equal(*test.wantDueAt)) {
+				t.Errorf("due at = %v, want %v", stored.DueAt, test.wantDueAt)
+			}
+			if !reflect.DeepEqual(stored.Tags, test.wantTags) {
+				t.Errorf("tags = %v, want %v", stored.Tags, test.wantTags)
+			}
+		})
+	}
+}
+
+func TestTodoListServiceDeleteKeepsTodos(t *testing.T) {
+	db := newTestDatabase(t)
+	owner := createTestUser(t, db, "owner")
+	listRepository := repository.NewTodoListRepository(db, testLogger)
+	list, err := listRepository.Create(models.TodoList{Name: "groceries"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	service := newTestTodoService(db).WithViewer(owner.ID, "user")
+	todo, err := service.CreateTodo(models.Todo{Task: "milk", ListID: &list.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewTodoListService(listRepository, repository.NewTodoRepository(db, testLogger)).DeleteOneTodoList(list.ID); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := service.GetOneTodo(todo.ID)
+	if err != nil {
+		t.Fatalf("todo of the deleted list: %v", err)
+	}
+	if stored.ListID != nil {
+		t.Errorf("list = %d, want the todo outside of any list", *stored.ListID)
+	}
+}
+
+func timePointer(value time.Time) *time.Time {
+	return &value
+}
+
+func TestTodoServicePrepareRecurrence(t *testing.T) {
+	rule := func(value string) *string { return &value }
+	dueAt := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	laterDueAt := dueAt.AddDate(0, 0, 7)
+	recurring := models.Todo{RRule: rule("FREQ=DAILY"), DueAt: &dueAt, RecurrenceStart: &dueAt}
+
+	tests := []struct {
+		name          string
+		existing      models.Todo
+		update        models.Todo
+		wantRule      *string
+		wantStart     *time.Time
+		wantErr       bool
+		wantErrSubstr string
+	}{
+		{name: "no recurrence fields", update: models.Todo{Task: "milk"}},
+		{name: "due date without rule", update: models.Todo{DueAt: &dueAt}},
+		{name: "new rule is normalized and starts at the due date", update: models.Todo{RRule: rule(" rrule:freq=weekly;byday=mo "), DueAt: &dueAt}, wantRule: rule("FREQ=WEEKLY;BYDAY=MO"), wantStart: &dueAt},
+		{name: "rule added to a todo with a due date", existing: models.Todo{DueAt: &dueAt}, update: models.Todo{RRule: rule("FREQ=DAILY")}, wantRule: rule("FREQ=DAILY"), wantStart: &dueAt},
+		{name: "moving the due date restarts the rule", existing: recurring, update: models.Todo{DueAt: &laterDueAt}, wantStart: &laterDueAt},
+		{name: "empty rule stops recurring", existing: recurring, update: models.Todo{RRule: rule("")}, wantRule: rule("")},
+		{name: "rule without due date", update: models.Todo{RRule: rule("FREQ=DAILY")}, wantErr: true, wantErrSubstr: "need a due date"},
+		{name: "invalid rule", update: models.Todo{RRule: rule("FREQ=MINUTELY"), DueAt: &dueAt}, wantErr: true, wantErrSubstr: "Invalid recurrence rule"},
+		{name: "unknown timezone", existing: recurring, update: models.Todo{DueTimezone: "Mars/Olympus"}, wantErr: true, wantErrSubstr: "Unknown timezone"},
+		{name: "rule too long", update: models.Todo{RRule: rule("FREQ=DAILY;" + strings.Repeat("BYHOUR=1;", TodoRRuleMaxLength/9+1)), DueAt: &dueAt}, wantErr: true, wantErrSubstr: "longer than"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			todo := test.update
+			err := TodoService{}.prepareRecurrence(&todo, test.existing)
+			if test.wantErr {
+				if errors.GetErrorType(err) != errors.BadRequest {
+					t.Fatalf("error = %v, want BadRequest", err)
+				}
+				contexts := errors.GetErrorContext(err)
+				if len(contexts) != 1 || contexts[0].Field != "rrule" || !strings.Contains(contexts[0].Message, test.wantErrSubstr) {
+					t.Errorf("error context = %+v, want rrule containing %q", contexts, test.wantErrSubstr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (todo.RRule == nil) != (test.wantRule == nil) || (todo.RRule != nil && *todo.RRule != *test.wantRule) {
+				t.Errorf("rrule = %v, want %v", todo.RRule, test.wantRule)
+			}
+			if (todo.RecurrenceStart == nil) != (test.wantStart == nil) || (todo.RecurrenceStart != nil && !todo.RecurrenceStart.Equal(*test.wantStart)) {
+				t.Errorf("recurrence start = %v, want %v", todo.RecurrenceStart, test.wantStart)
+			}
+		})
+	}
+}
+
+func TestMergeRecurrence(t *testing.T) {
+	rule := func(value string) *string { return &value }
+	dueAt := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	start := dueAt.AddDate(0, 0, -7)
+	laterDueAt := dueAt.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name     string
+		existing models.Todo
+		update   models.Todo
+		want     models.Todo
+	}{
+		{
+			name:     "existing recurrence is kept",
+			existing: models.Todo{RRule: rule("FREQ=DAILY"), DueAt: &dueAt, DueTimezone: "Europe/Berlin", RecurrenceStart: &start},
+			want:     models.Todo{RRule: rule("FREQ=DAILY"), DueAt: &dueAt, DueTimezone: "Europe/Berlin", RecurrenceStart: &start},
+		},
+		{
+			name:     "update overrides the fields it sets",
+			existing: models.Todo{RRule: rule("FREQ=DAILY"), DueAt: &dueAt, DueTimezone: "Europe/Berlin", RecurrenceStart: &start},
+			update:   models.Todo{RRule: rule("FREQ=WEEKLY"), DueAt: &laterDueAt, DueTimezone: "UTC"},
+			want:     models.Todo{RRule: rule("FREQ=WEEKLY"), DueAt: &laterDueAt, DueTimezone: "UTC", RecurrenceStart: &start},
+		},
+		{
+			name:   "recurrence starts at the due date by default",
+			update: models.Todo{RRule: rule("FREQ=DAILY"), DueAt: &dueAt},
+			want:   models.Todo{RRule: rule("FREQ=DAILY"), DueAt: &dueAt, RecurrenceStart: &dueAt},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := mergeRecurrence(test.existing, test.update)
+			if *got.RRule != *test.want.RRule || !got.DueAt.Equal(*test.want.DueAt) || got.DueTimezone != test.want.DueTimezone || !got.RecurrenceStart.Equal(*test.want.RecurrenceStart) {
+				t.Errorf("mergeRecurrence() = rrule %s, due %v %s, start %v, want rrule %s, due %v %s, start %v",
+					*got.RRule, got.DueAt, got.DueTimezone, got.RecurrenceStart,
+					*test.want.RRule, test.want.DueAt, test.want.DueTimezone, test.want.RecurrenceStart)
+			}
+		})
+	}
+}
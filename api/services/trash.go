@@ -0,0 +1,161 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"time"
+
+	"firebase.google.com/go/auth"
+	"gorm.io/gorm"
+)
+
+// trashPurgeBatchSize -> expired users purged at once by the retention job
+const trashPurgeBatchSize = 100
+
+// TrashService -> lists, restores and purges soft deleted users and todos
+type TrashService struct {
+	userRepository  repository.UserRepository
+	todoRepository  repository.TodoRepository
+	firebaseService FirebaseService
+	logger          infrastructure.Logger
+}
+
+// NewTrashService -> creates a new TrashService
+func NewTrashService(
+	userRepository repository.UserRepository,
+	todoRepository repository.TodoRepository,
+	firebaseService FirebaseService,
+	logger infrastructure.Logger,
+) TrashService {
+	return TrashService{
+		userRepository:  userRepository,
+		todoRepository:  todoRepository,
+		firebaseService: firebaseService,
+		logger:          logger,
+	}
+}
+
+// WithTrx -> enables repository with transaction
+func (c TrashService) WithTrx(trxHandle *gorm.DB) TrashService {
+	c.userRepository = c.userRepository.WithTrx(trxHandle)
+	c.todoRepository = c.todoRepository.WithTrx(trxHandle)
+	return c
+}
+
+// WithTenant -> limits the service to the organization
+func (c TrashService) WithTenant(tenantID int64) TrashService {
+	c.userRepository = c.userRepository.WithTenant(tenantID)
+	c.todoRepository = c.todoRepository.WithTenant(tenantID)
+	return c
+}
+
+// GetDeletedUsers -> Get the soft deleted users
+func (c TrashService) GetDeletedUsers(pagination utils.Pagination) ([]models.User, utils.PageInfo, error) {
+	return c.userRepository.GetDeletedUsers(pagination)
+}
+
+// getDeletedUser -> soft deleted user, not found otherwise
+func (c TrashService) getDeletedUser(ID int64) (models.User, error) {
+	user, err := c.userRepository.GetDeletedUser(ID)
+	if err == gorm.ErrRecordNotFound {
+		return user, errors.NotFound.Wrap(err, "deleted user not found")
+	}
+	return user, err
+}
+
+// RestoreUser -> restores the user and enables the firebase account again
+func (c TrashService) RestoreUser(ID int64) (models.User, error) {
+	user, err := c.getDeletedUser(ID)
+	if err != nil {
+		return user, err
+	}
+	if err := c.userRepository.RestoreUser(ID); err != nil {
+		return user, err
+	}
+	if user.FirebaseUID != "" {
+		if err := c.firebaseService.DisableUser(user.FirebaseUID, false); err != nil {
+			return user, errors.InternalError.Wrap(err, "failed to enable firebase user")
+		}
+	}
+	user.DeletedAt = gorm.DeletedAt{}
+	return user, nil
+}
+
+// PurgeUser -> permanently deletes the soft deleted user along with the firebase account
+func (c TrashService) PurgeUser(ID int64) error {
+	user, err := c.getDeletedUser(ID)
+	if err != nil {
+		return err
+	}
+	return c.purgeUser(user)
+}
+
+// purgeUser -> the firebase account goes first so a failure leaves the user to be purged again later
+// todos the user owned stay with the organization and comments stay without author
+func (c TrashService) purgeUser(user models.User) error {
+	if user.FirebaseUID != "" {
+		if err := c.firebaseService.DeleteUser(user.FirebaseUID); err != nil && !auth.IsUserNotFound(err) {
+			return errors.InternalError.Wrap(err, "failed to delete firebase user")
+		}
+	}
+	return c.userRepository.PurgeUser(user.ID)
+}
+
+// GetDeletedTodos -> Get the soft deleted todos
+func (c TrashService) GetDeletedTodos(pagination utils.Pagination) ([]models.Todo, utils.PageInfo, error) {
+	return c.todoRepository.GetDeletedTodos(pagination)
+}
+
+// RestoreTodo -> restores the soft deleted todo
+func (c TrashService) RestoreTodo(ID int64) error {
+	restored, err := c.todoRepository.RestoreTodo(ID)
+	if err != nil {
+		return err
+	}
+	if !restored {
+		return errors.NotFound.New("deleted todo not found")
+	}
+	return nil
+}
+
+// PurgeTodo -> permanently deletes the soft deleted todo
+func (c TrashService) PurgeTodo(ID int64) error {
+	purged, err := c.todoRepository.PurgeTodo(ID)
+	if err != nil {
+		return err
+	}
+	if !purged {
+		return errors.NotFound.New("deleted todo not found")
+	}
+	return nil
+}
+
+// PurgeExpired -> permanently deletes users and todos of every organization deleted before the time
+// users are purged one by one so a failing firebase account does not keep the others
+func (c TrashService) PurgeExpired(before time.Time) (int, int64, error) {
+	purgedUsers := 0
+	for {
+		users, err := c.userRepository.GetExpiredUsers(before, trashPurgeBatchSize)
+		if err != nil {
+			return purgedUsers, 0, err
+		}
+		failed := 0
+		for _, user := range users {
+			if err := c.purgeUser(user); err != nil {
+				c.logger.Zap.Error("Error [PurgeExpired] purging user ", user.ID, ": ", err.Error())
+				failed++
+				continue
+			}
+			purgedUsers++
+		}
+		// stop when nothing is left or when every user of the batch keeps failing
+		if len(users) < trashPurgeBatchSize || failed == len(users) {
+			break
+		}
+	}
+	purgedTodos, err := c.todoRepository.PurgeExpiredTodos(before)
+	return purgedUsers, purgedTodos, err
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"boilerplate-api/infrastructure"
+	"strconv"
+	"time"
+)
+
+const (
+	// TrashRetentionInterval -> how often expired soft deleted records are purged
+	TrashRetentionInterval = time.Hour
+
+	// TrashRetentionDefaultDays -> days soft deleted records are kept when TrashRetentionDays is not set
+	TrashRetentionDefaultDays = 30
+)
+
+// TrashRetentionJob -> permanently deletes soft deleted users and todos after the retention period
+type TrashRetentionJob struct {
+	logger    infrastructure.Logger
+	service   TrashService
+	retention time.Duration
+	stop      chan struct{}
+}
+
+// NewTrashRetentionJob -> creates a new TrashRetentionJob, TrashRetentionDays=0 keeps deleted records forever
+func NewTrashRetentionJob(
+	logger infrastructure.Logger,
+	env infrastructure.Env,
+	service TrashService,
+) TrashRetentionJob {
+	days := TrashRetentionDefaultDays
+	if env.TrashRetentionDays != "" {
+		parsed, err := strconv.Atoi(env.TrashRetentionDays)
+		if err != nil || parsed < 0 {
+			logger.Zap.Fatalf("TrashRetentionDays: invalid number of days %q", env.TrashRetentionDays)
+		}
+		days = parsed
+	}
+	return TrashRetentionJob{
+		logger:    logger,
+		service:   service,
+		retention: time.Duration(days) * 24 * time.Hour,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start -> purges expired records every interval until Stop is called
+func (s TrashRetentionJob) Start() {
+	if s.retention == 0 {
+		s.logger.Zap.Info("🗑️ trash retention disabled, deleted records are kept")
+		<-s.stop
+		return
+	}
+	ticker := time.NewTicker(TrashRetentionInterval)
+	defer ticker.Stop()
+
+	s.purgeExpired()
+	for {
+		select {
+		case <-ticker.C:
+			s.purgeExpired()
+		case <-s.stop:
+			return
+		}
+	}
+}
+
+// Stop -> stops the job
+func (s TrashRetentionJob) Stop() {
+	close(s.stop)
+}
+
+func (s TrashRetentionJob) purgeExpired() {
+	users, todos, err := s.service.PurgeExpired(time.Now().Add(-s.retention))
+	if err != nil {
+		s.logger.Zap.Error("Error [TrashRetentionJob] [PurgeExpired]: ", err.Error())
+		return
+	}
+	if users > 0 || todos > 0 {
+		s.logger.Zap.Infof("🗑️ purged %d users and %d todos deleted more than %s ago", users, todos, s.retention)
+	}
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/models"
+	"testing"
+	"time"
+)
+
+func TestTrashServicePurgeKeepsUserContent(t *testing.T) {
+	tests := []struct {
+		name  string
+		purge func(service TrashService, user models.User) error
+	}{
+		{
+			name:  "purge user",
+			purge: func(service TrashService, user models.User) error { return service.PurgeUser(user.ID) },
+		},
+		{
+			name: "purge expired",
+			purge: func(service TrashService, user models.User) error {
+				_, _, err := service.PurgeExpired(time.Now().Add(time.Minute))
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			author := createTestUser(t, db, "author")
+			reader := createTestUser(t, db, "reader")
+
+			priority := models.TodoPriorityNone
+			todo := models.Todo{Task: "milk", Priority: &priority, OwnerID: &author.ID, AssigneeID: &author.ID}
+			if err := db.DB.Create(&todo).Error; err != nil {
+				t.Fatal(err)
+			}
+			blog := models.Blog{Title: "title", Slug: "title", Content: "content", CreatedById: &author.ID, UpdatedById: &author.ID}
+			if err := db.DB.Omit("Categories").Create(&blog).Error; err != nil {
+				t.Fatal(err)
+			}
+			comment := models.Comment{BlogId: blog.ID, UserId: &author.ID, Content: "first", Status: models.CommentStatusApproved}
+			if err := db.DB.Create(&comment).Error; err != nil {
+				t.Fatal(err)
+			}
+			readerTodo := models.Todo{Task: "bread", Priority: &priority, OwnerID: &reader.ID}
+			if err := db.DB.Create(&readerTodo).Error; err != nil {
+				t.Fatal(err)
+			}
+			if err := db.DB.Delete(&author).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			service := NewTrashService(
+				repository.NewUserRepository(db, testLogger),
+				repository.NewTodoRepository(db, testLogger),
+				FirebaseService{},
+				testLogger,
+			)
+			if err := test.purge(service, author); err != nil {
+				t.Fatalf("purge: %v", err)
+			}
+
+			var users int64
+			if err := db.DB.Unscoped().Model(&models.User{}).Where("id = ?", author.ID).Count(&users).Error; err != nil {
+				t.Fatal(err)
+			}
+			if users != 0 {
+				t.Errorf("purged user still stored")
+			}
+
+			var keptTodo models.Todo
+			if err := db.DB.First(&keptTodo, todo.ID).Error; err != nil {
+				t.Fatalf("todo of the purged user: %v", err)
+			}
+			if keptTodo.OwnerID != nil || keptTodo.AssigneeID != nil {
+				t.Errorf("todo owner %v assignee %v, want both cleared", keptTodo.OwnerID, keptTodo.AssigneeID)
+			}
+			var keptBlog models.Blog
+			if err := db.DB.First(&keptBlog, blog.ID).Error; err != nil {
+				t.Fatalf("blog of the purged user: %v", err)
+			}
+			if keptBlog.CreatedById != nil || keptBlog.UpdatedById != nil {
+				t.Errorf("blog authors %v %v, want both cleared", keptBlog.CreatedById, keptBlog.UpdatedById)
+			}
+			var keptComment models.Comment
+			if err := db.DB.First(&keptComment, comment.ID).Error; err != nil {
+				t.Fatalf("comment of the purged user: %v", err)
+			}
+			if keptComment.UserId != nil {
+				t.Errorf("comment author %v, want cleared", *keptComment.UserId)
+			}
+
+			var otherTodo models.Todo
+			if err := db.DB.First(&otherTodo, readerTodo.ID).Error; err != nil {
+				t.Fatalf("todo of another user: %v", err)
+			}
+			if otherTodo.OwnerID == nil || *otherTodo.OwnerID != reader.ID {
+				t.Errorf("todo owner of another user = %v, want %d", otherTodo.OwnerID, reader.ID)
+			}
+		})
+	}
+}
@@ -2,12 +2,42 @@ package services
 
 import (
 	"boilerplate-api/api/repository"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
 	"boilerplate-api/models"
 	"boilerplate-api/utils"
+	"strings"
 
 	"gorm.io/gorm"
 )
 
+// UserRoles -> roles a created or imported user can have
+var UserRoles = []string{
+	constants.RoleAdmin,
+	constants.RoleClient,
+	constants.RoleClientAdmin,
+	constants.RoleClientGeneral,
+	constants.RoleClientUser,
+	constants.RoleUser,
+}
+
+// ValidateRole -> role given to a new user by the creator, empty keeps the default role
+// only admins can create admins
+func ValidateRole(role string, creatorRole string) error {
+	if role == "" {
+		return nil
+	}
+	if !utils.StringInList(role, UserRoles) {
+		err := errors.BadRequest.New("invalid role")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return errors.AddErrorContext(err, "role", "Role has to be one of "+strings.Join(UserRoles, ", ")+".")
+	}
+	if role == constants.RoleAdmin && creatorRole != constants.RoleAdmin {
+		return errors.Forbidden.New("only admins can create admins")
+	}
+	return nil
+}
+
 // UserService -> struct
 type UserService struct {
 	repository repository.UserRepository
@@ -26,32 +56,62 @@ func (c UserService) WithTrx(trxHandle *gorm.DB) UserService {
 	return c
 }
 
+// WithTenant -> limits the service to the organization
+func (c UserService) WithTenant(tenantID int64) UserService {
+	c.repository = c.repository.WithTenant(tenantID)
+	return c
+}
+
+// WithViewer -> acts on behalf of the user, outside of an organization only privileged roles see other users
+func (c UserService) WithViewer(userID int64, role string) UserService {
+	if utils.StringInList(role, constants.RolePrivileged) {
+		c.repository = c.repository.WithViewer(0)
+		return c
+	}
+	c.repository = c.repository.WithViewer(userID)
+	return c
+}
+
+// WithFieldset -> limits the fields of users returned by the service
+func (c UserService) WithFieldset(fieldset utils.Fieldset) UserService {
+	c.repository = c.repository.WithFieldset(fieldset)
+	return c
+}
+
 // CreateUser -> call to create the User
 func (c UserService) CreateUser(user *models.User) (*models.User, error) {
 	return c.repository.Create(user)
 }
 
 // GetAllUser -> call to get all the User
-func (c UserService) GetAllUsers(pagination utils.Pagination) ([]models.User, int64, error) {
+func (c UserService) GetAllUsers(pagination utils.Pagination) ([]models.User, utils.PageInfo, error) {
 	return c.repository.GetAllUsers(pagination)
 }
 
+// ExportUsers -> hands the filtered users to fn in batches
+func (c UserService) ExportUsers(pagination utils.Pagination, batchSize int, fn func(users []models.User) error) error {
+	return c.repository.ExportUsers(pagination, batchSize, fn)
+}
+
 // upate user partially
 func (c UserService) UpdatePartial(ID int64, map_update map[string]interface{}) (*models.User, error) {
 	return c.repository.UpdatePartial(ID, map_update)
 }
 
-func (c UserService) UpdateUser(ID string, map_update map[string]interface{}) (*models.User, error) {
-	return c.repository.UpdateUser(ID, map_update)
+// UpdateUser -> updates the user, version 0 skips the version check
+func (c UserService) UpdateUser(ID int64, map_update map[string]interface{}, version int64) (*models.User, error) {
+	return c.repository.UpdateUser(ID, map_update, version)
 }
 
-func (c UserService) GetOneUser(Id string) (*models.User, error) {
-	return c.repository.GetOneUser(Id)
+func (c UserService) GetOneUser(ID int64) (*models.User, error) {
+	return c.repository.GetOneUser(ID)
 }
 
 func (c UserService) GetOneUserWithEmail(Email string) (*models.User, error) {
 	return c.repository.GetOneUserWithEmail(Email)
 }
-func (c UserService) DeleteOneUser(Id string) (*string, error) {
-	return c.repository.DeleteOneUser(Id)
+
+// DeleteOneUser -> deletes the user, version 0 skips the version check
+func (c UserService) DeleteOneUser(ID int64, version int64) (*string, error) {
+	return c.repository.DeleteOneUser(ID, version)
 }
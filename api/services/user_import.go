@@ -0,0 +1,243 @@
+package services
+
+import (
+	"boilerplate-api/api/validators"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/infrastructure"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	// UserImportMaxRows -> most users a single file may carry
+	UserImportMaxRows = 1000
+
+	// Outcomes of an imported row
+	UserImportCreated = "created"
+	UserImportInvited = "invited"
+	UserImportFailed  = "failed"
+)
+
+// UserImportColumns -> columns read on import, rows without password are invited instead of created
+var UserImportColumns = []string{"username", "full_name", "email", "phone", "address", "role", "password"}
+
+// UserExportColumns -> columns written on export, the header matches the import columns
+var UserExportColumns = []string{"id", "username", "full_name", "email", "phone", "address", "role", "email_verified", "phone_verified", "created_at"}
+
+// UserImportRow -> outcome of a row of the file, rows are numbered like in a spreadsheet with the header as row 1
+type UserImportRow struct {
+	Row    int                   `json:"row"`
+	Email  string                `json:"email"`
+	Status string                `json:"status"`
+	Errors []errors.ErrorContext `json:"errors,omitempty"`
+}
+
+// UserImportResult -> per row report of an import
+type UserImportResult struct {
+	Created int             `json:"created"`
+	Invited int             `json:"invited"`
+	Failed  int             `json:"failed"`
+	Rows    []UserImportRow `json:"rows"`
+}
+
+// UserImportService -> creates and invites users from spreadsheet rows
+type UserImportService struct {
+	userService       UserService
+	invitationService InvitationService
+	validator         validators.UserValidator
+	logger            infrastructure.Logger
+	trx               *gorm.DB
+	tenantID          int64
+}
+
+// NewUserImportService -> creates a new UserImportService
+func NewUserImportService(
+	userService UserService,
+	invitationService InvitationService,
+	validator validators.UserValidator,
+	logger infrastructure.Logger,
+) UserImportService {
+	return UserImportService{
+		userService:       userService,
+		invitationService: invitationService,
+		validator:         validator,
+		logger:            logger,
+	}
+}
+
+// WithTrx -> enables the services with transaction, every row runs in its own savepoint
+func (c UserImportService) WithTrx(trxHandle *gorm.DB) UserImportService {
+	c.trx = trxHandle
+	c.userService = c.userService.WithTrx(trxHandle)
+	c.invitationService = c.invitationService.WithTrx(trxHandle)
+	return c
+}
+
+// WithTenant -> imports users into the organization
+func (c UserImportService) WithTenant(tenantID int64) UserImportService {
+	c.tenantID = tenantID
+	c.userService = c.userService.WithTenant(tenantID)
+	c.invitationService = c.invitationService.WithTenant(tenantID)
+	return c
+}
+
+// Import -> creates or invites the user of every row, a failed row does not stop the others
+// the first row is the header, column names are matched case insensitively
+func (c UserImportService) Import(rows [][]string, importer models.User) (UserImportResult, error) {
+	result := UserImportResult{Rows: []UserImportRow{}}
+	if len(rows) == 0 {
+		err := errors.BadRequest.New("empty import file")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return result, errors.AddErrorContext(err, "file", "File has no header row.")
+	}
+	if len(rows)-1 > UserImportMaxRows {
+		err := errors.BadRequest.Newf("import file with %d rows", len(rows)-1)
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return result, errors.AddErrorContext(err, "file", fmt.Sprintf("File can have at most %d users.", UserImportMaxRows))
+	}
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+		if utils.StringInList(name, UserImportColumns) {
+			columns[name] = i
+		}
+	}
+	if _, ok := columns["email"]; !ok {
+		err := errors.BadRequest.New("import file without email column")
+		err = errors.SetCustomMessage(err, "Invalid input information")
+		return result, errors.AddErrorContext(err, "file", "File has to have an email column.")
+	}
+
+	seen := map[string]bool{}
+	for i, values := range rows[1:] {
+		value := func(column string) string {
+			if index, ok := columns[column]; ok && index < len(values) {
+				return strings.TrimSpace(values[index])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(values, "")) == "" {
+			continue
+		}
+		row := UserImportRow{Row: i + 2, Email: strings.ToLower(value("email"))}
+		user := models.User{
+			Username: value("username"),
+			FullName: value("full_name"),
+			Email:    row.Email,
+			Phone:    value("phone"),
+			Address:  value("address"),
+			Role:     value("role"),
+			Password: value("password"),
+		}
+
+		row.Errors = c.validate(&user, importer)
+		if len(row.Errors) == 0 && seen[user.Email] {
+			row.Errors = append(row.Errors, errors.ErrorContext{Field: "email", Message: "Email appears more than once in the file."})
+		}
+		seen[user.Email] = true
+		if len(row.Errors) == 0 {
+			row.Status, row.Errors = c.importRow(row.Row, user, importer)
+		}
+		if len(row.Errors) > 0 {
+			row.Status = UserImportFailed
+		}
+		switch row.Status {
+		case UserImportCreated:
+			result.Created++
+		case UserImportInvited:
+			result.Invited++
+		default:
+			result.Failed++
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, nil
+}
+
+// validate -> field errors of the row, users with password need a complete profile
+func (c UserImportService) validate(user *models.User, importer models.User) []errors.ErrorContext {
+	var validations []errors.ErrorContext
+	if user.Role == "" {
+		user.Role = constants.RoleUser
+		if c.tenantID != 0 {
+			user.Role = constants.RoleClientUser
+		}
+	}
+	if !utils.StringInList(user.Role, UserRoles) {
+		validations = append(validations, errors.ErrorContext{Field: "role", Message: "Role has to be one of " + strings.Join(UserRoles, ", ") + "."})
+	} else if user.Role == constants.RoleAdmin && importer.Role != constants.RoleAdmin {
+		validations = append(validations, errors.ErrorContext{Field: "role", Message: "Only admins can import admins."})
+	}
+
+	if user.Password == "" {
+		if err := c.validator.Validate.Var(user.Email, "required,email"); err != nil {
+			validations = append(validations, errors.ErrorContext{Field: "email", Message: "Field 'email' is not valid."})
+		}
+		return validations
+	}
+	if err := c.validator.Validate.Struct(user); err != nil {
+		validations = append(validations, c.validator.GenerateValidationResponse(err)...)
+	}
+	if user.Email != "" && !utils.IsValidEmail(user.Email) {
+		validations = append(validations, errors.ErrorContext{Field: "email", Message: "Field 'email' is not valid."})
+	}
+	return validations
+}
+
+// importRow -> creates the user when a password is given and invites them otherwise
+func (c UserImportService) importRow(row int, user models.User, importer models.User) (string, []errors.ErrorContext) {
+	if _, err := c.userService.GetOneUserWithEmail(user.Email); err == nil {
+		return UserImportFailed, []errors.ErrorContext{{Field: "email", Message: "Email address already taken."}}
+	}
+
+	savepoint := fmt.Sprintf("import_%d", row)
+	if c.trx != nil {
+		if err := c.trx.SavePoint(savepoint).Error; err != nil {
+			return UserImportFailed, importError(err)
+		}
+	}
+	status, err := c.save(user, importer)
+	if err != nil {
+		c.logger.Zap.Error("Error [Import] row ", row, ": ", err.Error())
+		if c.trx != nil {
+			if rollbackErr := c.trx.RollbackTo(savepoint).Error; rollbackErr != nil {
+				c.logger.Zap.Error("Error [Import] rolling back to savepoint: ", rollbackErr.Error())
+			}
+		}
+		return UserImportFailed, importError(err)
+	}
+	return status, nil
+}
+
+func (c UserImportService) save(user models.User, importer models.User) (string, error) {
+	if user.Password == "" {
+		if _, err := c.invitationService.Invite(user.Email, user.Role, importer); err != nil {
+			return "", err
+		}
+		return UserImportInvited, nil
+	}
+	password, err := bcrypt.GenerateFromPassword([]byte(user.Password), 10)
+	if err != nil {
+		return "", err
+	}
+	user.Password = string(password)
+	if _, err := c.userService.CreateUser(&user); err != nil {
+		return "", err
+	}
+	return UserImportCreated, nil
+}
+
+// importError -> error of a row, custom messages of typed errors are kept
+func importError(err error) []errors.ErrorContext {
+	message := errors.GetCustomMessage(err)
+	if message == "" || errors.GetErrorType(err) == errors.InternalError {
+		message = "Failed to import the user."
+	}
+	return []errors.ErrorContext{{Field: "row", Message: message}}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/api/validators"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"boilerplate-api/models"
+	"boilerplate-api/utils"
+	"reflect"
+	"testing"
+)
+
+func TestUserImportServiceImport(t *testing.T) {
+	header := []string{"Username", "Full Name", "EMAIL", "Phone", "Address", "Role", "Password"}
+	row := func(name string, role string, password string) []string {
+		return []string{name, name, name + "@example.com", name, "address", role, password}
+	}
+
+	tests := []struct {
+		name       string
+		rows       [][]string
+		importer   string
+		wantErr    bool
+		wantFailed int
+		wantErrors [][]string
+		wantRows   []int
+	}{
+		{name: "empty file", wantErr: true},
+		{name: "header without email", rows: [][]string{{"username", "password"}, {"jane", "secret"}}, wantErr: true},
+		{name: "too many rows", rows: make([][]string, UserImportMaxRows+2), wantErr: true},
+		{
+			name:       "unknown role",
+			rows:       [][]string{header, row("jane", "superuser", "secret")},
+			importer:   constants.RoleAdmin,
+			wantFailed: 1,
+			wantErrors: [][]string{{"role"}},
+		},
+		{
+			name:       "admin by a client admin",
+			rows:       [][]string{header, row("jane", constants.RoleAdmin, "secret")},
+			importer:   constants.RoleClientAdmin,
+			wantFailed: 1,
+			wantErrors: [][]string{{"role"}},
+		},
+		{
+			name:       "invitation with an invalid email",
+			rows:       [][]string{header, {"", "", "not an email"}},
+			importer:   constants.RoleAdmin,
+			wantFailed: 1,
+			wantErrors: [][]string{{"email"}},
+		},
+		{
+			name:       "user with password and an incomplete profile",
+			rows:       [][]string{header, {"jane", "", "jane@example.com", "", "", "", "secret"}},
+			importer:   constants.RoleAdmin,
+			wantFailed: 1,
+			wantErrors: [][]string{{"Phone", "FullName", "Address"}},
+		},
+		{
+			name:       "email already taken",
+			rows:       [][]string{header, row("taken", "", "secret")},
+			importer:   constants.RoleAdmin,
+			wantFailed: 1,
+			wantErrors: [][]string{{"email"}},
+		},
+		{
+			name:       "email twice in the file and blank rows",
+			rows:       [][]string{header, row("taken", "", "secret"), {"", " "}, row("taken", "", "secret")},
+			importer:   constants.RoleAdmin,
+			wantFailed: 2,
+			wantErrors: [][]string{{"email"}, {"email"}},
+			wantRows:   []int{2, 4},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			createTestUser(t, db, "taken")
+			userService := NewUserService(repository.NewUserRepository(db, testLogger))
+			service := NewUserImportService(userService, InvitationService{}, validators.NewUserValidator(), testLogger).WithTrx(db.DB)
+
+			result, err := service.Import(test.rows, models.User{Role: test.importer})
+			if test.wantErr {
+				if err == nil || errors.GetErrorType(err) != errors.BadRequest {
+					t.Fatalf("error = %v, want BadRequest", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Failed != test.wantFailed || result.Created != 0 || result.Invited != 0 {
+				t.Errorf("result = %d created %d invited %d failed, want %d failed", result.Created, result.Invited, result.Failed, test.wantFailed)
+			}
+			var fields [][]string
+			var rows []int
+			for _, row := range result.Rows {
+				rows = append(rows, row.Row)
+				if row.Status != UserImportFailed {
+					t.Errorf("row %d status = %q, want failed", row.Row, row.Status)
+				}
+				var rowFields []string
+				for _, context := range row.Errors {
+					rowFields = append(rowFields, context.Field)
+				}
+				fields = append(fields, rowFields)
+			}
+			if !reflect.DeepEqual(fields, test.wantErrors) {
+				t.Errorf("row errors = %v, want %v", fields, test.wantErrors)
+			}
+			if test.wantRows != nil && !reflect.DeepEqual(rows, test.wantRows) {
+				t.Errorf("rows = %v, want %v", rows, test.wantRows)
+			}
+		})
+	}
+}
+
+func TestUserServiceExportUsers(t *testing.T) {
+	db := newTestDatabase(t)
+	var want []int64
+	for _, name := range []string{"amy", "ben", "cat", "dan", "eve"} {
+		want = append(want, createTestUser(t, db, name).ID)
+	}
+	service := NewUserService(repository.NewUserRepository(db, testLogger))
+
+	var batches []int
+	var got []int64
+	err := service.ExportUsers(utils.Pagination{}, 2, func(users []models.User) error {
+		batches = append(batches, len(users))
+		for _, user := range users {
+			got = append(got, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(batches, []int{2, 2, 1}) {
+		t.Errorf("batches = %v, want [2 2 1]", batches)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("exported ids = %v, want %v", got, want)
+	}
+}
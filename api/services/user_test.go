@@ -0,0 +1,79 @@
+package services
+
+import (
+	"boilerplate-api/api/repository"
+	"boilerplate-api/constants"
+	"boilerplate-api/errors"
+	"testing"
+)
+
+func TestValidateRole(t *testing.T) {
+	tests := []struct {
+		name        string
+		role        string
+		creatorRole string
+		wantErr     bool
+		wantErrType errors.HttpErrorType
+	}{
+		{name: "default role", role: "", creatorRole: constants.RoleClientAdmin},
+		{name: "client user by client admin", role: constants.RoleClientUser, creatorRole: constants.RoleClientAdmin},
+		{name: "client admin by client admin", role: constants.RoleClientAdmin, creatorRole: constants.RoleClientAdmin},
+		{name: "admin by admin", role: constants.RoleAdmin, creatorRole: constants.RoleAdmin},
+		{name: "admin by client admin", role: constants.RoleAdmin, creatorRole: constants.RoleClientAdmin, wantErr: true, wantErrType: errors.Forbidden},
+		{name: "admin without creator role", role: constants.RoleAdmin, wantErr: true, wantErrType: errors.Forbidden},
+		{name: "unknown role", role: "superuser", creatorRole: constants.RoleAdmin, wantErr: true, wantErrType: errors.BadRequest},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateRole(test.role, test.creatorRole)
+			if !test.wantErr {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || errors.GetErrorType(err) != test.wantErrType {
+				t.Fatalf("error = %v, want type %v", err, test.wantErrType)
+			}
+		})
+	}
+}
+
+func TestUserServiceUpdateUserChecksVersion(t *testing.T) {
+	tests := []struct {
+		name        string
+		updates     map[string]interface{}
+		version     int64
+		wantErr     bool
+		wantVersion int64
+	}{
+		{name: "fields at the current version", updates: map[string]interface{}{"full_name": "Jane Doe"}, version: 1, wantVersion: 2},
+		{name: "fields at a stale version", updates: map[string]interface{}{"full_name": "Jane Doe"}, version: 5, wantErr: true},
+		{name: "no fields at the current version", updates: map[string]interface{}{}, version: 1, wantVersion: 2},
+		{name: "no fields at a stale version", updates: map[string]interface{}{}, version: 5, wantErr: true},
+		{name: "no version check", updates: map[string]interface{}{}, wantVersion: 2},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			db := newTestDatabase(t)
+			user := createTestUser(t, db, "jane")
+			service := NewUserService(repository.NewUserRepository(db, testLogger))
+
+			updated, err := service.UpdateUser(user.ID, test.updates, test.version)
+			if test.wantErr {
+				if errors.GetErrorType(err) != errors.PreconditionFailed {
+					t.Fatalf("error = %v, want PreconditionFailed", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if updated.Version != test.wantVersion {
+				t.Errorf("version = %d, want %d", updated.Version, test.wantVersion)
+			}
+		})
+	}
+}
@@ -6,4 +6,6 @@ var Module = fx.Options(
 	fx.Provide(NewUserValidator),
 	fx.Provide(NewCategoryValidator),
 	fx.Provide(NewBlogValidator),
+	fx.Provide(NewCommentValidator),
+	fx.Provide(NewTodoListValidator),
 )
@@ -38,9 +38,13 @@ func bootstrap(
 	logger infrastructure.Logger,
 	middlewares middlewares.Middlewares,
 	database infrastructure.Database,
+	replicaHealthCheck infrastructure.ReplicaHealthCheck,
 	cliApp cli.Application,
 	migrations infrastructure.Migrations,
 	seeds seeds.Seeds,
+	blogScheduler services.BlogScheduler,
+	trashRetentionJob services.TrashRetentionJob,
+	idempotencyCleanupJob services.IdempotencyCleanupJob,
 ) {
 
 	appStop := func(context.Context) error {
@@ -78,6 +82,10 @@ func bootstrap(
 				routes.Setup()
 				logger.Zap.Info("🌱 seeding data...")
 				seeds.Run()
+				go blogScheduler.Start()
+				go trashRetentionJob.Start()
+				go idempotencyCleanupJob.Start()
+				go replicaHealthCheck.Start()
 				if env.ServerPort == "" {
 					handler.Gin.Run(":5000")
 				} else {
@@ -86,6 +94,12 @@ func bootstrap(
 			}()
 			return nil
 		},
-		OnStop: appStop,
+		OnStop: func(ctx context.Context) error {
+			blogScheduler.Stop()
+			trashRetentionJob.Stop()
+			idempotencyCleanupJob.Stop()
+			replicaHealthCheck.Stop()
+			return appStop(ctx)
+		},
 	})
 }
@@ -7,6 +7,9 @@ const (
 	// Claims -> authentication claims
 	Claims = "Claims"
 
+	// TenantID -> organization the authenticated user is acting in
+	TenantID = "tenant_id"
+
 	// UID -> authenticated user's id
 	UID = "UID"
 
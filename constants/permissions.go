@@ -0,0 +1,19 @@
+package constants
+
+const (
+	// List of permissions
+	PermissionUserRead  = "user:read"
+	PermissionUserWrite = "user:write"
+	PermissionTodoRead  = "todo:read"
+	PermissionTodoWrite = "todo:write"
+)
+
+// RolePermissions -> permissions granted to each role
+var RolePermissions = map[string][]string{
+	RoleAdmin:         {PermissionUserRead, PermissionUserWrite, PermissionTodoRead, PermissionTodoWrite},
+	RoleClientAdmin:   {PermissionUserRead, PermissionUserWrite, PermissionTodoRead, PermissionTodoWrite},
+	RoleClient:        {PermissionUserRead, PermissionTodoRead, PermissionTodoWrite},
+	RoleClientGeneral: {PermissionTodoRead, PermissionTodoWrite},
+	RoleClientUser:    {PermissionTodoRead, PermissionTodoWrite},
+	RoleUser:          {PermissionTodoRead, PermissionTodoWrite},
+}
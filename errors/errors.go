@@ -18,6 +18,10 @@ const (
 	Conflict
 	InternalError
 	Unavailable
+	TooManyRequests
+	PreconditionFailed
+	PreconditionRequired
+	UnsupportedMediaType
 )
 
 // GetStatusCode returns the status code for the error type
@@ -37,6 +41,14 @@ func GetStatusCode(httpErrorType HttpErrorType) int {
 		return http.StatusInternalServerError
 	case Unavailable:
 		return http.StatusServiceUnavailable
+	case TooManyRequests:
+		return http.StatusTooManyRequests
+	case PreconditionFailed:
+		return http.StatusPreconditionFailed
+	case PreconditionRequired:
+		return http.StatusPreconditionRequired
+	case UnsupportedMediaType:
+		return http.StatusUnsupportedMediaType
 	default:
 		return http.StatusInternalServerError
 	}
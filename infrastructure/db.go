@@ -3,17 +3,30 @@ package infrastructure
 import (
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 	"time"
 
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
+)
+
+const (
+	// Database drivers selected with DBDriver
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
 )
 
 // Database modal
 type Database struct {
 	DB *gorm.DB
+
+	replicas *replicaSet
 }
 
 // NewDatabase creates a new database instance
@@ -30,10 +43,65 @@ func NewDatabase(Zaplogger Logger, env Env) Database {
 
 	Zaplogger.Zap.Info(env)
 
-	url := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", env.DBUsername, env.DBPassword, env.DBHost, env.DBPort, env.DBName)
+	dialector, err := newDialector(env)
+	if err != nil {
+		Zaplogger.Zap.Panic(err)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: newLogger})
+	if err != nil {
+		Zaplogger.Zap.Info("Driver: ", env.DBDriver)
+		Zaplogger.Zap.Panic(err)
+	}
+	if env.DBDriver == DriverMySQL {
+		_ = db.Exec("CREATE DATABASE IF NOT EXISTS " + env.DBName + ";")
+	}
 
+	if err := registerErrorTranslation(db); err != nil {
+		Zaplogger.Zap.Panic(err)
+	}
+
+	replicas, err := useReplicas(db, env)
+	if err != nil {
+		Zaplogger.Zap.Panic(err)
+	}
+
+	Zaplogger.Zap.Info("Database connection established")
+
+	return Database{
+		DB:       db,
+		replicas: replicas,
+	}
+}
+
+// Primary -> queries the primary even when they only read, for reads that must see writes made just before
+// inside a transaction every query runs on the primary already
+func (d Database) Primary() *gorm.DB {
+	return d.DB.Clauses(dbresolver.Write)
+}
+
+// newDialector -> gorm dialector of the configured driver
+func newDialector(env Env) (gorm.Dialector, error) {
+	switch env.DBDriver {
+	case DriverMySQL:
+		return mysql.Open(mysqlDSN(env)), nil
+	case DriverPostgres:
+		return postgres.Open(postgresDSN(env)), nil
+	case DriverSQLite:
+		return sqlite.Open(sqliteDSN(env)), nil
+	}
+	return nil, unsupportedDriverError(env.DBDriver)
+}
+
+// unsupportedDriverError -> DBDriver is none of the supported drivers
+func unsupportedDriverError(driver string) error {
+	return fmt.Errorf("unsupported database driver %s, use %s, %s or %s", driver, DriverMySQL, DriverPostgres, DriverSQLite)
+}
+
+// mysqlDSN -> TCP locally, Cloud SQL unix socket otherwise
+func mysqlDSN(env Env) string {
 	if env.Environment != "local" {
-		url = fmt.Sprintf(
+		return fmt.Sprintf(
 			"%s:%s@unix(/cloudsql/%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
 			env.DBUsername,
 			env.DBPassword,
@@ -41,17 +109,27 @@ func NewDatabase(Zaplogger Logger, env Env) Database {
 			env.DBName,
 		)
 	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", env.DBUsername, env.DBPassword, env.DBHost, env.DBPort, env.DBName)
+}
 
-	db, err := gorm.Open(mysql.Open(url), &gorm.Config{Logger: newLogger})
-	_ = db.Exec("CREATE DATABASE IF NOT EXISTS " + env.DBName + ";")
-	if err != nil {
-		Zaplogger.Zap.Info("Url: ", url)
-		Zaplogger.Zap.Panic(err)
+// postgresDSN -> connection url, TCP locally and Cloud SQL unix socket otherwise
+func postgresDSN(env Env) string {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(env.DBUsername, env.DBPassword),
+		Host:   env.DBHost + ":" + env.DBPort,
+		Path:   "/" + env.DBName,
 	}
-
-	Zaplogger.Zap.Info("Database connection established")
-
-	return Database{
-		DB: db,
+	query := url.Values{"sslmode": {"disable"}}
+	if env.Environment != "local" {
+		dsn.Host = ""
+		query.Set("host", "/cloudsql/"+env.DBHost)
 	}
+	dsn.RawQuery = query.Encode()
+	return dsn.String()
+}
+
+// sqliteDSN -> DBName is the database file, foreign keys are off in SQLite unless enabled per connection
+func sqliteDSN(env Env) string {
+	return env.DBName + "?_foreign_keys=on"
 }
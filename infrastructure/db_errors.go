@@ -0,0 +1,143 @@
+package infrastructure
+
+import (
+	"boilerplate-api/errors"
+	stdErrors "errors"
+	"fmt"
+	"strings"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// uniqueViolation -> unique constraint violation reported by the driver
+// MySQL only names the violated key, PostgreSQL and SQLite name the columns
+type uniqueViolation struct {
+	constraint string
+	columns    []string
+}
+
+// parseUniqueViolation -> unique constraint violation of any of the drivers
+func parseUniqueViolation(err error) (uniqueViolation, bool) {
+	var mysqlErr *mysqlDriver.MySQLError
+	if stdErrors.As(err, &mysqlErr) {
+		if mysqlErr.Number != 1062 {
+			return uniqueViolation{}, false
+		}
+		// Duplicate entry 'value' for key 'UQ_user_email', MySQL 8 prefixes the key with the table
+		key := mysqlErr.Message
+		if i := strings.LastIndex(key, " for key "); i >= 0 {
+			key = key[i+len(" for key "):]
+		}
+		key = strings.Trim(key, "'")
+		return uniqueViolation{constraint: key[strings.LastIndex(key, ".")+1:]}, true
+	}
+	var postgresErr *pgconn.PgError
+	if stdErrors.As(err, &postgresErr) {
+		if postgresErr.Code != "23505" {
+			return uniqueViolation{}, false
+		}
+		// Key (organization_id, user_id)=(1, 2) already exists.
+		violation := uniqueViolation{constraint: postgresErr.ConstraintName}
+		if start, end := strings.Index(postgresErr.Detail, "("), strings.Index(postgresErr.Detail, ")="); start >= 0 && end > start {
+			violation.columns = splitColumns(postgresErr.Detail[start+1 : end])
+		}
+		return violation, true
+	}
+	var sqliteErr sqlite3.Error
+	if stdErrors.As(err, &sqliteErr) {
+		if sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique && sqliteErr.ExtendedCode != sqlite3.ErrConstraintPrimaryKey {
+			return uniqueViolation{}, false
+		}
+		// UNIQUE constraint failed: TodoTag.TodoID, TodoTag.TagID
+		violation := uniqueViolation{}
+		if i := strings.Index(sqliteErr.Error(), ": "); i >= 0 {
+			violation.columns = splitColumns(sqliteErr.Error()[i+2:])
+		}
+		return violation, true
+	}
+	return uniqueViolation{}, false
+}
+
+// splitColumns -> column names of a comma separated list, without quotes and table prefixes
+func splitColumns(list string) []string {
+	var columns []string
+	for _, column := range strings.Split(list, ",") {
+		column = strings.Trim(strings.TrimSpace(column), `"`)
+		if column == "" {
+			continue
+		}
+		columns = append(columns, column[strings.LastIndex(column, ".")+1:])
+	}
+	return columns
+}
+
+// fields -> API names of the violated columns, the json name of the model field when there is one
+// a MySQL key is matched against the columns of the model by the UQ_<table>_<column> naming of the migrations
+func (v uniqueViolation) fields(stmt *gorm.Statement) []string {
+	columns := v.columns
+	if len(columns) == 0 && v.constraint != "" && stmt.Schema != nil {
+		column := strings.TrimPrefix(v.constraint, "UQ_"+stmt.Schema.Table+"_")
+		if stmt.Schema.LookUpField(column) != nil {
+			columns = []string{column}
+		}
+	}
+
+	fields := make([]string, 0, len(columns))
+	for _, column := range columns {
+		field := column
+		if stmt.Schema != nil {
+			if schemaField := stmt.Schema.LookUpField(column); schemaField != nil {
+				if name := strings.Split(schemaField.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+					field = name
+				}
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// TranslateError -> unique constraint violation of the statement as Conflict with the violated fields, other errors unchanged
+func TranslateError(err error, stmt *gorm.Statement) error {
+	violation, ok := parseUniqueViolation(err)
+	if !ok {
+		return err
+	}
+	fields := violation.fields(stmt)
+
+	err = errors.Conflict.Wrap(err, "unique constraint violated")
+	if len(fields) == 1 {
+		label := strings.ReplaceAll(fields[0], "_", " ")
+		err = errors.SetCustomMessage(err, strings.ToUpper(label[:1])+label[1:]+" already taken")
+	} else {
+		err = errors.SetCustomMessage(err, "Record already exists")
+	}
+	for _, field := range fields {
+		err = errors.AddErrorContext(err, field, fmt.Sprintf("Field '%s' is already taken.", field))
+	}
+	return err
+}
+
+// registerErrorTranslation -> translates the errors of every write, so repositories never hand driver errors to services
+func registerErrorTranslation(db *gorm.DB) error {
+	translate := func(db *gorm.DB) {
+		if db.Error != nil {
+			db.Error = TranslateError(db.Error, db.Statement)
+		}
+	}
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().After("*").Register("app:translate_error", translate),
+		callbacks.Update().After("*").Register("app:translate_error", translate),
+		callbacks.Delete().After("*").Register("app:translate_error", translate),
+		callbacks.Raw().After("*").Register("app:translate_error", translate),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
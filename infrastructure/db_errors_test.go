@@ -0,0 +1,204 @@
+package infrastructure
+
+import (
+	"boilerplate-api/errors"
+	stdErrors "errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	mysqlDriver "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgconn"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+// testAccount -> model with a single column and a composite unique key, json names differ from the columns
+type testAccount struct {
+	ID             int64  `json:"id"`
+	UserName       string `gorm:"column:user_name;uniqueIndex" json:"username"`
+	OrganizationID int64  `gorm:"uniqueIndex:UQ_test_accounts_member" json:"organization_id"`
+	UserID         int64  `gorm:"uniqueIndex:UQ_test_accounts_member" json:"user_id"`
+}
+
+func testAccountStatement(t *testing.T) *gorm.Statement {
+	t.Helper()
+	accountSchema, err := schema.Parse(&testAccount{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &gorm.Statement{Schema: accountSchema}
+}
+
+func TestTranslateError(t *testing.T) {
+	stmt := testAccountStatement(t)
+	tests := []struct {
+		name        string
+		err         error
+		stmt        *gorm.Statement
+		wantMessage string
+		wantFields  []string
+		unchanged   bool
+	}{
+		{
+			name:        "MySQL 8 key with table prefix",
+			err:         &mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry 'jane' for key 'test_accounts.UQ_test_accounts_user_name'"},
+			stmt:        stmt,
+			wantMessage: "Username already taken",
+			wantFields:  []string{"username"},
+		},
+		{
+			name:        "MySQL 5.7 key",
+			err:         &mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry 'jane' for key 'UQ_test_accounts_user_name'"},
+			stmt:        stmt,
+			wantMessage: "Username already taken",
+			wantFields:  []string{"username"},
+		},
+		{
+			name:        "MySQL composite key",
+			err:         &mysqlDriver.MySQLError{Number: 1062, Message: "Duplicate entry '1-2' for key 'UQ_test_accounts_member'"},
+			stmt:        stmt,
+			wantMessage: "Record already exists",
+		},
+		{
+			name:      "MySQL other error",
+			err:       &mysqlDriver.MySQLError{Number: 1452, Message: "Cannot add or update a child row"},
+			stmt:      stmt,
+			unchanged: true,
+		},
+		{
+			name:        "PostgreSQL single column",
+			err:         &pgconn.PgError{Code: "23505", ConstraintName: "idx_test_accounts_user_name", Detail: "Key (user_name)=(jane) already exists."},
+			stmt:        stmt,
+			wantMessage: "Username already taken",
+			wantFields:  []string{"username"},
+		},
+		{
+			name:        "PostgreSQL composite key",
+			err:         &pgconn.PgError{Code: "23505", ConstraintName: "UQ_test_accounts_member", Detail: `Key (organization_id, "user_id")=(1, 2) already exists.`},
+			stmt:        stmt,
+			wantMessage: "Record already exists",
+			wantFields:  []string{"organization_id", "user_id"},
+		},
+		{
+			name:      "PostgreSQL other error",
+			err:       &pgconn.PgError{Code: "23503"},
+			stmt:      stmt,
+			unchanged: true,
+		},
+		{
+			name:        "wrapped driver error",
+			err:         fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", Detail: "Key (user_name)=(jane) already exists."}),
+			stmt:        stmt,
+			wantMessage: "Username already taken",
+			wantFields:  []string{"username"},
+		},
+		{
+			name:        "statement without schema keeps the column names",
+			err:         &pgconn.PgError{Code: "23505", Detail: "Key (user_name)=(jane) already exists."},
+			stmt:        &gorm.Statement{},
+			wantMessage: "User name already taken",
+			wantFields:  []string{"user_name"},
+		},
+		{
+			name:      "other error",
+			err:       stdErrors.New("connection refused"),
+			stmt:      stmt,
+			unchanged: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := TranslateError(test.err, test.stmt)
+			if test.unchanged {
+				if got != test.err {
+					t.Errorf("TranslateError() = %v, want the error unchanged", got)
+				}
+				return
+			}
+			assertConflict(t, got, test.wantMessage, test.wantFields)
+		})
+	}
+}
+
+func TestRegisterErrorTranslation(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registerErrorTranslation(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&testAccount{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&testAccount{UserName: "jane", OrganizationID: 1, UserID: 1}).Error; err != nil {
+		t.Fatal(err)
+	}
+	other := testAccount{UserName: "john", OrganizationID: 1, UserID: 2}
+	if err := db.Create(&other).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		write       func() error
+		wantMessage string
+		wantFields  []string
+	}{
+		{
+			name:        "create with a taken column",
+			write:       func() error { return db.Create(&testAccount{UserName: "jane", OrganizationID: 2, UserID: 3}).Error },
+			wantMessage: "Username already taken",
+			wantFields:  []string{"username"},
+		},
+		{
+			name:        "create with a taken composite key",
+			write:       func() error { return db.Create(&testAccount{UserName: "jim", OrganizationID: 1, UserID: 1}).Error },
+			wantMessage: "Record already exists",
+			wantFields:  []string{"organization_id", "user_id"},
+		},
+		{
+			name:        "update to a taken column",
+			write:       func() error { return db.Model(&other).Update("user_name", "jane").Error },
+			wantMessage: "Username already taken",
+			wantFields:  []string{"username"},
+		},
+		{
+			name: "raw statement keeps the column names",
+			write: func() error {
+				return db.Exec("INSERT INTO test_accounts (user_name, organization_id, user_id) VALUES (?, ?, ?)", "jane", 5, 5).Error
+			},
+			wantMessage: "User name already taken",
+			wantFields:  []string{"user_name"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assertConflict(t, test.write(), test.wantMessage, test.wantFields)
+		})
+	}
+}
+
+// assertConflict -> checks the error is a Conflict with the message and an error context for each field
+func assertConflict(t *testing.T, err error, message string, fields []string) {
+	t.Helper()
+	if errors.GetErrorType(err) != errors.Conflict {
+		t.Fatalf("error = %v, want Conflict", err)
+	}
+	if got := errors.GetCustomMessage(err); got != message {
+		t.Errorf("message = %q, want %q", got, message)
+	}
+	var gotFields []string
+	for _, context := range errors.GetErrorContext(err) {
+		gotFields = append(gotFields, context.Field)
+	}
+	if !reflect.DeepEqual(gotFields, fields) {
+		t.Errorf("fields = %v, want %v", gotFields, fields)
+	}
+}
@@ -9,12 +9,23 @@ type Env struct {
 	ServerPort  string
 	Environment string
 	LogOutput   string
+	DBDriver    string
 	DBUsername  string
 	DBPassword  string
 	DBHost      string
 	DBPort      string
 	DBName      string
-	SentryDSN   string
+
+	DBReplicaHosts    string
+	DBMaxOpenConns    string
+	DBMaxIdleConns    string
+	DBConnMaxLifetime string
+	DBConnMaxIdleTime string
+
+	SentryDSN string
+	ClientURL string
+
+	TrustedProxies string
 
 	StorageBucketName string
 
@@ -37,6 +48,17 @@ type Env struct {
 	TwilioSMSFrom   string
 	FirebaseApiKey  string
 	JWT_SECRET      string
+
+	JWT_ENCRYPTION_ENABLED   string
+	JWT_ENCRYPTION_KEY_FILE  string
+	JWT_ENCRYPTION_ALGORITHM string
+	JWT_CLAIMS               string
+
+	PaginationCursorSecret string
+
+	TrashRetentionDays string
+
+	IdempotencyKeyHours string
 }
 
 // NewEnv creates a new environment
@@ -49,17 +71,42 @@ func NewEnv() Env {
 // LoadEnv loads environment
 func (env *Env) LoadEnv() {
 	env.ServerPort = os.Getenv("ServerPort")
+	env.TrustedProxies = os.Getenv("TrustedProxies")
 	env.Environment = os.Getenv("Environment")
 	env.LogOutput = os.Getenv("LogOutput")
 	env.JWT_SECRET = os.Getenv("JWT_SECRET")
+	env.JWT_ENCRYPTION_ENABLED = os.Getenv("JWT_ENCRYPTION_ENABLED")
+	env.JWT_ENCRYPTION_KEY_FILE = os.Getenv("JWT_ENCRYPTION_KEY_FILE")
+	env.JWT_ENCRYPTION_ALGORITHM = os.Getenv("JWT_ENCRYPTION_ALGORITHM")
+	env.JWT_CLAIMS = os.Getenv("JWT_CLAIMS")
+
+	env.PaginationCursorSecret = os.Getenv("PaginationCursorSecret")
+	if env.PaginationCursorSecret == "" {
+		env.PaginationCursorSecret = env.JWT_SECRET
+	}
+
+	env.TrashRetentionDays = os.Getenv("TrashRetentionDays")
 
+	env.IdempotencyKeyHours = os.Getenv("IdempotencyKeyHours")
+
+	env.DBDriver = os.Getenv("DBDriver")
+	if env.DBDriver == "" {
+		env.DBDriver = DriverMySQL
+	}
 	env.DBUsername = os.Getenv("DBUsername")
 	env.DBPassword = os.Getenv("DBPassword")
 	env.DBHost = os.Getenv("DBHost")
 	env.DBPort = os.Getenv("DBPort")
 	env.DBName = os.Getenv("DBName")
 
+	env.DBReplicaHosts = os.Getenv("DBReplicaHosts")
+	env.DBMaxOpenConns = os.Getenv("DBMaxOpenConns")
+	env.DBMaxIdleConns = os.Getenv("DBMaxIdleConns")
+	env.DBConnMaxLifetime = os.Getenv("DBConnMaxLifetime")
+	env.DBConnMaxIdleTime = os.Getenv("DBConnMaxIdleTime")
+
 	env.SentryDSN = os.Getenv("SentryDSN")
+	env.ClientURL = os.Getenv("ClientURL")
 	env.StorageBucketName = os.Getenv("StorageBucketName")
 
 	env.AdminEmail = os.Getenv("AdminEmail")
@@ -8,6 +8,7 @@ var Module = fx.Options(
 	fx.Provide(NewRouter),
 	fx.Provide(NewEnv),
 	fx.Provide(NewDatabase),
+	fx.Provide(NewReplicaHealthCheck),
 	fx.Provide(NewFBApp),
 	fx.Provide(NewFBAuth),
 	fx.Provide(NewFirestoreClient),
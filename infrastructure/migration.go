@@ -1,10 +1,10 @@
 package infrastructure
 
 import (
-	"fmt"
-
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/mysql"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/database/sqlite3"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
@@ -29,26 +29,17 @@ func NewMigrations(
 func (m Migrations) Migrate() {
 	m.logger.Zap.Info("Migrating schemas...")
 
-	USER := m.env.DBUsername
-	PASS := m.env.DBPassword
-	HOST := m.env.DBHost
-	PORT := m.env.DBPort
-	DBNAME := m.env.DBName
-	ENVIRONMENT := m.env.Environment
-
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local", USER, PASS, HOST, PORT, DBNAME)
-
-	if ENVIRONMENT != "local" {
-		dsn = fmt.Sprintf(
-			"%s:%s@unix(/cloudsql/%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-			USER,
-			PASS,
-			HOST,
-			DBNAME,
-		)
+	databaseURL, err := migrationDatabaseURL(m.env)
+	if err != nil {
+		m.logger.Zap.Error("Error in migration: ", err.Error())
+		return
+	}
+	// every driver has its own migration directory, versions are kept in step across them
+	migrations, err := migrate.New("file://migration/"+m.env.DBDriver, databaseURL)
+	if err != nil {
+		m.logger.Zap.Error("Error in migration: ", err.Error())
+		return
 	}
-
-	migrations, err := migrate.New("file://migration/", "mysql://"+dsn)
 
 	m.logger.Zap.Info("--- Running Migration ---")
 	err = migrations.Steps(1000)
@@ -56,3 +47,16 @@ func (m Migrations) Migrate() {
 		m.logger.Zap.Error("Error in migration: ", err.Error())
 	}
 }
+
+// migrationDatabaseURL -> database url of the configured driver for golang-migrate
+func migrationDatabaseURL(env Env) (string, error) {
+	switch env.DBDriver {
+	case DriverMySQL:
+		return "mysql://" + mysqlDSN(env), nil
+	case DriverPostgres:
+		return postgresDSN(env), nil
+	case DriverSQLite:
+		return "sqlite3://" + sqliteDSN(env), nil
+	}
+	return "", unsupportedDriverError(env.DBDriver)
+}
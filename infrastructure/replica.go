@@ -0,0 +1,241 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const (
+	// ReplicaHealthCheckInterval -> how often the read replicas are pinged
+	ReplicaHealthCheckInterval = 10 * time.Second
+
+	// ReplicaPingTimeout -> how long a replica has to answer a ping before it counts as unhealthy
+	ReplicaPingTimeout = 2 * time.Second
+)
+
+// poolConfig -> connection pool settings, zero values keep the database/sql defaults
+type poolConfig struct {
+	maxOpenConns    int
+	maxIdleConns    int
+	connMaxLifetime time.Duration
+	connMaxIdleTime time.Duration
+}
+
+// newPoolConfig -> pool settings of the env, lifetimes are durations like 30m
+func newPoolConfig(env Env) (config poolConfig, err error) {
+	if config.maxOpenConns, err = envInt("DBMaxOpenConns", env.DBMaxOpenConns); err != nil {
+		return config, err
+	}
+	if config.maxIdleConns, err = envInt("DBMaxIdleConns", env.DBMaxIdleConns); err != nil {
+		return config, err
+	}
+	if config.connMaxLifetime, err = envDuration("DBConnMaxLifetime", env.DBConnMaxLifetime); err != nil {
+		return config, err
+	}
+	config.connMaxIdleTime, err = envDuration("DBConnMaxIdleTime", env.DBConnMaxIdleTime)
+	return config, err
+}
+
+func envInt(name string, value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("%s: invalid number %q", name, value)
+	}
+	return parsed, nil
+}
+
+func envDuration(name string, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("%s: invalid duration %q", name, value)
+	}
+	return parsed, nil
+}
+
+// replicaEnv -> env of a replica, replicas share the credentials and database name of the primary
+// host is host[:port] for MySQL and PostgreSQL and the database file for SQLite
+func replicaEnv(env Env, host string) Env {
+	if env.DBDriver == DriverSQLite {
+		env.DBName = host
+		return env
+	}
+	env.DBHost = host
+	if replicaHost, port, err := net.SplitHostPort(host); err == nil {
+		env.DBHost, env.DBPort = replicaHost, port
+	}
+	return env
+}
+
+// useReplicas -> registers dbresolver with the replicas of DBReplicaHosts and applies the pool settings to every connection
+// reads outside of transactions go to the replicas, writes, locking reads and transactions stay on the primary
+func useReplicas(db *gorm.DB, env Env) (*replicaSet, error) {
+	pool, err := newPoolConfig(env)
+	if err != nil {
+		return nil, err
+	}
+	replicas := &replicaSet{unhealthy: map[gorm.ConnPool]bool{}}
+
+	config := dbresolver.Config{Policy: replicas}
+	for _, host := range strings.Split(env.DBReplicaHosts, ",") {
+		if host = strings.TrimSpace(host); host == "" {
+			continue
+		}
+		dialector, err := newDialector(replicaEnv(env, host))
+		if err != nil {
+			return nil, err
+		}
+		config.Replicas = append(config.Replicas, dialector)
+	}
+	if len(config.Replicas) > 0 {
+		primary, err := newDialector(env)
+		if err != nil {
+			return nil, err
+		}
+		config.Replicas = append(config.Replicas, primary)
+	}
+
+	// connections are handed out primary first and then the replicas in order, the last one being the primary fallback
+	var pools []gorm.ConnPool
+	resolver := dbresolver.Register(config)
+	_ = resolver.Call(func(connPool gorm.ConnPool) error {
+		pools = append(pools, connPool)
+		return nil
+	})
+	if pool.maxOpenConns > 0 {
+		resolver.SetMaxOpenConns(pool.maxOpenConns)
+	}
+	if pool.maxIdleConns > 0 {
+		resolver.SetMaxIdleConns(pool.maxIdleConns)
+	}
+	if pool.connMaxLifetime > 0 {
+		resolver.SetConnMaxLifetime(pool.connMaxLifetime)
+	}
+	if pool.connMaxIdleTime > 0 {
+		resolver.SetConnMaxIdleTime(pool.connMaxIdleTime)
+	}
+	if err := db.Use(resolver); err != nil {
+		return nil, err
+	}
+	if len(config.Replicas) > 0 {
+		replicas.pools = pools[1 : len(pools)-1]
+	}
+	return replicas, nil
+}
+
+// replicaSet -> read replicas with their health, reads go to the primary while no replica is healthy
+type replicaSet struct {
+	pools []gorm.ConnPool
+
+	mu        sync.RWMutex
+	unhealthy map[gorm.ConnPool]bool
+}
+
+// Resolve -> dbresolver policy picking a random healthy replica
+// the last connection is the primary, keeping it in the list also makes dbresolver ask the policy when there is a single replica
+func (s *replicaSet) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	replicas, primary := connPools[:len(connPools)-1], connPools[len(connPools)-1]
+
+	s.mu.RLock()
+	healthy := make([]gorm.ConnPool, 0, len(replicas))
+	for _, replica := range replicas {
+		if !s.unhealthy[replica] {
+			healthy = append(healthy, replica)
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(healthy) == 0 {
+		return primary
+	}
+	return healthy[rand.Intn(len(healthy))]
+}
+
+// setHealthy -> records the health of the replica, reporting whether it changed
+func (s *replicaSet) setHealthy(replica gorm.ConnPool, healthy bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.unhealthy[replica] == !healthy {
+		return false
+	}
+	s.unhealthy[replica] = !healthy
+	return true
+}
+
+// ReplicaHealthCheck -> pings the read replicas, an unreachable replica gets no reads until it answers again
+type ReplicaHealthCheck struct {
+	logger   Logger
+	replicas *replicaSet
+	stop     chan struct{}
+}
+
+// NewReplicaHealthCheck -> creates a new ReplicaHealthCheck
+func NewReplicaHealthCheck(logger Logger, database Database) ReplicaHealthCheck {
+	return ReplicaHealthCheck{
+		logger:   logger,
+		replicas: database.replicas,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start -> checks the replicas every interval until Stop is called
+func (c ReplicaHealthCheck) Start() {
+	if c.replicas == nil || len(c.replicas.pools) == 0 {
+		return
+	}
+	ticker := time.NewTicker(ReplicaHealthCheckInterval)
+	defer ticker.Stop()
+
+	c.check()
+	for {
+		select {
+		case <-ticker.C:
+			c.check()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// Stop -> stops the health checks
+func (c ReplicaHealthCheck) Stop() {
+	close(c.stop)
+}
+
+func (c ReplicaHealthCheck) check() {
+	for i, replica := range c.replicas.pools {
+		err := ping(replica)
+		if !c.replicas.setHealthy(replica, err == nil) {
+			continue
+		}
+		if err != nil {
+			c.logger.Zap.Errorf("Error [ReplicaHealthCheck] replica %d unreachable, reading from the primary: %s", i+1, err.Error())
+		} else {
+			c.logger.Zap.Infof("🗄️ replica %d reachable again", i+1)
+		}
+	}
+}
+
+func ping(connPool gorm.ConnPool) error {
+	pinger, ok := connPool.(interface{ PingContext(context.Context) error })
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), ReplicaPingTimeout)
+	defer cancel()
+	return pinger.PingContext(ctx)
+}
@@ -0,0 +1,181 @@
+package infrastructure
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestNewPoolConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     Env
+		want    poolConfig
+		wantErr bool
+	}{
+		{name: "defaults", env: Env{}},
+		{
+			name: "all settings",
+			env:  Env{DBMaxOpenConns: "20", DBMaxIdleConns: "5", DBConnMaxLifetime: "30m", DBConnMaxIdleTime: "90s"},
+			want: poolConfig{maxOpenConns: 20, maxIdleConns: 5, connMaxLifetime: 30 * time.Minute, connMaxIdleTime: 90 * time.Second},
+		},
+		{name: "invalid number", env: Env{DBMaxOpenConns: "many"}, wantErr: true},
+		{name: "negative number", env: Env{DBMaxIdleConns: "-1"}, wantErr: true},
+		{name: "duration without unit", env: Env{DBConnMaxLifetime: "30"}, wantErr: true},
+		{name: "negative duration", env: Env{DBConnMaxIdleTime: "-1m"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := newPoolConfig(test.env)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != test.want {
+				t.Errorf("config = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestReplicaEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		driver   string
+		host     string
+		wantHost string
+		wantPort string
+		wantName string
+	}{
+		{name: "host only", driver: DriverMySQL, host: "replica", wantHost: "replica", wantPort: "3306", wantName: "app"},
+		{name: "host and port", driver: DriverPostgres, host: "replica:5433", wantHost: "replica", wantPort: "5433", wantName: "app"},
+		{name: "sqlite file", driver: DriverSQLite, host: "replica.db", wantHost: "primary", wantPort: "3306", wantName: "replica.db"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			env := Env{DBDriver: test.driver, DBHost: "primary", DBPort: "3306", DBName: "app", DBUsername: "user"}
+			got := replicaEnv(env, test.host)
+			if got.DBHost != test.wantHost || got.DBPort != test.wantPort || got.DBName != test.wantName || got.DBUsername != "user" {
+				t.Errorf("replica env = %s:%s/%s as %s, want %s:%s/%s as user", got.DBHost, got.DBPort, got.DBName, got.DBUsername, test.wantHost, test.wantPort, test.wantName)
+			}
+		})
+	}
+}
+
+// openReplicatedSQLite -> sqlite primary with a replica, each holding a single row naming the database
+func openReplicatedSQLite(t *testing.T) (*gorm.DB, *replicaSet) {
+	t.Helper()
+	dir := t.TempDir()
+	env := Env{DBDriver: DriverSQLite, DBName: filepath.Join(dir, "primary.db"), DBReplicaHosts: " " + filepath.Join(dir, "replica.db") + ", "}
+
+	for _, name := range []string{"primary", "replica"} {
+		dialector, err := newDialector(replicaEnv(env, filepath.Join(dir, name+".db")))
+		if err != nil {
+			t.Fatal(err)
+		}
+		db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Exec("CREATE TABLE source (name TEXT)").Error; err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Exec("INSERT INTO source (name) VALUES (?)", name).Error; err != nil {
+			t.Fatal(err)
+		}
+		sqlDB, _ := db.DB()
+		sqlDB.Close()
+	}
+
+	dialector, err := newDialector(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replicas, err := useReplicas(db, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, replicas
+}
+
+func TestUseReplicas(t *testing.T) {
+	db, replicas := openReplicatedSQLite(t)
+	if len(replicas.pools) != 1 {
+		t.Fatalf("replicas = %d, want the blank hosts skipped", len(replicas.pools))
+	}
+	source := func(db *gorm.DB) string {
+		t.Helper()
+		var name string
+		if err := db.Table("source").Select("name").Scan(&name).Error; err != nil {
+			t.Fatal(err)
+		}
+		return name
+	}
+
+	if got := source(db); got != "replica" {
+		t.Errorf("read from %s, want the replica", got)
+	}
+	if got := source(Database{DB: db}.Primary()); got != "primary" {
+		t.Errorf("primary read from %s, want the primary", got)
+	}
+	trx := db.Begin()
+	if got := source(trx); got != "primary" {
+		t.Errorf("read in transaction from %s, want the primary", got)
+	}
+	trx.Rollback()
+
+	if !replicas.setHealthy(replicas.pools[0], false) {
+		t.Error("replica going down not reported as a change")
+	}
+	if replicas.setHealthy(replicas.pools[0], false) {
+		t.Error("replica staying down reported as a change")
+	}
+	if got := source(db); got != "primary" {
+		t.Errorf("read with the replica down from %s, want the primary", got)
+	}
+	if !replicas.setHealthy(replicas.pools[0], true) {
+		t.Error("replica coming back not reported as a change")
+	}
+	if got := source(db); got != "replica" {
+		t.Errorf("read with the replica back from %s, want the replica", got)
+	}
+}
+
+func TestUseReplicasWithoutReplicas(t *testing.T) {
+	env := Env{DBDriver: DriverSQLite, DBName: filepath.Join(t.TempDir(), "primary.db"), DBMaxOpenConns: "4"}
+	dialector, err := newDialector(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(dialector, &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replicas, err := useReplicas(db, env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replicas.pools) != 0 {
+		t.Errorf("replicas = %d, want none", len(replicas.pools))
+	}
+	// nothing to check, the health check returns right away instead of ticking forever
+	NewReplicaHealthCheck(Logger{}, Database{DB: db, replicas: replicas}).Start()
+
+	if _, err := useReplicas(db, Env{DBDriver: DriverSQLite, DBMaxIdleConns: "few"}); err == nil {
+		t.Error("invalid pool setting accepted")
+	}
+}
@@ -3,6 +3,7 @@ package infrastructure
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/getsentry/sentry-go"
 	sentrygin "github.com/getsentry/sentry-go/gin"
@@ -29,11 +30,19 @@ func NewRouter(env Env) Router {
 	}
 
 	httpRouter := gin.Default()
+	// forwarded headers are only trusted from the configured proxies, gin trusts every client by default
+	httpRouter.TrustedProxies = nil
+	for _, proxy := range strings.Split(env.TrustedProxies, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			httpRouter.TrustedProxies = append(httpRouter.TrustedProxies, proxy)
+		}
+	}
 
 	httpRouter.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"PUT", "PATCH", "GET", "POST", "OPTIONS", "DELETE"},
 		AllowHeaders:     []string{"*"},
+		ExposeHeaders:    []string{"ETag", "Idempotent-Replayed"},
 		AllowCredentials: true,
 	}))
 
@@ -0,0 +1,3 @@
+ALTER TABLE user
+  ADD COLUMN `email_verified` tinyint(1) NOT NULL DEFAULT 0 AFTER `address`,
+  ADD COLUMN `phone_verified` tinyint(1) NOT NULL DEFAULT 0 AFTER `email_verified`;
@@ -0,0 +1,11 @@
+ALTER TABLE Todo
+  DROP FOREIGN KEY `FK_Todo_organization`,
+  DROP INDEX `IDX_Todo_OrganizationID`,
+  DROP COLUMN `OrganizationID`;
+
+ALTER TABLE user
+  DROP FOREIGN KEY `FK_user_organization`,
+  DROP COLUMN `organization_id`;
+
+DROP TABLE IF EXISTS organization_member;
+DROP TABLE IF EXISTS organization;
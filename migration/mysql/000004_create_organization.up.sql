@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS organization (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `name` VARCHAR(255) NOT NULL,
+  `created_by_id` INT NULL,
+  `created_at` DATETIME NOT NULL,
+  `updated_at` DATETIME NULL,
+  `deleted_at` DATETIME NULL,
+  PRIMARY KEY (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS organization_member (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `organization_id` INT NOT NULL,
+  `user_id` INT NOT NULL,
+  `role` VARCHAR(50) NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  `updated_at` DATETIME NULL,
+  `deleted_at` DATETIME NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT `UQ_organization_member` UNIQUE (`organization_id`, `user_id`),
+  CONSTRAINT `FK_organization_member_organization` FOREIGN KEY (`organization_id`) REFERENCES organization (`id`) ON DELETE CASCADE,
+  CONSTRAINT `FK_organization_member_user` FOREIGN KEY (`user_id`) REFERENCES user (`id`) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE user
+  ADD COLUMN `organization_id` INT NULL AFTER `phone_verified`,
+  ADD CONSTRAINT `FK_user_organization` FOREIGN KEY (`organization_id`) REFERENCES organization (`id`) ON DELETE SET NULL;
+
+ALTER TABLE Todo
+  ADD COLUMN `OrganizationID` INT NULL AFTER `IsCompleted`,
+  ADD INDEX `IDX_Todo_OrganizationID` (`OrganizationID`),
+  ADD CONSTRAINT `FK_Todo_organization` FOREIGN KEY (`OrganizationID`) REFERENCES organization (`id`) ON DELETE CASCADE;
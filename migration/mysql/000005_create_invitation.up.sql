@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS invitation (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `email` VARCHAR(255) NOT NULL,
+  `role` VARCHAR(50) NOT NULL,
+  `token` VARCHAR(64) NOT NULL,
+  `organization_id` INT NULL,
+  `invited_by_id` INT NULL,
+  `expires_at` DATETIME NOT NULL,
+  `accepted_at` DATETIME NULL,
+  `sent_at` DATETIME NULL,
+  `created_at` DATETIME NOT NULL,
+  `updated_at` DATETIME NULL,
+  `deleted_at` DATETIME NULL,
+  PRIMARY KEY (id),
+  INDEX `IDX_invitation_email` (`email`),
+  CONSTRAINT `UQ_invitation_token` UNIQUE (`token`),
+  CONSTRAINT `FK_invitation_organization` FOREIGN KEY (`organization_id`) REFERENCES organization (`id`) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
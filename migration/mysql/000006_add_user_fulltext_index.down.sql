@@ -0,0 +1,2 @@
+ALTER TABLE user
+  DROP INDEX `IDX_user_search`;
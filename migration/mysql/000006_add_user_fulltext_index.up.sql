@@ -0,0 +1,2 @@
+ALTER TABLE user
+  ADD FULLTEXT INDEX `IDX_user_search` (`username`, `email`, `full_name`);
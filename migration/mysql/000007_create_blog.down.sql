@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS blog_categories;
+DROP TABLE IF EXISTS blog;
+DROP TABLE IF EXISTS category;
@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS category (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `title` VARCHAR(255) NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  `updated_at` DATETIME NULL,
+  `deleted_at` DATETIME NULL,
+  PRIMARY KEY (id)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS blog (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `title` VARCHAR(255) NOT NULL,
+  `content` LONGTEXT NOT NULL,
+  `thumbnail_image` VARCHAR(500) NULL,
+  `is_published` tinyint(1) NOT NULL DEFAULT 0,
+  `published_at` DATETIME NULL,
+  `created_by_id` INT NULL,
+  `updated_by_id` INT NULL,
+  `created_at` DATETIME NOT NULL,
+  `updated_at` DATETIME NULL,
+  `deleted_at` DATETIME NULL,
+  PRIMARY KEY (id),
+  INDEX `IDX_blog_published` (`is_published`, `published_at`),
+  CONSTRAINT `FK_blog_created_by` FOREIGN KEY (`created_by_id`) REFERENCES user (`id`) ON DELETE SET NULL,
+  CONSTRAINT `FK_blog_updated_by` FOREIGN KEY (`updated_by_id`) REFERENCES user (`id`) ON DELETE SET NULL
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS blog_categories (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `blog_id` INT NOT NULL,
+  `category_id` INT NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  `updated_at` DATETIME NULL,
+  `deleted_at` DATETIME NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT `UQ_blog_categories` UNIQUE (`blog_id`, `category_id`),
+  CONSTRAINT `FK_blog_categories_blog` FOREIGN KEY (`blog_id`) REFERENCES blog (`id`) ON DELETE CASCADE,
+  CONSTRAINT `FK_blog_categories_category` FOREIGN KEY (`category_id`) REFERENCES category (`id`) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS blog_slug;
+
+ALTER TABLE blog
+  DROP INDEX `IDX_blog_publish_at`,
+  DROP INDEX `UQ_blog_slug`,
+  DROP COLUMN `publish_at`,
+  DROP COLUMN `og_image`,
+  DROP COLUMN `meta_description`,
+  DROP COLUMN `slug`;
@@ -0,0 +1,24 @@
+ALTER TABLE blog
+  ADD COLUMN `slug` VARCHAR(255) NULL AFTER `title`,
+  ADD COLUMN `meta_description` VARCHAR(300) NULL AFTER `thumbnail_image`,
+  ADD COLUMN `og_image` VARCHAR(500) NULL AFTER `meta_description`,
+  ADD COLUMN `publish_at` DATETIME NULL AFTER `published_at`;
+
+UPDATE blog SET `slug` = CONCAT('blog-', `id`) WHERE `slug` IS NULL;
+
+ALTER TABLE blog
+  MODIFY COLUMN `slug` VARCHAR(255) NOT NULL,
+  ADD CONSTRAINT `UQ_blog_slug` UNIQUE (`slug`),
+  ADD INDEX `IDX_blog_publish_at` (`is_published`, `publish_at`);
+
+CREATE TABLE IF NOT EXISTS blog_slug (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `blog_id` INT NOT NULL,
+  `slug` VARCHAR(255) NOT NULL,
+  `created_at` DATETIME NOT NULL,
+  `updated_at` DATETIME NULL,
+  `deleted_at` DATETIME NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT `UQ_blog_slug_slug` UNIQUE (`slug`),
+  CONSTRAINT `FK_blog_slug_blog` FOREIGN KEY (`blog_id`) REFERENCES blog (`id`) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS comment (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `blog_id` INT NOT NULL,
+  `parent_id` INT NULL,
+  `root_id` INT NULL,
+  `depth` INT NOT NULL DEFAULT 0,
+  `user_id` INT NULL,
+  `content` TEXT NOT NULL,
+  `status` VARCHAR(20) NOT NULL DEFAULT 'pending',
+  `moderated_by_id` INT NULL,
+  `moderated_at` DATETIME NULL,
+  `created_at` DATETIME NOT NULL,
+  `updated_at` DATETIME NULL,
+  `deleted_at` DATETIME NULL,
+  PRIMARY KEY (id),
+  INDEX `IDX_comment_blog` (`blog_id`, `root_id`, `status`),
+  INDEX `IDX_comment_status` (`status`, `created_at`),
+  INDEX `IDX_comment_user` (`user_id`, `created_at`),
+  CONSTRAINT `FK_comment_blog` FOREIGN KEY (`blog_id`) REFERENCES blog (`id`) ON DELETE CASCADE,
+  CONSTRAINT `FK_comment_parent` FOREIGN KEY (`parent_id`) REFERENCES comment (`id`) ON DELETE CASCADE,
+  CONSTRAINT `FK_comment_user` FOREIGN KEY (`user_id`) REFERENCES user (`id`) ON DELETE SET NULL
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
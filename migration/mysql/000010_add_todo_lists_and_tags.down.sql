@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS TodoTag;
+DROP TABLE IF EXISTS Tag;
+
+ALTER TABLE Todo
+  DROP FOREIGN KEY `FK_Todo_TodoList`,
+  DROP INDEX `IDX_Todo_DueAt`,
+  DROP INDEX `IDX_Todo_ListID`,
+  DROP COLUMN `DueTimezone`,
+  DROP COLUMN `DueAt`,
+  DROP COLUMN `Priority`,
+  DROP COLUMN `ListID`;
+
+DROP TABLE IF EXISTS TodoList;
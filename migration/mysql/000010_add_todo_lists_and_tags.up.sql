@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS TodoList (
+  `ID` INT NOT NULL AUTO_INCREMENT,
+  `Name` VARCHAR(255) NOT NULL,
+  `Description` TEXT NULL,
+  `OrganizationID` INT NULL,
+  `CreateDateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `UpdateDateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `DeleteDateTime` timestamp NULL DEFAULT NULL,
+  `DeleteFlg` tinyint(4) NULL DEFAULT NULL,
+  PRIMARY KEY (ID),
+  INDEX `IDX_TodoList_OrganizationID` (`OrganizationID`),
+  CONSTRAINT `FK_TodoList_organization` FOREIGN KEY (`OrganizationID`) REFERENCES organization (`id`) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+ALTER TABLE Todo
+  ADD COLUMN `ListID` INT NULL AFTER `IsCompleted`,
+  ADD COLUMN `Priority` tinyint(4) NOT NULL DEFAULT 0 AFTER `ListID`,
+  ADD COLUMN `DueAt` DATETIME NULL AFTER `Priority`,
+  ADD COLUMN `DueTimezone` VARCHAR(64) NOT NULL DEFAULT '' AFTER `DueAt`,
+  ADD INDEX `IDX_Todo_ListID` (`ListID`),
+  ADD INDEX `IDX_Todo_DueAt` (`DueAt`),
+  ADD CONSTRAINT `FK_Todo_TodoList` FOREIGN KEY (`ListID`) REFERENCES TodoList (`ID`) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS Tag (
+  `ID` INT NOT NULL AUTO_INCREMENT,
+  `Name` VARCHAR(64) NOT NULL,
+  `OrganizationID` INT NULL,
+  `CreateDateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (ID),
+  CONSTRAINT `UQ_Tag_Name` UNIQUE (`OrganizationID`, `Name`),
+  CONSTRAINT `FK_Tag_organization` FOREIGN KEY (`OrganizationID`) REFERENCES organization (`id`) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS TodoTag (
+  `TodoID` INT NOT NULL,
+  `TagID` INT NOT NULL,
+  PRIMARY KEY (`TodoID`, `TagID`),
+  INDEX `IDX_TodoTag_TagID` (`TagID`),
+  CONSTRAINT `FK_TodoTag_Todo` FOREIGN KEY (`TodoID`) REFERENCES Todo (`ID`) ON DELETE CASCADE,
+  CONSTRAINT `FK_TodoTag_Tag` FOREIGN KEY (`TagID`) REFERENCES Tag (`ID`) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
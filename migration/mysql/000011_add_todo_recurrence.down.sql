@@ -0,0 +1,6 @@
+ALTER TABLE Todo
+  DROP FOREIGN KEY `FK_Todo_Recurrence`,
+  DROP INDEX `IDX_Todo_RecurrenceID`,
+  DROP COLUMN `RecurrenceID`,
+  DROP COLUMN `RecurrenceStart`,
+  DROP COLUMN `RRule`;
@@ -0,0 +1,6 @@
+ALTER TABLE Todo
+  ADD COLUMN `RRule` VARCHAR(500) NULL AFTER `DueTimezone`,
+  ADD COLUMN `RecurrenceStart` DATETIME NULL AFTER `RRule`,
+  ADD COLUMN `RecurrenceID` INT NULL AFTER `RecurrenceStart`,
+  ADD INDEX `IDX_Todo_RecurrenceID` (`RecurrenceID`),
+  ADD CONSTRAINT `FK_Todo_Recurrence` FOREIGN KEY (`RecurrenceID`) REFERENCES Todo (`ID`) ON DELETE SET NULL;
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS TodoShare;
+DROP TABLE IF EXISTS TodoCollaborator;
+
+ALTER TABLE Todo
+  DROP FOREIGN KEY `FK_Todo_Assignee`,
+  DROP FOREIGN KEY `FK_Todo_Owner`,
+  DROP INDEX `IDX_Todo_AssigneeID`,
+  DROP INDEX `IDX_Todo_OwnerID`,
+  DROP COLUMN `AssigneeID`,
+  DROP COLUMN `OwnerID`;
@@ -0,0 +1,34 @@
+ALTER TABLE Todo
+  ADD COLUMN `OwnerID` INT NULL AFTER `OrganizationID`,
+  ADD COLUMN `AssigneeID` INT NULL AFTER `OwnerID`,
+  ADD INDEX `IDX_Todo_OwnerID` (`OwnerID`),
+  ADD INDEX `IDX_Todo_AssigneeID` (`AssigneeID`),
+  ADD CONSTRAINT `FK_Todo_Owner` FOREIGN KEY (`OwnerID`) REFERENCES user (`id`) ON DELETE SET NULL,
+  ADD CONSTRAINT `FK_Todo_Assignee` FOREIGN KEY (`AssigneeID`) REFERENCES user (`id`) ON DELETE SET NULL;
+
+CREATE TABLE IF NOT EXISTS TodoCollaborator (
+  `TodoID` INT NOT NULL,
+  `UserID` INT NOT NULL,
+  `Permission` VARCHAR(20) NOT NULL,
+  `CreateDateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`TodoID`, `UserID`),
+  INDEX `IDX_TodoCollaborator_UserID` (`UserID`),
+  CONSTRAINT `FK_TodoCollaborator_Todo` FOREIGN KEY (`TodoID`) REFERENCES Todo (`ID`) ON DELETE CASCADE,
+  CONSTRAINT `FK_TodoCollaborator_User` FOREIGN KEY (`UserID`) REFERENCES user (`id`) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+
+CREATE TABLE IF NOT EXISTS TodoShare (
+  `ID` INT NOT NULL AUTO_INCREMENT,
+  `TodoID` INT NOT NULL,
+  `Token` VARCHAR(64) NOT NULL,
+  `Permission` VARCHAR(20) NOT NULL,
+  `CreatedByID` INT NOT NULL,
+  `ExpiresAt` DATETIME NULL,
+  `CreateDateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `UpdateDateTime` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `DeleteDateTime` timestamp NULL DEFAULT NULL,
+  `DeleteFlg` tinyint(4) NULL DEFAULT NULL,
+  PRIMARY KEY (ID),
+  CONSTRAINT `UQ_TodoShare_Token` UNIQUE (`Token`),
+  CONSTRAINT `FK_TodoShare_Todo` FOREIGN KEY (`TodoID`) REFERENCES Todo (`ID`) ON DELETE CASCADE
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,5 @@
+ALTER TABLE Todo
+  DROP COLUMN `Version`;
+
+ALTER TABLE user
+  DROP COLUMN `version`;
@@ -0,0 +1,5 @@
+ALTER TABLE user
+  ADD COLUMN `version` INT NOT NULL DEFAULT 1 AFTER `organization_id`;
+
+ALTER TABLE Todo
+  ADD COLUMN `Version` INT NOT NULL DEFAULT 1 AFTER `AssigneeID`;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS idempotency_key (
+  `id` INT NOT NULL AUTO_INCREMENT,
+  `user_id` INT NOT NULL,
+  `key` VARCHAR(255) NOT NULL,
+  `fingerprint` CHAR(64) NOT NULL,
+  `status` INT NOT NULL DEFAULT 0,
+  `content_type` VARCHAR(255) NULL,
+  `body` MEDIUMBLOB NULL,
+  `created_at` DATETIME NOT NULL,
+  `expires_at` DATETIME NOT NULL,
+  PRIMARY KEY (id),
+  INDEX `IDX_idempotency_key_expires_at` (`expires_at`),
+  CONSTRAINT `UQ_idempotency_key_user_key` UNIQUE (`user_id`, `key`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,263 @@
+CREATE TABLE IF NOT EXISTS organization (
+  "id" SERIAL NOT NULL,
+  "name" VARCHAR(255) NOT NULL,
+  "created_by_id" INTEGER NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NULL,
+  "deleted_at" TIMESTAMP NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "user" (
+  "id" SERIAL NOT NULL,
+  "username" VARCHAR(100) NOT NULL,
+  "email" VARCHAR(255) NOT NULL,
+  "password" VARCHAR(255) NOT NULL,
+  "phone" VARCHAR(15) NOT NULL,
+  "full_name" VARCHAR(255) NOT NULL,
+  "firebase_uid" VARCHAR(255) NULL,
+  "address" VARCHAR(15) NOT NULL,
+  "email_verified" BOOLEAN NOT NULL DEFAULT FALSE,
+  "phone_verified" BOOLEAN NOT NULL DEFAULT FALSE,
+  "organization_id" INTEGER NULL,
+  "version" INTEGER NOT NULL DEFAULT 1,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NULL,
+  "deleted_at" TIMESTAMP NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "UQ_user_email" UNIQUE ("email"),
+  CONSTRAINT "UQ_user_username" UNIQUE ("username"),
+  CONSTRAINT "UQ_user_phone" UNIQUE ("phone"),
+  CONSTRAINT "UQ_user_firebase_uid" UNIQUE ("firebase_uid"),
+  CONSTRAINT "FK_user_organization" FOREIGN KEY ("organization_id") REFERENCES organization ("id") ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS organization_member (
+  "id" SERIAL NOT NULL,
+  "organization_id" INTEGER NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "role" VARCHAR(50) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NULL,
+  "deleted_at" TIMESTAMP NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "UQ_organization_member" UNIQUE ("organization_id", "user_id"),
+  CONSTRAINT "FK_organization_member_organization" FOREIGN KEY ("organization_id") REFERENCES organization ("id") ON DELETE CASCADE,
+  CONSTRAINT "FK_organization_member_user" FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS invitation (
+  "id" SERIAL NOT NULL,
+  "email" VARCHAR(255) NOT NULL,
+  "role" VARCHAR(50) NOT NULL,
+  "token" VARCHAR(64) NOT NULL,
+  "organization_id" INTEGER NULL,
+  "invited_by_id" INTEGER NULL,
+  "expires_at" TIMESTAMP NOT NULL,
+  "accepted_at" TIMESTAMP NULL,
+  "sent_at" TIMESTAMP NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NULL,
+  "deleted_at" TIMESTAMP NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "UQ_invitation_token" UNIQUE ("token"),
+  CONSTRAINT "FK_invitation_organization" FOREIGN KEY ("organization_id") REFERENCES organization ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "IDX_invitation_email" ON invitation ("email");
+
+CREATE TABLE IF NOT EXISTS "TodoList" (
+  "ID" SERIAL NOT NULL,
+  "Name" VARCHAR(255) NOT NULL,
+  "Description" TEXT NULL,
+  "OrganizationID" INTEGER NULL,
+  "CreateDateTime" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "UpdateDateTime" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "DeleteDateTime" TIMESTAMP NULL DEFAULT NULL,
+  "DeleteFlg" BOOLEAN NULL DEFAULT NULL,
+  PRIMARY KEY ("ID"),
+  CONSTRAINT "FK_TodoList_organization" FOREIGN KEY ("OrganizationID") REFERENCES organization ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "IDX_TodoList_OrganizationID" ON "TodoList" ("OrganizationID");
+
+CREATE TABLE IF NOT EXISTS "Todo" (
+  "ID" SERIAL NOT NULL,
+  "Task" VARCHAR(255) NOT NULL,
+  "IsCompleted" BOOLEAN DEFAULT FALSE,
+  "ListID" INTEGER NULL,
+  "Priority" SMALLINT NOT NULL DEFAULT 0,
+  "DueAt" TIMESTAMP NULL,
+  "DueTimezone" VARCHAR(64) NOT NULL DEFAULT '',
+  "RRule" VARCHAR(500) NULL,
+  "RecurrenceStart" TIMESTAMP NULL,
+  "RecurrenceID" INTEGER NULL,
+  "OrganizationID" INTEGER NULL,
+  "OwnerID" INTEGER NULL,
+  "AssigneeID" INTEGER NULL,
+  "Version" INTEGER NOT NULL DEFAULT 1,
+  "CreateDateTime" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "UpdateDateTime" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "DeleteDateTime" TIMESTAMP NULL DEFAULT NULL,
+  "DeleteFlg" BOOLEAN NULL DEFAULT NULL,
+  PRIMARY KEY ("ID"),
+  CONSTRAINT "FK_Todo_organization" FOREIGN KEY ("OrganizationID") REFERENCES organization ("id") ON DELETE CASCADE,
+  CONSTRAINT "FK_Todo_TodoList" FOREIGN KEY ("ListID") REFERENCES "TodoList" ("ID") ON DELETE SET NULL,
+  CONSTRAINT "FK_Todo_Recurrence" FOREIGN KEY ("RecurrenceID") REFERENCES "Todo" ("ID") ON DELETE SET NULL,
+  CONSTRAINT "FK_Todo_Owner" FOREIGN KEY ("OwnerID") REFERENCES "user" ("id") ON DELETE SET NULL,
+  CONSTRAINT "FK_Todo_Assignee" FOREIGN KEY ("AssigneeID") REFERENCES "user" ("id") ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS "IDX_Todo_OrganizationID" ON "Todo" ("OrganizationID");
+CREATE INDEX IF NOT EXISTS "IDX_Todo_ListID" ON "Todo" ("ListID");
+CREATE INDEX IF NOT EXISTS "IDX_Todo_DueAt" ON "Todo" ("DueAt");
+CREATE INDEX IF NOT EXISTS "IDX_Todo_RecurrenceID" ON "Todo" ("RecurrenceID");
+CREATE INDEX IF NOT EXISTS "IDX_Todo_OwnerID" ON "Todo" ("OwnerID");
+CREATE INDEX IF NOT EXISTS "IDX_Todo_AssigneeID" ON "Todo" ("AssigneeID");
+
+CREATE TABLE IF NOT EXISTS "Tag" (
+  "ID" SERIAL NOT NULL,
+  "Name" VARCHAR(64) NOT NULL,
+  "OrganizationID" INTEGER NULL,
+  "CreateDateTime" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("ID"),
+  CONSTRAINT "UQ_Tag_Name" UNIQUE ("OrganizationID", "Name"),
+  CONSTRAINT "FK_Tag_organization" FOREIGN KEY ("OrganizationID") REFERENCES organization ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "TodoTag" (
+  "TodoID" INTEGER NOT NULL,
+  "TagID" INTEGER NOT NULL,
+  PRIMARY KEY ("TodoID", "TagID"),
+  CONSTRAINT "FK_TodoTag_Todo" FOREIGN KEY ("TodoID") REFERENCES "Todo" ("ID") ON DELETE CASCADE,
+  CONSTRAINT "FK_TodoTag_Tag" FOREIGN KEY ("TagID") REFERENCES "Tag" ("ID") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "IDX_TodoTag_TagID" ON "TodoTag" ("TagID");
+
+CREATE TABLE IF NOT EXISTS "TodoCollaborator" (
+  "TodoID" INTEGER NOT NULL,
+  "UserID" INTEGER NOT NULL,
+  "Permission" VARCHAR(20) NOT NULL,
+  "CreateDateTime" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY ("TodoID", "UserID"),
+  CONSTRAINT "FK_TodoCollaborator_Todo" FOREIGN KEY ("TodoID") REFERENCES "Todo" ("ID") ON DELETE CASCADE,
+  CONSTRAINT "FK_TodoCollaborator_User" FOREIGN KEY ("UserID") REFERENCES "user" ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS "IDX_TodoCollaborator_UserID" ON "TodoCollaborator" ("UserID");
+
+CREATE TABLE IF NOT EXISTS "TodoShare" (
+  "ID" SERIAL NOT NULL,
+  "TodoID" INTEGER NOT NULL,
+  "Token" VARCHAR(64) NOT NULL,
+  "Permission" VARCHAR(20) NOT NULL,
+  "CreatedByID" INTEGER NOT NULL,
+  "ExpiresAt" TIMESTAMP NULL,
+  "CreateDateTime" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "UpdateDateTime" TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "DeleteDateTime" TIMESTAMP NULL DEFAULT NULL,
+  "DeleteFlg" BOOLEAN NULL DEFAULT NULL,
+  PRIMARY KEY ("ID"),
+  CONSTRAINT "UQ_TodoShare_Token" UNIQUE ("Token"),
+  CONSTRAINT "FK_TodoShare_Todo" FOREIGN KEY ("TodoID") REFERENCES "Todo" ("ID") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS category (
+  "id" SERIAL NOT NULL,
+  "title" VARCHAR(255) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NULL,
+  "deleted_at" TIMESTAMP NULL,
+  PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS blog (
+  "id" SERIAL NOT NULL,
+  "title" VARCHAR(255) NOT NULL,
+  "slug" VARCHAR(255) NOT NULL,
+  "content" TEXT NOT NULL,
+  "thumbnail_image" VARCHAR(500) NULL,
+  "meta_description" VARCHAR(300) NULL,
+  "og_image" VARCHAR(500) NULL,
+  "is_published" BOOLEAN NOT NULL DEFAULT FALSE,
+  "published_at" TIMESTAMP NULL,
+  "publish_at" TIMESTAMP NULL,
+  "created_by_id" INTEGER NULL,
+  "updated_by_id" INTEGER NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NULL,
+  "deleted_at" TIMESTAMP NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "UQ_blog_slug" UNIQUE ("slug"),
+  CONSTRAINT "FK_blog_created_by" FOREIGN KEY ("created_by_id") REFERENCES "user" ("id") ON DELETE SET NULL,
+  CONSTRAINT "FK_blog_updated_by" FOREIGN KEY ("updated_by_id") REFERENCES "user" ("id") ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS "IDX_blog_published" ON blog ("is_published", "published_at");
+CREATE INDEX IF NOT EXISTS "IDX_blog_publish_at" ON blog ("is_published", "publish_at");
+
+CREATE TABLE IF NOT EXISTS blog_categories (
+  "id" SERIAL NOT NULL,
+  "blog_id" INTEGER NOT NULL,
+  "category_id" INTEGER NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NULL,
+  "deleted_at" TIMESTAMP NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "UQ_blog_categories" UNIQUE ("blog_id", "category_id"),
+  CONSTRAINT "FK_blog_categories_blog" FOREIGN KEY ("blog_id") REFERENCES blog ("id") ON DELETE CASCADE,
+  CONSTRAINT "FK_blog_categories_category" FOREIGN KEY ("category_id") REFERENCES category ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS blog_slug (
+  "id" SERIAL NOT NULL,
+  "blog_id" INTEGER NOT NULL,
+  "slug" VARCHAR(255) NOT NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NULL,
+  "deleted_at" TIMESTAMP NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "UQ_blog_slug_slug" UNIQUE ("slug"),
+  CONSTRAINT "FK_blog_slug_blog" FOREIGN KEY ("blog_id") REFERENCES blog ("id") ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS comment (
+  "id" SERIAL NOT NULL,
+  "blog_id" INTEGER NOT NULL,
+  "parent_id" INTEGER NULL,
+  "root_id" INTEGER NULL,
+  "depth" INTEGER NOT NULL DEFAULT 0,
+  "user_id" INTEGER NULL,
+  "content" TEXT NOT NULL,
+  "status" VARCHAR(20) NOT NULL DEFAULT 'pending',
+  "moderated_by_id" INTEGER NULL,
+  "moderated_at" TIMESTAMP NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "updated_at" TIMESTAMP NULL,
+  "deleted_at" TIMESTAMP NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "FK_comment_blog" FOREIGN KEY ("blog_id") REFERENCES blog ("id") ON DELETE CASCADE,
+  CONSTRAINT "FK_comment_parent" FOREIGN KEY ("parent_id") REFERENCES comment ("id") ON DELETE CASCADE,
+  CONSTRAINT "FK_comment_user" FOREIGN KEY ("user_id") REFERENCES "user" ("id") ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS "IDX_comment_blog" ON comment ("blog_id", "root_id", "status");
+CREATE INDEX IF NOT EXISTS "IDX_comment_status" ON comment ("status", "created_at");
+CREATE INDEX IF NOT EXISTS "IDX_comment_user" ON comment ("user_id", "created_at");
+
+CREATE TABLE IF NOT EXISTS idempotency_key (
+  "id" SERIAL NOT NULL,
+  "user_id" INTEGER NOT NULL,
+  "key" VARCHAR(255) NOT NULL,
+  "fingerprint" CHAR(64) NOT NULL,
+  "status" INTEGER NOT NULL DEFAULT 0,
+  "content_type" VARCHAR(255) NULL,
+  "body" BYTEA NULL,
+  "created_at" TIMESTAMP NOT NULL,
+  "expires_at" TIMESTAMP NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "UQ_idempotency_key_user_key" UNIQUE ("user_id", "key")
+);
+
+CREATE INDEX IF NOT EXISTS "IDX_idempotency_key_expires_at" ON idempotency_key ("expires_at");
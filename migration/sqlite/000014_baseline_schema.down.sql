@@ -0,0 +1,16 @@
+DROP TABLE IF EXISTS idempotency_key;
+DROP TABLE IF EXISTS comment;
+DROP TABLE IF EXISTS blog_slug;
+DROP TABLE IF EXISTS blog_categories;
+DROP TABLE IF EXISTS blog;
+DROP TABLE IF EXISTS category;
+DROP TABLE IF EXISTS "TodoShare";
+DROP TABLE IF EXISTS "TodoCollaborator";
+DROP TABLE IF EXISTS "TodoTag";
+DROP TABLE IF EXISTS "Tag";
+DROP TABLE IF EXISTS "Todo";
+DROP TABLE IF EXISTS "TodoList";
+DROP TABLE IF EXISTS invitation;
+DROP TABLE IF EXISTS organization_member;
+DROP TABLE IF EXISTS "user";
+DROP TABLE IF EXISTS organization;
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"boilerplate-api/utils"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,6 +15,11 @@ type Base struct {
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"deleted_at"` //add soft delete in gorm
 }
 
+// PageCursor -> position of the record in keyset pagination
+func (b Base) PageCursor() utils.Cursor {
+	return utils.Cursor{CreatedAt: b.CreatedAt, ID: b.ID}
+}
+
 type BinaryBase struct {
 	ID        BINARY16       `json:"id"`
 	CreatedAt time.Time      `json:"created_at"`
@@ -28,3 +34,8 @@ type BaseModel struct {
 	DeleteDateTime gorm.DeletedAt `gorm:"column:DeleteDateTime" json:"deleted_datetime"`
 	DeleteFlg      bool           `gorm:"column:DeleteFlg;softDelete:flag" json:"is_deleted"`
 }
+
+// PageCursor -> position of the record in keyset pagination
+func (b BaseModel) PageCursor() utils.Cursor {
+	return utils.Cursor{CreatedAt: b.CreateDateTime, ID: b.ID}
+}
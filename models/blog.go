@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type Category struct {
 	Base
 	Title string `json:"title" validate:"required"`
@@ -36,28 +38,59 @@ func (m BlogCategories) ToMap() map[string]interface{} {
 
 type Blog struct {
 	Base
-	Title          int64  `json:"title" validate:"required"`
-	Content        string `json:"content" validate:"required"`
-	ThumbnailImage string `json:"thumbnail_image" validate:"required"`
-	IsPublished    bool   `json:"is_published" validate:"required"`
-	CreatedById    int64  `json:"created_by_id" validate:"required"`
-	UpdatedById    int64  `json:"updated_by_id" validate:"required"`
-	CreatedBy      User   `json:"created_by"`
-	UpdatedBy      User   `json:"updated_by" `
+	Title           string     `json:"title" validate:"required"`
+	Slug            string     `json:"slug"`
+	Content         string     `json:"content" validate:"required"`
+	ThumbnailImage  string     `json:"thumbnail_image"`
+	MetaDescription string     `json:"meta_description" validate:"max=300"`
+	OGImage         string     `gorm:"column:og_image" json:"og_image"`
+	IsPublished     bool       `json:"is_published"`
+	PublishedAt     *time.Time `json:"published_at"`
+	PublishAt       *time.Time `json:"publish_at"`
+	CreatedById     *int64     `json:"created_by_id"`
+	UpdatedById     *int64     `json:"updated_by_id"`
+	CreatedBy       *User      `gorm:"foreignKey:CreatedById" json:"created_by,omitempty"`
+	UpdatedBy       *User      `gorm:"foreignKey:UpdatedById" json:"updated_by,omitempty"`
+	Categories      []Category `gorm:"many2many:blog_categories;joinForeignKey:BlogId;joinReferences:CategoryId" json:"categories,omitempty"`
+
+	// CategoryIds -> categories to attach when creating or updating
+	CategoryIds []int64 `gorm:"-" json:"category_ids,omitempty"`
 }
 
 func (m *Blog) TableName() string {
-	return "blog_categories"
+	return "blog"
+}
+
+// IsScheduled -> whether the blog waits for the scheduler to be published
+func (m Blog) IsScheduled() bool {
+	return !m.IsPublished && m.PublishAt != nil
 }
 
 // ToMap convert User to map
 func (m Blog) ToMap() map[string]interface{} {
 	return map[string]interface{}{
-		"title":           m.Title,
-		"content":         m.Content,
-		"thumbnail_image": m.ThumbnailImage,
-		"is_published":    m.IsPublished,
-		"created_by":      m.CreatedBy,
-		"updated_by":      m.UpdatedBy,
+		"title":            m.Title,
+		"slug":             m.Slug,
+		"content":          m.Content,
+		"thumbnail_image":  m.ThumbnailImage,
+		"meta_description": m.MetaDescription,
+		"og_image":         m.OGImage,
+		"is_published":     m.IsPublished,
+		"published_at":     m.PublishedAt,
+		"publish_at":       m.PublishAt,
+		"created_by":       m.CreatedBy,
+		"updated_by":       m.UpdatedBy,
+		"categories":       m.Categories,
 	}
 }
+
+// BlogSlug -> previous slugs of a blog, kept to redirect old urls
+type BlogSlug struct {
+	Base
+	BlogId int64  `json:"blog_id"`
+	Slug   string `json:"slug"`
+}
+
+func (m *BlogSlug) TableName() string {
+	return "blog_slug"
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+const (
+	// Comment statuses
+	CommentStatusPending  = "pending"
+	CommentStatusApproved = "approved"
+	CommentStatusRejected = "rejected"
+)
+
+// Comment -> comment on a blog, replies point to their parent and to the root of the thread
+type Comment struct {
+	Base
+	BlogId        int64      `json:"blog_id"`
+	ParentId      *int64     `json:"parent_id"`
+	RootId        *int64     `json:"root_id"`
+	Depth         int        `json:"depth"`
+	UserId        *int64     `json:"user_id"`
+	Content       string     `json:"content" validate:"required,max=5000"`
+	Status        string     `json:"status"`
+	ModeratedById *int64     `json:"moderated_by_id,omitempty"`
+	ModeratedAt   *time.Time `json:"moderated_at,omitempty"`
+	User          *User      `gorm:"foreignKey:UserId" json:"user,omitempty"`
+
+	Replies []*Comment `gorm:"-" json:"replies,omitempty"`
+}
+
+// TableName gives table name of model
+func (m *Comment) TableName() string {
+	return "comment"
+}
+
+// ThreadID -> id of the root comment of the thread
+func (m Comment) ThreadID() int64 {
+	if m.RootId != nil {
+		return *m.RootId
+	}
+	return m.ID
+}
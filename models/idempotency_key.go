@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// IdempotencyKey -> response stored for a request sent with an Idempotency-Key header
+// status is 0 while the first request with the key is still being processed
+type IdempotencyKey struct {
+	ID          int64     `gorm:"primaryKey" json:"id"`
+	UserID      int64     `json:"user_id"`
+	Key         string    `json:"key"`
+	Fingerprint string    `json:"fingerprint"`
+	Status      int       `json:"status"`
+	ContentType string    `json:"content_type"`
+	Body        []byte    `json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// TableName gives table name of model
+func (m *IdempotencyKey) TableName() string {
+	return "idempotency_key"
+}
+
+// IsCompleted checks if the response of the request has been stored
+func (m IdempotencyKey) IsCompleted() bool {
+	return m.Status != 0
+}
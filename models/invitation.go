@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Invitation -> pending invitation for a new user to sign up
+type Invitation struct {
+	Base
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	Token          string     `json:"-"`
+	OrganizationID *int64     `json:"organization_id"`
+	InvitedByID    int64      `json:"invited_by_id"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	SentAt         *time.Time `json:"sent_at"`
+}
+
+// TableName gives table name of model
+func (m *Invitation) TableName() string {
+	return "invitation"
+}
+
+// IsExpired checks if the invitation can no longer be accepted
+func (m Invitation) IsExpired() bool {
+	return time.Now().After(m.ExpiresAt)
+}
+
+// ToMap convert Invitation to map
+func (m Invitation) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":              m.ID,
+		"email":           m.Email,
+		"role":            m.Role,
+		"organization_id": m.OrganizationID,
+		"expires_at":      m.ExpiresAt,
+		"sent_at":         m.SentAt,
+		"created_at":      m.CreatedAt,
+	}
+}
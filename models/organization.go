@@ -0,0 +1,35 @@
+package models
+
+// Organization -> tenant owning users and data
+type Organization struct {
+	Base
+	Name        string `json:"name" validate:"required"`
+	CreatedByID int64  `json:"created_by_id"`
+}
+
+// TableName gives table name of model
+func (m *Organization) TableName() string {
+	return "organization"
+}
+
+// ToMap convert Organization to map
+func (m Organization) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"id":   m.ID,
+		"name": m.Name,
+	}
+}
+
+// OrganizationMember -> membership of a user in an organization
+type OrganizationMember struct {
+	Base
+	OrganizationID int64         `json:"organization_id"`
+	UserID         int64         `json:"user_id"`
+	Role           string        `json:"role"`
+	Organization   *Organization `json:"organization,omitempty"`
+}
+
+// TableName gives table name of model
+func (m *OrganizationMember) TableName() string {
+	return "organization_member"
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Tag -> free-form label of todos, unique by name within an organization
+type Tag struct {
+	ID             int64     `gorm:"primaryKey;column:ID" json:"id"`
+	Name           string    `gorm:"column:Name" json:"name"`
+	CreateDateTime time.Time `gorm:"autoCreateTime;column:CreateDateTime" json:"created_datetime"`
+
+	OrganizationID *int64 `gorm:"column:OrganizationID" json:"organization_id"`
+}
+
+// TableName  -> returns table name of model
+func (c Tag) TableName() string {
+	return "Tag"
+}
+
+// TodoTag -> join table of todos and tags
+type TodoTag struct {
+	TodoID int64 `gorm:"primaryKey;column:TodoID" json:"todo_id"`
+	TagID  int64 `gorm:"primaryKey;column:TagID" json:"tag_id"`
+}
+
+// TableName  -> returns table name of model
+func (c TodoTag) TableName() string {
+	return "TodoTag"
+}
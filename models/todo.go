@@ -1,13 +1,75 @@
 package models
 
+import (
+	"boilerplate-api/utils"
+	"time"
+)
+
+const (
+	// Todo priority levels
+	TodoPriorityNone   = 0
+	TodoPriorityLow    = 1
+	TodoPriorityMedium = 2
+	TodoPriorityHigh   = 3
+	TodoPriorityUrgent = 4
+)
+
 // Todo -> DB model
 type Todo struct {
 	BaseModel
-	Task        string `gorm:"column:Task" json:"task"`
-	IsCompleted *bool  `gorm:"column:IsCompleted" json:"is_completed"`
+	Task        string     `gorm:"column:Task" json:"task"`
+	IsCompleted *bool      `gorm:"column:IsCompleted" json:"is_completed"`
+	ListID      *int64     `gorm:"column:ListID" json:"list_id"`
+	Priority    *int       `gorm:"column:Priority" json:"priority"`
+	DueAt       *time.Time `gorm:"column:DueAt" json:"due_at"`
+	DueTimezone string     `gorm:"column:DueTimezone" json:"due_timezone"`
+
+	// RRule -> RFC 5545 recurrence rule (e.g. `FREQ=WEEKLY;BYDAY=MO`), empty to stop recurring
+	RRule *string `gorm:"column:RRule" json:"rrule"`
+	// RecurrenceStart -> DTSTART of the rule, the due date the rule was set on
+	RecurrenceStart *time.Time `gorm:"column:RecurrenceStart" json:"recurrence_start"`
+	// RecurrenceID -> first todo of the series the occurrence belongs to
+	RecurrenceID *int64 `gorm:"column:RecurrenceID" json:"recurrence_id"`
+
+	OrganizationID *int64 `gorm:"column:OrganizationID" json:"organization_id"`
+	// OwnerID -> user who created the todo, todos without owner are shared with the whole organization
+	OwnerID    *int64 `gorm:"column:OwnerID" json:"owner_id"`
+	AssigneeID *int64 `gorm:"column:AssigneeID" json:"assignee_id"`
+	// Version -> incremented on every change, sent as the ETag of the todo
+	Version int64 `gorm:"column:Version" json:"version"`
+
+	// DueDate -> due date in the due timezone (`2006-01-02` or `2006-01-02T15:04`), alternative to due_at
+	DueDate    string   `gorm:"-" json:"due_date,omitempty"`
+	DueAtLocal string   `gorm:"-" json:"due_at_local,omitempty"`
+	Tags       []string `gorm:"-" json:"tags"`
 }
 
 // TableName  -> returns table name of model
 func (c Todo) TableName() string {
 	return "Todo"
 }
+
+// IsRecurring -> whether the todo has a recurrence rule
+func (c Todo) IsRecurring() bool {
+	return c.RRule != nil && *c.RRule != ""
+}
+
+// IsDone -> whether the todo is completed
+func (c Todo) IsDone() bool {
+	return c.IsCompleted != nil && *c.IsCompleted
+}
+
+// SetDueAtLocal -> formats the due date in the due timezone
+func (c *Todo) SetDueAtLocal() {
+	c.DueAtLocal = ""
+	if c.DueAt == nil {
+		return
+	}
+	c.DueAtLocal = utils.FormatInTimezone(*c.DueAt, c.DueTimezone)
+}
+
+// TodoOccurrence -> upcoming occurrence of a recurring todo
+type TodoOccurrence struct {
+	DueAt      time.Time `json:"due_at"`
+	DueAtLocal string    `json:"due_at_local"`
+}
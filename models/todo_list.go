@@ -0,0 +1,15 @@
+package models
+
+// TodoList -> list (project) grouping todos
+type TodoList struct {
+	BaseModel
+	Name        string `gorm:"column:Name" json:"name" validate:"required,max=255"`
+	Description string `gorm:"column:Description" json:"description"`
+
+	OrganizationID *int64 `gorm:"column:OrganizationID" json:"organization_id"`
+}
+
+// TableName  -> returns table name of model
+func (c TodoList) TableName() string {
+	return "TodoList"
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+const (
+	// Permissions on shared todos
+	TodoPermissionView  = "view"
+	TodoPermissionEdit  = "edit"
+	TodoPermissionOwner = "owner"
+)
+
+// TodoPermissions -> permissions that can be granted to collaborators and share links
+var TodoPermissions = []string{TodoPermissionView, TodoPermissionEdit}
+
+// TodoCollaborator -> user a todo is shared with
+type TodoCollaborator struct {
+	TodoID         int64     `gorm:"primaryKey;column:TodoID" json:"todo_id"`
+	UserID         int64     `gorm:"primaryKey;column:UserID" json:"user_id"`
+	Permission     string    `gorm:"column:Permission" json:"permission"`
+	CreateDateTime time.Time `gorm:"autoCreateTime;column:CreateDateTime" json:"created_datetime"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// TableName  -> returns table name of model
+func (c TodoCollaborator) TableName() string {
+	return "TodoCollaborator"
+}
+
+// TodoShare -> share link granting the permission to whoever opens it
+type TodoShare struct {
+	BaseModel
+	TodoID      int64      `gorm:"column:TodoID" json:"todo_id"`
+	Token       string     `gorm:"column:Token" json:"token"`
+	Permission  string     `gorm:"column:Permission" json:"permission"`
+	CreatedByID int64      `gorm:"column:CreatedByID" json:"created_by_id"`
+	ExpiresAt   *time.Time `gorm:"column:ExpiresAt" json:"expires_at"`
+
+	URL string `gorm:"-" json:"url,omitempty"`
+}
+
+// TableName  -> returns table name of model
+func (c TodoShare) TableName() string {
+	return "TodoShare"
+}
+
+// IsExpired checks if the share link can no longer be used
+func (c TodoShare) IsExpired() bool {
+	return c.ExpiresAt != nil && time.Now().After(*c.ExpiresAt)
+}
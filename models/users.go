@@ -10,6 +10,19 @@ type User struct {
 	FullName    string `json:"full_name" validate:"required"`
 	Address     string `json:"address" validate:"required"`
 	Password    string `json:"-" validate:"required"`
+
+	EmailVerified bool `json:"email_verified"`
+	PhoneVerified bool `json:"phone_verified"`
+
+	OrganizationID *int64               `json:"organization_id"`
+	Memberships    []OrganizationMember `gorm:"foreignKey:UserID" json:"memberships,omitempty"`
+
+	// Version -> incremented on every change, sent as the ETag of the user
+	Version int64 `json:"version"`
+
+	// set on keyword search only
+	Relevance  float64           `gorm:"->;-:migration" json:"relevance,omitempty"`
+	Highlights map[string]string `gorm:"-" json:"highlights,omitempty"`
 }
 
 // TableName gives table name of model
@@ -26,6 +39,16 @@ type UserToUpdate struct {
 	Address  string
 }
 
+// MembershipRole returns the user's role in the organization
+func (m User) MembershipRole(organizationID int64) string {
+	for _, membership := range m.Memberships {
+		if membership.OrganizationID == organizationID {
+			return membership.Role
+		}
+	}
+	return ""
+}
+
 // ToMap convert User to map
 func (m User) ToMap() map[string]interface{} {
 	return map[string]interface{}{
@@ -35,6 +58,12 @@ func (m User) ToMap() map[string]interface{} {
 		"phone":     m.Phone,
 		"full_name": m.FullName,
 		"address":   m.Address,
+
+		"email_verified": m.EmailVerified,
+		"phone_verified": m.PhoneVerified,
+
+		"organization_id": m.OrganizationID,
+		"version":         m.Version,
 	}
 }
 
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Cursor -> position of a record in keyset pagination ordered by created_at,id
+// the fingerprint of the request is signed along, so a cursor can not be replayed against another list or filter
+type Cursor struct {
+	CreatedAt   time.Time `json:"t"`
+	ID          int64     `json:"i"`
+	Backward    bool      `json:"b,omitempty"`
+	Fingerprint string    `json:"f"`
+}
+
+// PageInfo -> pagination details returned along with records
+type PageInfo struct {
+	Count *int64
+	Next  *Cursor
+	Prev  *Cursor
+}
+
+// EncodeCursors -> signed next and previous cursors of the page
+func (p PageInfo) EncodeCursors(secret string) (next string, prev string) {
+	if p.Next != nil {
+		next = EncodeCursor(secret, *p.Next)
+	}
+	if p.Prev != nil {
+		prev = EncodeCursor(secret, *p.Prev)
+	}
+	return next, prev
+}
+
+// EncodeCursor -> opaque signed representation of the cursor
+func EncodeCursor(secret string, cursor Cursor) string {
+	payload, _ := json.Marshal(cursor)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + signCursor(secret, encoded)
+}
+
+// DecodeCursor -> verifies the signature and decodes the cursor
+func DecodeCursor(secret string, token string) (*Cursor, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, errors.New("malformed cursor")
+	}
+	if !hmac.Equal([]byte(parts[1]), []byte(signCursor(secret, parts[0]))) {
+		return nil, errors.New("invalid cursor signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	cursor := Cursor{}
+	if err := json.Unmarshal(payload, &cursor); err != nil {
+		return nil, err
+	}
+	return &cursor, nil
+}
+
+func signCursor(secret string, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
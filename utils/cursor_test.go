@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	cursor := Cursor{
+		CreatedAt:   time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		ID:          42,
+		Backward:    true,
+		Fingerprint: "fingerprint",
+	}
+	token := EncodeCursor("secret", cursor)
+	payload := strings.Split(token, ".")[0]
+
+	tests := []struct {
+		name    string
+		secret  string
+		token   string
+		wantErr bool
+	}{
+		{name: "valid cursor", secret: "secret", token: token},
+		{name: "other secret", secret: "other", token: token, wantErr: true},
+		{name: "tampered payload", secret: "secret", token: base64.RawURLEncoding.EncodeToString([]byte(`{"i":43}`)) + "." + strings.Split(token, ".")[1], wantErr: true},
+		{name: "tampered signature", secret: "secret", token: payload + ".signature", wantErr: true},
+		{name: "missing signature", secret: "secret", token: payload, wantErr: true},
+		{name: "too many parts", secret: "secret", token: token + ".extra", wantErr: true},
+		{name: "signed payload that is not base64", secret: "secret", token: "!!." + signCursor("secret", "!!"), wantErr: true},
+		{name: "signed payload that is not json", secret: "secret", token: "bm90IGpzb24." + signCursor("secret", "bm90IGpzb24"), wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := DecodeCursor(test.secret, test.token)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(*got, cursor) {
+				t.Errorf("DecodeCursor() = %+v, want %+v", *got, cursor)
+			}
+		})
+	}
+}
+
+func TestPageInfoEncodeCursors(t *testing.T) {
+	next := &Cursor{ID: 2, Fingerprint: "f"}
+	prev := &Cursor{ID: 1, Backward: true, Fingerprint: "f"}
+	tests := []struct {
+		name     string
+		page     PageInfo
+		wantNext bool
+		wantPrev bool
+	}{
+		{name: "no cursors", page: PageInfo{}},
+		{name: "first page", page: PageInfo{Next: next}, wantNext: true},
+		{name: "last page", page: PageInfo{Prev: prev}, wantPrev: true},
+		{name: "middle page", page: PageInfo{Next: next, Prev: prev}, wantNext: true, wantPrev: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gotNext, gotPrev := test.page.EncodeCursors("secret")
+			if (gotNext != "") != test.wantNext || (gotPrev != "") != test.wantPrev {
+				t.Fatalf("EncodeCursors() = %q, %q", gotNext, gotPrev)
+			}
+			if test.wantNext && gotNext != EncodeCursor("secret", *next) {
+				t.Errorf("next = %q, want the encoded next cursor", gotNext)
+			}
+			if test.wantPrev && gotPrev != EncodeCursor("secret", *prev) {
+				t.Errorf("prev = %q, want the encoded previous cursor", gotPrev)
+			}
+		})
+	}
+}
@@ -18,3 +18,49 @@ func ConvertRFCStringToDate(date string) time.Time {
 	fmt.Println(err)
 	return time
 }
+
+// LoadTimezone -> location of an IANA timezone name, empty name is UTC
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(name)
+}
+
+// ParseDueDate -> parses a RFC3339 timestamp, a local date time or a date in the location
+// a plain date is due at the end of that day
+func ParseDueDate(value string, loc *time.Location) (time.Time, error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date.UTC(), nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"} {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return date.UTC(), nil
+		}
+	}
+	date, err := time.ParseInLocation("2006-01-02", value, loc)
+	if err != nil {
+		return date, err
+	}
+	return EndOfDay(date, loc).UTC(), nil
+}
+
+// StartOfDay -> first instant of the day of t in the location
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// EndOfDay -> last second of the day of t in the location
+func EndOfDay(t time.Time, loc *time.Location) time.Time {
+	return StartOfDay(t, loc).AddDate(0, 0, 1).Add(-time.Second)
+}
+
+// FormatInTimezone -> RFC3339 time in the timezone, UTC when the timezone is unknown
+func FormatInTimezone(t time.Time, timezone string) string {
+	loc, err := LoadTimezone(timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format(time.RFC3339)
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ETag -> strong entity tag of a version of a resource
+func ETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ParseETag -> version of a strong entity tag, false for weak or malformed tags
+func ParseETag(tag string) (int64, bool) {
+	tag = strings.TrimSpace(tag)
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+	return version, err == nil
+}
+
+// ETagMatch -> whether the If-None-Match header lists the entity tag, weak tags compare equal to strong ones
+func ETagMatch(header string, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
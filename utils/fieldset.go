@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Fieldset -> sparse fieldset `?fields=id,username` and relations to expand `?include=memberships`
+type Fieldset struct {
+	Fields  []string
+	Include []string
+}
+
+// BuildFieldset -> builds the fieldset from the query
+func BuildFieldset(c *gin.Context) Fieldset {
+	return Fieldset{
+		Fields:  splitList(c.Query("fields")),
+		Include: splitList(c.Query("include")),
+	}
+}
+
+// IsEmpty -> whether the client asked for the default representation
+func (f Fieldset) IsEmpty() bool {
+	return len(f.Fields) == 0 && len(f.Include) == 0
+}
+
+// splitList -> comma separated values without blanks
+func splitList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+// searchOperators -> boolean mode operators stripped from search terms
+const searchOperators = `+-~<>()"*@`
+
+// SearchTerms -> splits the keyword into terms to match and terms to exclude (`-term`)
+func SearchTerms(keyword string) (include []string, exclude []string) {
+	for _, term := range strings.Fields(keyword) {
+		excluded := strings.HasPrefix(term, "-")
+		term = strings.Trim(term, searchOperators)
+		if term == "" {
+			continue
+		}
+		if excluded {
+			exclude = append(exclude, term)
+		} else {
+			include = append(include, term)
+		}
+	}
+	return include, exclude
+}
+
+// Highlight -> html escapes the text and wraps case-insensitive matches of the terms in <mark>
+// reports false when none of the terms matched
+func Highlight(text string, terms []string) (string, bool) {
+	if text == "" || len(terms) == 0 {
+		return "", false
+	}
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	pattern := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	matches := pattern.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return "", false
+	}
+
+	var builder strings.Builder
+	last := 0
+	for _, match := range matches {
+		builder.WriteString(html.EscapeString(text[last:match[0]]))
+		builder.WriteString("<mark>")
+		builder.WriteString(html.EscapeString(text[match[0]:match[1]]))
+		builder.WriteString("</mark>")
+		last = match[1]
+	}
+	builder.WriteString(html.EscapeString(text[last:]))
+	return builder.String(), true
+}
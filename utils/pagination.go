@@ -1,11 +1,21 @@
 package utils
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+// MaxPageSize -> largest page size a request can ask for, larger sizes are capped
+const MaxPageSize = 100
+
+// cursorIndependentParams -> query parameters that can change between the pages of a cursor
+var cursorIndependentParams = []string{"cursor", "page", "pageSize", "skipCount", "fields", "include"}
+
 //Pagination -> struct for Pagination
 type Pagination struct {
 	Page       int
@@ -14,6 +24,15 @@ type Pagination struct {
 	Offset     int
 	All        bool
 	Keyword    string
+	SearchMode string
+	SkipCount  bool
+	CursorMode bool
+	CursorRaw  string
+	Cursor     *Cursor
+	Query      QuerySpec
+
+	// Fingerprint -> route and filters of the request, cursors are only valid for the same fingerprint
+	Fingerprint string
 }
 
 //BuildPagination -> builds the pagination
@@ -22,28 +41,66 @@ func BuildPagination(c *gin.Context) Pagination {
 	pageSizeStr := c.Query("pageSize")
 	sort := c.Query("sort")
 	keyword := c.Query("keyword")
-
-	var all bool
-	if pageSizeStr == "Infinity" {
-		all = true
-	}
+	skipCount := c.Query("skipCount") == "true"
+	cursor, cursorMode := c.GetQuery("cursor")
 
 	page, err := strconv.Atoi(pageStr)
 	if err != nil || page <= 0 {
 		page = 1
 	}
 
+	// Infinity asks for the largest page, requests can not skip the limit
 	pageSize, err := strconv.Atoi(pageSizeStr)
 	if err != nil || pageSize <= 0 {
 		pageSize = 10
 	}
+	if pageSize > MaxPageSize || pageSizeStr == "Infinity" {
+		pageSize = MaxPageSize
+	}
 
 	return Pagination{
 		Page:       page,
 		Sort:       sort,
 		PageSize:   pageSize,
 		Offset:     (page - 1) * pageSize,
-		All:        all,
 		Keyword:    keyword,
+		SearchMode: c.Query("searchMode"),
+		SkipCount:  skipCount,
+		CursorMode: cursorMode,
+		CursorRaw:  cursor,
+		Query:      ParseQuerySpec(c.Request.URL.Query()),
+
+		Fingerprint: queryFingerprint(c.FullPath(), c.Request.URL.Query()),
 	}
-}
\ No newline at end of file
+}
+
+// queryFingerprint -> digest of the route and the query parameters that select and order the records
+func queryFingerprint(route string, query url.Values) string {
+	filters := url.Values{}
+	for name, values := range query {
+		filters[name] = values
+	}
+	for _, name := range cursorIndependentParams {
+		filters.Del(name)
+	}
+	// Encode sorts by name, so the order of the parameters does not matter
+	digest := sha256.Sum256([]byte(route + "?" + filters.Encode()))
+	return base64.RawURLEncoding.EncodeToString(digest[:16])
+}
+
+//DecodeCursor -> verifies the cursor sent with the request
+func (p *Pagination) DecodeCursor(secret string) error {
+	if !p.CursorMode || p.CursorRaw == "" {
+		return nil
+	}
+	cursor, err := DecodeCursor(secret, p.CursorRaw)
+	if err != nil {
+		return err
+	}
+	if cursor.Fingerprint != p.Fingerprint {
+		return errors.New("cursor belongs to another list or filter")
+	}
+	p.Cursor = cursor
+	p.Offset = 0
+	return nil
+}
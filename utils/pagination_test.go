@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// testPagination -> pagination of a request to the route
+func testPagination(route string, target string) Pagination {
+	var pagination Pagination
+	engine := gin.New()
+	engine.GET(route, func(c *gin.Context) {
+		pagination = BuildPagination(c)
+	})
+	engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil))
+	return pagination
+}
+
+func TestBuildPagination(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		wantPage     int
+		wantPageSize int
+		wantOffset   int
+		wantAll      bool
+		wantCursor   bool
+	}{
+		{name: "defaults", target: "/users", wantPage: 1, wantPageSize: 10},
+		{name: "page and size", target: "/users?page=3&pageSize=20", wantPage: 3, wantPageSize: 20, wantOffset: 40},
+		{name: "invalid values", target: "/users?page=-1&pageSize=abc", wantPage: 1, wantPageSize: 10},
+		{name: "size is capped", target: "/users?page=2&pageSize=5000", wantPage: 2, wantPageSize: MaxPageSize, wantOffset: MaxPageSize},
+		{name: "infinity is capped", target: "/users?page=2&pageSize=Infinity", wantPage: 2, wantPageSize: MaxPageSize, wantOffset: MaxPageSize},
+		{name: "infinity is capped in cursor mode", target: "/users?pageSize=Infinity&cursor=", wantPage: 1, wantPageSize: MaxPageSize, wantCursor: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := testPagination("/users", test.target)
+			if got.Page != test.wantPage || got.PageSize != test.wantPageSize || got.Offset != test.wantOffset {
+				t.Errorf("page, size, offset = %d, %d, %d, want %d, %d, %d", got.Page, got.PageSize, got.Offset, test.wantPage, test.wantPageSize, test.wantOffset)
+			}
+			if got.All != test.wantAll || got.CursorMode != test.wantCursor {
+				t.Errorf("all, cursor mode = %v, %v, want %v, %v", got.All, got.CursorMode, test.wantAll, test.wantCursor)
+			}
+		})
+	}
+}
+
+func TestQueryFingerprint(t *testing.T) {
+	base := queryFingerprint("/users", url.Values{"keyword": {"jane"}, "sort": {"-created_at"}})
+	tests := []struct {
+		name  string
+		route string
+		query url.Values
+		same  bool
+	}{
+		{name: "same query", route: "/users", query: url.Values{"keyword": {"jane"}, "sort": {"-created_at"}}, same: true},
+		{name: "page size, fields and cursor do not matter", route: "/users", query: url.Values{"keyword": {"jane"}, "sort": {"-created_at"}, "pageSize": {"50"}, "page": {"2"}, "cursor": {"abc"}, "skipCount": {"true"}, "fields": {"id"}, "include": {"todos"}}, same: true},
+		{name: "other route", route: "/todo", query: url.Values{"keyword": {"jane"}, "sort": {"-created_at"}}},
+		{name: "other filter", route: "/users", query: url.Values{"keyword": {"john"}, "sort": {"-created_at"}}},
+		{name: "other sort", route: "/users", query: url.Values{"keyword": {"jane"}, "sort": {"created_at"}}},
+		{name: "extra filter", route: "/users", query: url.Values{"keyword": {"jane"}, "sort": {"-created_at"}, "filter[role]": {"admin"}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := queryFingerprint(test.route, test.query); (got == base) != test.same {
+				t.Errorf("fingerprint equal = %v, want %v", got == base, test.same)
+			}
+		})
+	}
+}
+
+func TestPaginationDecodeCursor(t *testing.T) {
+	fingerprint := testPagination("/users", "/users?keyword=jane").Fingerprint
+	cursor := EncodeCursor("secret", Cursor{ID: 5, Fingerprint: fingerprint})
+
+	tests := []struct {
+		name    string
+		route   string
+		target  string
+		wantErr bool
+		wantID  int64
+	}{
+		{name: "no cursor", route: "/users", target: "/users?keyword=jane"},
+		{name: "empty cursor starts at the first page", route: "/users", target: "/users?keyword=jane&cursor="},
+		{name: "cursor of the same list", route: "/users", target: "/users?keyword=jane&pageSize=20&cursor=" + cursor, wantID: 5},
+		{name: "cursor of another filter", route: "/users", target: "/users?keyword=john&cursor=" + cursor, wantErr: true},
+		{name: "cursor of another list", route: "/todo", target: "/todo?keyword=jane&cursor=" + cursor, wantErr: true},
+		{name: "invalid cursor", route: "/users", target: "/users?keyword=jane&cursor=abc", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			pagination := testPagination(test.route, test.target)
+			pagination.Offset = 30
+			err := pagination.DecodeCursor("secret")
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if test.wantID == 0 {
+				if pagination.Cursor != nil {
+					t.Errorf("cursor = %+v, want none", pagination.Cursor)
+				}
+				return
+			}
+			if pagination.Cursor == nil || pagination.Cursor.ID != test.wantID {
+				t.Fatalf("cursor = %+v, want id %d", pagination.Cursor, test.wantID)
+			}
+			if pagination.Offset != 0 {
+				t.Errorf("offset = %d, want 0 with a cursor", pagination.Offset)
+			}
+		})
+	}
+}
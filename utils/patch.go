@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"fmt"
+	"mime"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	// Content types of PATCH requests
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// PatchContentTypes -> accepted content types of PATCH requests, plain JSON is read as a merge patch
+var PatchContentTypes = []string{MergePatchContentType, JSONPatchContentType, "application/json"}
+
+// IsPatchContentType -> whether the Content-Type header is one of the accepted patch formats
+func IsPatchContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && StringInList(mediaType, PatchContentTypes)
+}
+
+// ApplyPatch -> applies a RFC 7396 merge patch or a RFC 6902 JSON Patch to the document
+func ApplyPatch(contentType string, document []byte, patch []byte) ([]byte, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+	switch mediaType {
+	case JSONPatchContentType:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, err
+		}
+		return operations.Apply(document)
+	case MergePatchContentType, "application/json":
+		return jsonpatch.MergePatch(document, patch)
+	}
+	return nil, fmt.Errorf("unsupported patch content type %s", mediaType)
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestIsPatchContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{contentType: "application/merge-patch+json", want: true},
+		{contentType: "application/json-patch+json", want: true},
+		{contentType: "application/json; charset=utf-8", want: true},
+		{contentType: "text/plain", want: false},
+		{contentType: "", want: false},
+		{contentType: "application/json;;", want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.contentType, func(t *testing.T) {
+			if got := IsPatchContentType(test.contentType); got != test.want {
+				t.Errorf("IsPatchContentType(%q) = %v, want %v", test.contentType, got, test.want)
+			}
+		})
+	}
+}
+
+func TestApplyPatch(t *testing.T) {
+	document := `{"task":"milk","priority":1,"tags":["home"]}`
+	tests := []struct {
+		name        string
+		contentType string
+		patch       string
+		want        string
+		wantErr     bool
+	}{
+		{name: "merge patch", contentType: MergePatchContentType, patch: `{"task":"bread"}`, want: `{"task":"bread","priority":1,"tags":["home"]}`},
+		{name: "merge patch null removes", contentType: MergePatchContentType, patch: `{"priority":null}`, want: `{"task":"milk","tags":["home"]}`},
+		{name: "merge patch replaces arrays", contentType: MergePatchContentType, patch: `{"tags":["work"]}`, want: `{"task":"milk","priority":1,"tags":["work"]}`},
+		{name: "plain json is a merge patch", contentType: "application/json; charset=utf-8", patch: `{"task":"bread"}`, want: `{"task":"bread","priority":1,"tags":["home"]}`},
+		{name: "json patch", contentType: JSONPatchContentType, patch: `[{"op":"add","path":"/tags/-","value":"work"},{"op":"replace","path":"/priority","value":3}]`, want: `{"task":"milk","priority":3,"tags":["home","work"]}`},
+		{name: "json patch remove", contentType: JSONPatchContentType, patch: `[{"op":"remove","path":"/priority"}]`, want: `{"task":"milk","tags":["home"]}`},
+		{name: "json patch failed test", contentType: JSONPatchContentType, patch: `[{"op":"test","path":"/task","value":"bread"}]`, wantErr: true},
+		{name: "json patch missing path", contentType: JSONPatchContentType, patch: `[{"op":"replace","path":"/missing","value":1}]`, wantErr: true},
+		{name: "json patch that is not a list", contentType: JSONPatchContentType, patch: `{"task":"bread"}`, wantErr: true},
+		{name: "malformed merge patch", contentType: MergePatchContentType, patch: `{"task":`, wantErr: true},
+		{name: "unsupported content type", contentType: "text/plain", patch: `{}`, wantErr: true},
+		{name: "invalid content type", contentType: "", patch: `{}`, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ApplyPatch(test.contentType, []byte(document), []byte(test.patch))
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var gotValue, wantValue interface{}
+			if err := json.Unmarshal(got, &gotValue); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(test.want), &wantValue); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("ApplyPatch() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// filterKeyPattern -> matches filter[field] and filter[field][operator]
+var filterKeyPattern = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+// SortField -> one entry of the sort query, `-` prefix means descending
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// FilterField -> one filter[field][operator]=value entry of the query
+type FilterField struct {
+	Key      string
+	Field    string
+	Operator string
+	Value    string
+}
+
+// QuerySpec -> sorting and filtering requested by the client
+type QuerySpec struct {
+	Sort    []SortField
+	Filters []FilterField
+}
+
+// ParseQuerySpec -> parses `sort=-created_at,task` and `filter[...]` query params
+// fields are not validated here, see repository.QueryFields
+func ParseQuerySpec(values url.Values) QuerySpec {
+	spec := QuerySpec{}
+
+	for _, field := range strings.Split(values.Get("sort"), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		sortField := SortField{Field: field}
+		if strings.HasPrefix(field, "-") {
+			sortField = SortField{Field: field[1:], Desc: true}
+		} else if strings.HasPrefix(field, "+") {
+			sortField = SortField{Field: field[1:]}
+		}
+		spec.Sort = append(spec.Sort, sortField)
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if strings.HasPrefix(key, "filter[") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		filter := FilterField{Key: key, Value: values.Get(key)}
+		if match := filterKeyPattern.FindStringSubmatch(key); match != nil {
+			filter.Field, filter.Operator = match[1], match[2]
+			if filter.Operator == "" {
+				filter.Operator = "eq"
+			}
+		}
+		spec.Filters = append(spec.Filters, filter)
+	}
+	return spec
+}
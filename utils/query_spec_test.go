@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestParseQuerySpec(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  QuerySpec
+	}{
+		{name: "empty query", query: "", want: QuerySpec{}},
+		{
+			name:  "sort fields with directions",
+			query: "sort=-created_at,+task, priority ,,",
+			want: QuerySpec{Sort: []SortField{
+				{Field: "created_at", Desc: true},
+				{Field: "task"},
+				{Field: "priority"},
+			}},
+		},
+		{
+			name:  "filters default to eq and are sorted by key",
+			query: "filter[task][like]=milk&filter[is_completed]=true&keyword=x",
+			want: QuerySpec{Filters: []FilterField{
+				{Key: "filter[is_completed]", Field: "is_completed", Operator: "eq", Value: "true"},
+				{Key: "filter[task][like]", Field: "task", Operator: "like", Value: "milk"},
+			}},
+		},
+		{
+			name:  "malformed filter keys keep no field",
+			query: "filter[task=1&filter[a][b][c]=2",
+			want: QuerySpec{Filters: []FilterField{
+				{Key: "filter[a][b][c]", Value: "2"},
+				{Key: "filter[task", Value: "1"},
+			}},
+		},
+		{
+			name:  "first value of a repeated filter",
+			query: "filter[id][in]=1,2&filter[id][in]=3",
+			want: QuerySpec{Filters: []FilterField{
+				{Key: "filter[id][in]", Field: "id", Operator: "in", Value: "1,2"},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			values, err := url.ParseQuery(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := ParseQuerySpec(values); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseQuerySpec() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"encoding/hex"
 	"io"
 	"strconv"
 	"time"
@@ -22,6 +23,16 @@ func GenerateRandomCode(codeLength int) string {
 	return string(b)
 }
 
+// GenerateSecureToken generates url safe random hex token from the given number of bytes
+func GenerateSecureToken(length int) string {
+	b := make([]byte, length)
+	n, err := io.ReadAtLeast(rand.Reader, b, length)
+	if n != length {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
 //GenerateRandomFileName genrates the fileName with unique time
 func GenerateRandomFileName() string {
 	time := time.Now().UnixNano()
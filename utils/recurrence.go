@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// NormalizeRecurrence -> upper cased RRULE without the `RRULE:` prefix
+func NormalizeRecurrence(rule string) string {
+	rule = strings.ToUpper(strings.TrimSpace(rule))
+	return strings.TrimPrefix(rule, "RRULE:")
+}
+
+// ParseRecurrence -> RFC 5545 RRULE starting at dtstart
+// occurrences are computed in the location so they keep the wall clock time of dtstart across DST changes
+func ParseRecurrence(rule string, dtstart time.Time, loc *time.Location) (*rrule.RRule, error) {
+	rule = NormalizeRecurrence(rule)
+	if strings.ContainsAny(rule, "\r\n") {
+		return nil, errors.New("the rule must be a single RRULE line, DTSTART is taken from the due date")
+	}
+	option, err := rrule.StrToROptionInLocation(rule, loc)
+	if err != nil {
+		return nil, err
+	}
+	if option.Freq == rrule.SECONDLY || option.Freq == rrule.MINUTELY {
+		return nil, fmt.Errorf("frequency %s is not supported", option.Freq)
+	}
+	option.Dtstart = dtstart.In(loc).Truncate(time.Second)
+	return rrule.NewRRule(*option)
+}
+
+// NextOccurrence -> first occurrence strictly after the given time, false once the rule has ended
+func NextOccurrence(rule string, dtstart time.Time, after time.Time, loc *time.Location) (time.Time, bool, error) {
+	recurrence, err := ParseRecurrence(rule, dtstart, loc)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	next := recurrence.After(after, false)
+	return next.UTC(), !next.IsZero(), nil
+}
+
+// Occurrences -> up to limit occurrences from the given time on, the time itself included
+func Occurrences(rule string, dtstart time.Time, from time.Time, loc *time.Location, limit int) ([]time.Time, error) {
+	recurrence, err := ParseRecurrence(rule, dtstart, loc)
+	if err != nil {
+		return nil, err
+	}
+	occurrences := []time.Time{}
+	next := recurrence.After(from, true)
+	for !next.IsZero() && len(occurrences) < limit {
+		occurrences = append(occurrences, next.UTC())
+		next = recurrence.After(next, false)
+	}
+	return occurrences, nil
+}
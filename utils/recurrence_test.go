@@ -0,0 +1,163 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func mustLoadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone data for %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestNormalizeRecurrence(t *testing.T) {
+	tests := []struct {
+		rule string
+		want string
+	}{
+		{rule: "FREQ=DAILY", want: "FREQ=DAILY"},
+		{rule: " freq=weekly;byday=mo ", want: "FREQ=WEEKLY;BYDAY=MO"},
+		{rule: "rrule:FREQ=MONTHLY", want: "FREQ=MONTHLY"},
+		{rule: "", want: ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.rule, func(t *testing.T) {
+			if got := NormalizeRecurrence(test.rule); got != test.want {
+				t.Errorf("NormalizeRecurrence(%q) = %q, want %q", test.rule, got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	dtstart := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		rule    string
+		wantErr bool
+	}{
+		{name: "daily", rule: "FREQ=DAILY"},
+		{name: "prefixed lower case", rule: "rrule:freq=weekly;byday=mo,we"},
+		{name: "count", rule: "FREQ=MONTHLY;COUNT=3"},
+		{name: "multiple lines", rule: "DTSTART:20240101T090000Z\nRRULE:FREQ=DAILY", wantErr: true},
+		{name: "minutely", rule: "FREQ=MINUTELY", wantErr: true},
+		{name: "secondly", rule: "FREQ=SECONDLY;INTERVAL=30", wantErr: true},
+		{name: "unknown frequency", rule: "FREQ=SOMETIMES", wantErr: true},
+		{name: "missing frequency", rule: "BYDAY=MO", wantErr: true},
+		{name: "invalid part", rule: "FREQ=DAILY;INTERVAL=x", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := ParseRecurrence(test.rule, dtstart, time.UTC)
+			if (err != nil) != test.wantErr {
+				t.Errorf("ParseRecurrence(%q) error = %v, want error %v", test.rule, err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	berlin := mustLoadLocation(t, "Europe/Berlin")
+	tests := []struct {
+		name     string
+		rule     string
+		dtstart  time.Time
+		after    time.Time
+		loc      *time.Location
+		want     time.Time
+		wantMore bool
+	}{
+		{
+			name:     "next day",
+			rule:     "FREQ=DAILY",
+			dtstart:  time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+			after:    time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			want:     time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+			wantMore: true,
+		},
+		{
+			name:     "weekly on the given days",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,FR",
+			dtstart:  time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+			after:    time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+			loc:      time.UTC,
+			want:     time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC),
+			wantMore: true,
+		},
+		{
+			name:     "wall clock time kept across DST",
+			rule:     "FREQ=DAILY",
+			dtstart:  time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC), // 09:00 CET
+			after:    time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC),
+			loc:      berlin,
+			want:     time.Date(2024, 3, 31, 7, 0, 0, 0, time.UTC), // 09:00 CEST
+			wantMore: true,
+		},
+		{
+			name:    "rule ended",
+			rule:    "FREQ=DAILY;COUNT=2",
+			dtstart: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+			after:   time.Date(2024, 1, 2, 9, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, more, err := NextOccurrence(test.rule, test.dtstart, test.after, test.loc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if more != test.wantMore || !got.Equal(test.want) {
+				t.Errorf("NextOccurrence() = %v, %v, want %v, %v", got, more, test.want, test.wantMore)
+			}
+			if more && got.Location() != time.UTC {
+				t.Errorf("occurrence in %v, want UTC", got.Location())
+			}
+		})
+	}
+
+	if _, _, err := NextOccurrence("FREQ=MINUTELY", time.Now(), time.Now(), time.UTC); err == nil {
+		t.Error("expected an error for an unsupported rule")
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	dtstart := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 9, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name  string
+		rule  string
+		from  time.Time
+		limit int
+		want  []time.Time
+	}{
+		{name: "limited", rule: "FREQ=DAILY", from: dtstart, limit: 3, want: []time.Time{day(1), day(2), day(3)}},
+		{name: "from time included", rule: "FREQ=DAILY", from: day(3), limit: 2, want: []time.Time{day(3), day(4)}},
+		{name: "from between occurrences", rule: "FREQ=DAILY;INTERVAL=2", from: day(2), limit: 2, want: []time.Time{day(3), day(5)}},
+		{name: "count ends early", rule: "FREQ=DAILY;COUNT=2", from: dtstart, limit: 5, want: []time.Time{day(1), day(2)}},
+		{name: "until ends early", rule: "FREQ=DAILY;UNTIL=20240102T090000Z", from: dtstart, limit: 5, want: []time.Time{day(1), day(2)}},
+		{name: "after the end", rule: "FREQ=DAILY;COUNT=2", from: day(5), limit: 5, want: []time.Time{}},
+		{name: "zero limit", rule: "FREQ=DAILY", from: dtstart, limit: 0, want: []time.Time{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Occurrences(test.rule, dtstart, test.from, time.UTC, test.limit)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("Occurrences() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RequestURL -> absolute request url without query and fragment
+// X-Forwarded-Proto is only honoured when the request comes from one of the TrustedProxies of the router
+func RequestURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if _, trusted := c.RemoteIP(); trusted {
+		if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+	}
+	return scheme + "://" + c.Request.Host + c.Request.URL.Path
+}
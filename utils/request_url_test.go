@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestURL(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		tls            bool
+		forwardedProto string
+		want           string
+	}{
+		{name: "plain http", target: "http://api.example.com/user?page=2", want: "http://api.example.com/user"},
+		{name: "tls", target: "https://api.example.com/user", tls: true, want: "https://api.example.com/user"},
+		{name: "forwarded proto of an untrusted client", target: "http://api.example.com/user", forwardedProto: "https", want: "http://api.example.com/user"},
+		{name: "forwarded proto of an untrusted client over tls", target: "https://api.example.com/user", tls: true, forwardedProto: "http", want: "https://api.example.com/user"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest("GET", test.target, nil)
+			if !test.tls {
+				c.Request.TLS = nil
+			} else if c.Request.TLS == nil {
+				c.Request.TLS = &tls.ConnectionState{}
+			}
+			if test.forwardedProto != "" {
+				c.Request.Header.Set("X-Forwarded-Proto", test.forwardedProto)
+			}
+
+			if got := RequestURL(c); got != test.want {
+				t.Errorf("RequestURL() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// SlugMaxLength -> longest slug generated from a title
+const SlugMaxLength = 200
+
+var (
+	htmlTagPattern     = regexp.MustCompile(`<[^>]*>`)
+	whitespacePattern  = regexp.MustCompile(`\s+`)
+	punctuationPattern = regexp.MustCompile(`\s+([,.;:!?])`)
+)
+
+// Slugify -> url safe slug of the text, accents are dropped ("Crème Brûlée!" -> "creme-brulee")
+func Slugify(text string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range norm.NFKD.String(strings.ToLower(text)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			builder.WriteRune(r)
+			dash = false
+		case builder.Len() > 0 && !dash:
+			builder.WriteRune('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimRight(builder.String(), "-")
+	if len(slug) > SlugMaxLength {
+		slug = strings.TrimRight(slug[:SlugMaxLength], "-")
+	}
+	return slug
+}
+
+// Excerpt -> plain text of the (html) content cut at a word boundary to at most length characters
+func Excerpt(content string, length int) string {
+	text := htmlTagPattern.ReplaceAllString(content, " ")
+	text = strings.TrimSpace(whitespacePattern.ReplaceAllString(text, " "))
+	text = punctuationPattern.ReplaceAllString(text, "$1")
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	cut := string(runes[:length])
+	if i := strings.LastIndex(cut, " "); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
@@ -0,0 +1,123 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	// Spreadsheet formats accepted for import and export
+	SpreadsheetCSV  = "csv"
+	SpreadsheetXLSX = "xlsx"
+)
+
+// SpreadsheetFormats -> accepted spreadsheet formats
+var SpreadsheetFormats = []string{SpreadsheetCSV, SpreadsheetXLSX}
+
+// SpreadsheetContentTypes -> content type of each spreadsheet format
+var SpreadsheetContentTypes = map[string]string{
+	SpreadsheetCSV:  "text/csv",
+	SpreadsheetXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// SpreadsheetFormat -> format of the file from its extension, empty when not a spreadsheet
+func SpreadsheetFormat(filename string) string {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	if StringInList(format, SpreadsheetFormats) {
+		return format
+	}
+	return ""
+}
+
+// ReadSpreadsheet -> rows of a csv file or of the first sheet of a xlsx file
+func ReadSpreadsheet(r io.Reader, format string) ([][]string, error) {
+	switch format {
+	case SpreadsheetCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		return reader.ReadAll()
+	case SpreadsheetXLSX:
+		file, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		return file.GetRows(file.GetSheetName(0))
+	}
+	return nil, fmt.Errorf("unsupported spreadsheet format %s", format)
+}
+
+// SpreadsheetWriter -> writes rows of a csv or xlsx file, Close has to be called to finish the file
+type SpreadsheetWriter interface {
+	Write(row []string) error
+	Close() error
+}
+
+// NewSpreadsheetWriter -> writer of the format, csv rows are streamed while xlsx is written out on Close
+func NewSpreadsheetWriter(w io.Writer, format string) (SpreadsheetWriter, error) {
+	switch format {
+	case SpreadsheetCSV:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case SpreadsheetXLSX:
+		file := excelize.NewFile()
+		stream, err := file.NewStreamWriter(file.GetSheetName(0))
+		if err != nil {
+			return nil, err
+		}
+		return &xlsxWriter{out: w, file: file, stream: stream}, nil
+	}
+	return nil, fmt.Errorf("unsupported spreadsheet format %s", format)
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvWriter) Write(row []string) error {
+	if err := w.writer.Write(row); err != nil {
+		return err
+	}
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+type xlsxWriter struct {
+	out    io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	rows   int
+}
+
+func (w *xlsxWriter) Write(row []string) error {
+	values := make([]interface{}, len(row))
+	for i, value := range row {
+		values[i] = value
+	}
+	w.rows++
+	cell, err := excelize.CoordinatesToCellName(1, w.rows)
+	if err != nil {
+		return err
+	}
+	return w.stream.SetRow(cell, values)
+}
+
+func (w *xlsxWriter) Close() error {
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+	if err := w.file.Write(w.out); err != nil {
+		return err
+	}
+	return w.file.Close()
+}
@@ -0,0 +1,11 @@
+package utils
+
+// StringInList -> checks if the given string is in the list
+func StringInList(value string, list []string) bool {
+	for _, i := range list {
+		if i == value {
+			return true
+		}
+	}
+	return false
+}